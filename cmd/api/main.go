@@ -1,50 +1,99 @@
-package main
-
-import (
-	"database/sql"
-	"log"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/joho/godotenv"
-	"github.com/sirupsen/logrus"
-
-	"internal/api/routes"
-	"internal/config"
-)
-
-func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
-	}
-
-	// Load configuration
-	cfg := config.Load()
-
-	// Setup logger
-	logrus.SetFormatter(&logrus.JSONFormatter{})
-	logrus.SetLevel(logrus.InfoLevel)
-
-	// Connect to database
-	db, err := sql.Open("pgx", cfg.DatabaseURL())
-	if err != nil {
-		logrus.Fatal("Failed to connect to database: ", err)
-	}
-	defer db.Close()
-
-	// Test database connection
-	if err := db.Ping(); err != nil {
-		logrus.Fatal("Failed to ping database: ", err)
-	}
-
-	logrus.Info("Successfully connected to database")
-
-	// Setup routes
-	router := routes.SetupRoutes(db)
-
-	// Start server
-	logrus.Infof("Starting server on port %s", cfg.ServerPort)
-	if err := router.Run(":" + cfg.ServerPort); err != nil {
-		logrus.Fatal("Failed to start server: ", err)
-	}
-}
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+
+	"internal/analytics"
+	"internal/api/routes"
+	"internal/config"
+	"internal/database"
+	"internal/subscription"
+	"internal/tracing"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logrus.Fatal("Failed to load configuration: ", err)
+	}
+
+	// Setup logger
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	level, err := logrus.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	logrus.SetLevel(level)
+	logrus.Infof("Loaded configuration: %s", cfg)
+
+	// Setup tracing before the database so SQL spans use the real provider
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env)
+	if err != nil {
+		logrus.Fatal("Failed to setup tracing: ", err)
+	}
+
+	// Connect to database
+	db, err := database.Open(context.Background(), cfg.Database)
+	if err != nil {
+		logrus.Fatal("Failed to connect to database: ", err)
+	}
+	defer db.Close()
+
+	logrus.Infof("Successfully connected to database (driver: %s)", db.Driver())
+
+	// Refresh the instructor analytics views in the background
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.Analytics.RefreshInterval > 0 {
+		go analytics.Run(jobsCtx, db.DB, cfg.Analytics.RefreshInterval)
+	}
+
+	// Renew, lapse and expire subscriptions in the background
+	if cfg.Subscriptions.SchedulerInterval > 0 {
+		go subscription.Run(jobsCtx, db.DB, cfg.Subscriptions.SchedulerInterval,
+			cfg.Subscriptions.RenewalLead, cfg.Subscriptions.GracePeriod)
+	}
+
+	// Setup routes
+	router := routes.SetupRoutes(db, cfg)
+
+	srv := &http.Server{
+		Addr:         cfg.Server.Addr(),
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+
+	// Start server
+	go func() {
+		logrus.Infof("Starting server on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Fatal("Failed to start server: ", err)
+		}
+	}()
+
+	// Wait for interrupt and drain in-flight requests
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logrus.Info("Shutting down server")
+	stopJobs()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logrus.Error("Server forced to shutdown: ", err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		logrus.Error("Failed to flush traces: ", err)
+	}
+}
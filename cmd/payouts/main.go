@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"internal/config"
+	"internal/ledger"
+)
+
+// Chạy định kỳ (cron) để tạo đợt chi trả; cùng logic với POST /api/admin/payout-batches
+func main() {
+	// Tải cấu hình (defaults, configs/, biến môi trường, *_FILE)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("❌ Không thể tải cấu hình:", err)
+	}
+
+	db, err := sql.Open("pgx", cfg.Database.DSN())
+	if err != nil {
+		log.Fatal("❌ Không thể kết nối đến database:", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatal("❌ Không thể ping database:", err)
+	}
+
+	batch, err := ledger.RunPayouts(context.Background(), db, cfg.Payouts.HoldingPeriod, cfg.Payouts.MinimumAmounts, nil)
+	if err != nil {
+		log.Fatal("❌ Không thể tạo đợt chi trả:", err)
+	}
+
+	if batch == nil {
+		log.Println("✅ Không có giảng viên nào đủ điều kiện chi trả")
+		return
+	}
+	log.Printf("✅ Đã tạo đợt chi trả %s với %d khoản chi (doanh thu trước %s)",
+		batch.ID, batch.Payouts, batch.EligibleBefore.Format("2006-01-02 15:04"))
+}
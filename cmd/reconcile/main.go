@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"internal/config"
+	"internal/counters"
+)
+
+func main() {
+	// -fix: cập nhật lại các cột bị lệch, mặc định chỉ báo cáo
+	fix := flag.Bool("fix", false, "cập nhật lại các bộ đếm bị lệch")
+	flag.Parse()
+
+	// Tải cấu hình (defaults, configs/, biến môi trường, *_FILE)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("❌ Không thể tải cấu hình:", err)
+	}
+
+	db, err := sql.Open("pgx", cfg.Database.DSN())
+	if err != nil {
+		log.Fatal("❌ Không thể kết nối đến database:", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatal("❌ Không thể ping database:", err)
+	}
+
+	// Tính lại bộ đếm của courses và instructor_profiles từ bảng nguồn
+	drift, err := counters.Reconcile(context.Background(), db, *fix)
+	if err != nil {
+		log.Fatal("❌ Không thể đối soát bộ đếm:", err)
+	}
+
+	if len(drift) == 0 {
+		log.Println("✅ Tất cả bộ đếm đều khớp với dữ liệu nguồn")
+		return
+	}
+
+	for _, d := range drift {
+		log.Printf("   %s %s: %s = %s, đúng là %s", d.Table, d.ID, d.Column, d.Stored, d.Actual)
+	}
+
+	if *fix {
+		log.Printf("✅ Đã cập nhật %d giá trị bị lệch", len(drift))
+		return
+	}
+	log.Printf("⚠️  Phát hiện %d giá trị bị lệch, chạy lại với -fix để cập nhật", len(drift))
+	os.Exit(1)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"internal/config"
+	"internal/subscription"
+)
+
+// Chạy định kỳ (cron) để gia hạn, chuyển quá hạn và thu hồi thuê bao; cùng
+// logic với job nền của API (subscriptions.scheduler_interval)
+func main() {
+	// Tải cấu hình (defaults, configs/, biến môi trường, *_FILE)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("❌ Không thể tải cấu hình:", err)
+	}
+
+	db, err := sql.Open("pgx", cfg.Database.DSN())
+	if err != nil {
+		log.Fatal("❌ Không thể kết nối đến database:", err)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatal("❌ Không thể ping database:", err)
+	}
+
+	res, ran, err := subscription.Process(context.Background(), db, time.Now(),
+		cfg.Subscriptions.RenewalLead, cfg.Subscriptions.GracePeriod)
+	if err != nil {
+		log.Fatal("❌ Không thể xử lý thuê bao:", err)
+	}
+
+	if !ran {
+		log.Println("✅ Một tiến trình khác đang xử lý thuê bao, bỏ qua lần chạy này")
+		return
+	}
+	log.Printf("✅ Đã xử lý thuê bao: %d khoản gia hạn mới, %d quá hạn, %d hủy cuối kỳ, %d hết hạn",
+		res.Renewals, res.PastDue, res.Cancelled, res.Expired)
+}
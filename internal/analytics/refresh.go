@@ -0,0 +1,80 @@
+// Package analytics keeps the materialized views behind the instructor
+// analytics endpoints up to date. The views hold daily buckets; coarser
+// granularities are aggregated at query time.
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Views lists the materialized views created by migration 011.
+var Views = []string{
+	"mv_course_daily_enrollments",
+	"mv_course_daily_revenue",
+	"mv_course_daily_ratings",
+	"mv_lecture_daily_progress",
+	"mv_section_learner_progress",
+}
+
+// refreshLockKey is the advisory lock held while refreshing, so that only
+// one API instance does the work per interval.
+const refreshLockKey = 0x616e616c79 // "analy"
+
+// Refresh refreshes every view without blocking readers. It returns false
+// without refreshing when another session holds the refresh lock.
+func Refresh(ctx context.Context, db *sql.DB) (bool, error) {
+	// Advisory locks belong to a session, so lock and unlock on one connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", refreshLockKey).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", refreshLockKey)
+
+	for _, view := range Views {
+		if _, err := conn.ExecContext(ctx, "REFRESH MATERIALIZED VIEW CONCURRENTLY "+view); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// Run refreshes the views once at startup, so a fresh deployment does not
+// serve empty views for a whole interval, then every interval until ctx is
+// cancelled. Failures are logged and retried on the next tick.
+func Run(ctx context.Context, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshLogged(ctx, db)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func refreshLogged(ctx context.Context, db *sql.DB) {
+	start := time.Now()
+	refreshed, err := Refresh(ctx, db)
+	switch {
+	case err != nil && ctx.Err() == nil:
+		logrus.WithError(err).Error("Failed to refresh analytics views")
+	case refreshed:
+		logrus.WithField("duration", time.Since(start).String()).Debug("Refreshed analytics views")
+	}
+}
@@ -0,0 +1,62 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRunRefreshesAtStartup(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`pg_try_advisory_lock`).WithArgs(refreshLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	for _, view := range Views {
+		mock.ExpectExec(`REFRESH MATERIALIZED VIEW CONCURRENTLY ` + view).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`pg_advisory_unlock`).WithArgs(refreshLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, db, time.Hour)
+		close(done)
+	}()
+
+	// The first tick is an hour away, so only the startup refresh can meet
+	// the expectations.
+	deadline := time.Now().Add(2 * time.Second)
+	for mock.ExpectationsWereMet() != nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefreshSkipsWhenLocked(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`pg_try_advisory_lock`).WithArgs(refreshLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	refreshed, err := Refresh(context.Background(), db)
+	if err != nil || refreshed {
+		t.Fatalf("Refresh = %v, %v; want false, nil", refreshed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
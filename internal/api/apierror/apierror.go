@@ -0,0 +1,100 @@
+// Package apierror defines the single error type returned by the API and
+// renders it either in the standard response envelope or, when the client
+// asks for it, as an RFC 7807 problem document.
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error is an API error with a stable machine-readable code. Message is safe
+// to show to clients; Err holds the underlying cause, which is only logged.
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	Details []FieldError
+	Err     error
+}
+
+// FieldError describes one invalid field of a request.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error with an explicit status.
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(code Code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+func NotFound(code Code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+func Conflict(code Code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+func Unprocessable(code Code, message string) *Error {
+	return New(http.StatusUnprocessableEntity, code, message)
+}
+
+// InvalidID reports a malformed UUID path or body parameter.
+func InvalidID(message string) *Error {
+	return BadRequest(CodeInvalidID, message)
+}
+
+// Internal wraps an unexpected failure. The cause is logged with the request
+// ID; clients only see message (or a generic text when it is empty).
+func Internal(err error, message string) *Error {
+	if message == "" {
+		message = "Internal server error"
+	}
+	return &Error{Status: http.StatusInternalServerError, Code: CodeInternal, Message: message, Err: err}
+}
+
+// WithDetails attaches field-level details.
+func (e *Error) WithDetails(details ...FieldError) *Error {
+	e.Details = append(e.Details, details...)
+	return e
+}
+
+// WithCause records the underlying error for the logs.
+func (e *Error) WithCause(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// From converts any error into an *Error; unknown errors become internal.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return Internal(err, "")
+}
+
+type panicError struct{ value interface{} }
+
+func (p panicError) Error() string {
+	return fmt.Sprintf("panic: %v", p.value)
+}
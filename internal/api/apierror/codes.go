@@ -0,0 +1,148 @@
+package apierror
+
+// Code is a stable identifier clients can branch on; messages may change,
+// codes may not.
+type Code string
+
+// Generic codes.
+const (
+	CodeInternal         Code = "INTERNAL_ERROR"
+	CodeInvalidRequest   Code = "INVALID_REQUEST"
+	CodeValidationFailed Code = "VALIDATION_FAILED"
+	CodeInvalidID        Code = "INVALID_ID"
+	CodeNoFieldsToUpdate Code = "NO_FIELDS_TO_UPDATE"
+	CodeRouteNotFound    Code = "ROUTE_NOT_FOUND"
+	CodeMethodNotAllowed Code = "METHOD_NOT_ALLOWED"
+	CodeRateLimited      Code = "RATE_LIMITED"
+	CodeInvalidCursor    Code = "INVALID_CURSOR"
+	CodeInvalidInclude   Code = "INVALID_INCLUDE"
+	CodeInvalidFields    Code = "INVALID_FIELDS"
+	CodeInvalidDateRange Code = "INVALID_DATE_RANGE"
+	CodeUnauthenticated  Code = "UNAUTHENTICATED"
+	CodeForbidden        Code = "FORBIDDEN"
+
+	CodeInvalidToken       Code = "INVALID_TOKEN"
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+
+	CodeInvalidIdempotencyKey Code = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyReused  Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyKeyInUse   Code = "IDEMPOTENCY_KEY_IN_USE"
+
+	// Fallbacks for constraints without a dedicated code.
+	CodeConflict          Code = "CONFLICT"
+	CodeStillReferenced   Code = "STILL_REFERENCED"
+	CodeReferenceNotFound Code = "REFERENCE_NOT_FOUND"
+)
+
+// Resource lookups.
+const (
+	CodeUserNotFound                Code = "USER_NOT_FOUND"
+	CodeInstructorNotFound          Code = "INSTRUCTOR_NOT_FOUND"
+	CodeInstructorProfileNotFound   Code = "INSTRUCTOR_PROFILE_NOT_FOUND"
+	CodeCategoryNotFound            Code = "CATEGORY_NOT_FOUND"
+	CodeCourseNotFound              Code = "COURSE_NOT_FOUND"
+	CodeSectionNotFound             Code = "SECTION_NOT_FOUND"
+	CodeLectureNotFound             Code = "LECTURE_NOT_FOUND"
+	CodeEnrollmentNotFound          Code = "ENROLLMENT_NOT_FOUND"
+	CodeLectureProgressNotFound     Code = "LECTURE_PROGRESS_NOT_FOUND"
+	CodeReviewNotFound              Code = "REVIEW_NOT_FOUND"
+	CodeQuestionNotFound            Code = "QUESTION_NOT_FOUND"
+	CodeAnswerNotFound              Code = "ANSWER_NOT_FOUND"
+	CodeAnnouncementNotFound        Code = "ANNOUNCEMENT_NOT_FOUND"
+	CodeNotificationNotFound        Code = "NOTIFICATION_NOT_FOUND"
+	CodeTagNotFound                 Code = "TAG_NOT_FOUND"
+	CodeCourseTagNotFound           Code = "COURSE_TAG_NOT_FOUND"
+	CodeWishlistItemNotFound        Code = "WISHLIST_ITEM_NOT_FOUND"
+	CodeCouponNotFound              Code = "COUPON_NOT_FOUND"
+	CodeReportNotFound              Code = "REPORT_NOT_FOUND"
+	CodeRevenueShareRuleNotFound    Code = "REVENUE_SHARE_RULE_NOT_FOUND"
+	CodePayoutBatchNotFound         Code = "PAYOUT_BATCH_NOT_FOUND"
+	CodePayoutNotFound              Code = "PAYOUT_NOT_FOUND"
+	CodeOrderNotFound               Code = "ORDER_NOT_FOUND"
+	CodeInvoiceNotFound             Code = "INVOICE_NOT_FOUND"
+	CodeCoursePriceNotFound         Code = "COURSE_PRICE_NOT_FOUND"
+	CodePromotionNotFound           Code = "PROMOTION_NOT_FOUND"
+	CodeGiftOrderNotFound           Code = "GIFT_ORDER_NOT_FOUND"
+	CodeVoucherNotFound             Code = "VOUCHER_NOT_FOUND"
+	CodeSubscriptionPlanNotFound    Code = "SUBSCRIPTION_PLAN_NOT_FOUND"
+	CodeSubscriptionNotFound        Code = "SUBSCRIPTION_NOT_FOUND"
+	CodeSubscriptionPaymentNotFound Code = "SUBSCRIPTION_PAYMENT_NOT_FOUND"
+	CodeOrganizationNotFound        Code = "ORGANIZATION_NOT_FOUND"
+	CodeOrganizationMemberNotFound  Code = "ORGANIZATION_MEMBER_NOT_FOUND"
+	CodeInvitationNotFound          Code = "INVITATION_NOT_FOUND"
+	CodeCourseAssignmentNotFound    Code = "COURSE_ASSIGNMENT_NOT_FOUND"
+)
+
+// Conflicts with existing state.
+const (
+	CodeEmailTaken              Code = "EMAIL_TAKEN"
+	CodeUsernameTaken           Code = "USERNAME_TAKEN"
+	CodeCourseSlugTaken         Code = "COURSE_SLUG_TAKEN"
+	CodeCategoryNameTaken       Code = "CATEGORY_NAME_TAKEN"
+	CodeCategorySlugTaken       Code = "CATEGORY_SLUG_TAKEN"
+	CodeSortOrderTaken          Code = "SORT_ORDER_TAKEN"
+	CodeTagNameTaken            Code = "TAG_NAME_TAKEN"
+	CodeTagSlugTaken            Code = "TAG_SLUG_TAKEN"
+	CodeCouponCodeTaken         Code = "COUPON_CODE_TAKEN"
+	CodeAlreadyEnrolled         Code = "ALREADY_ENROLLED"
+	CodeAlreadyReviewed         Code = "ALREADY_REVIEWED"
+	CodeAlreadyInWishlist       Code = "ALREADY_IN_WISHLIST"
+	CodeTagAlreadyOnCourse      Code = "TAG_ALREADY_ON_COURSE"
+	CodeLectureProgressExists   Code = "LECTURE_PROGRESS_EXISTS"
+	CodeInstructorProfileExists Code = "INSTRUCTOR_PROFILE_EXISTS"
+	CodeRevenueShareRuleExists  Code = "REVENUE_SHARE_RULE_EXISTS"
+	CodeInvoiceAlreadyIssued    Code = "INVOICE_ALREADY_ISSUED"
+	CodeVoucherAlreadyRedeemed  Code = "VOUCHER_ALREADY_REDEEMED"
+	CodeAlreadySubscribed       Code = "ALREADY_SUBSCRIBED"
+	CodePlanHasSubscriptions    Code = "PLAN_HAS_SUBSCRIPTIONS"
+	CodeAlreadyMember           Code = "ALREADY_MEMBER"
+	CodeCourseAlreadyAssigned   Code = "COURSE_ALREADY_ASSIGNED"
+	CodeCategoryHasChildren     Code = "CATEGORY_HAS_CHILDREN"
+	CodeCategoryHasCourses      Code = "CATEGORY_HAS_COURSES"
+	CodeCourseHasEnrollments    Code = "COURSE_HAS_ENROLLMENTS"
+	CodeSectionHasLectures      Code = "SECTION_HAS_LECTURES"
+	CodeUserHasCourses          Code = "USER_HAS_COURSES"
+)
+
+// Business rules.
+const (
+	CodeNotEnrolled        Code = "NOT_ENROLLED"
+	CodeNotInstructor      Code = "NOT_INSTRUCTOR"
+	CodeCourseNotPublished Code = "COURSE_NOT_PUBLISHED"
+
+	CodeCurriculumOrderMismatch Code = "CURRICULUM_ORDER_MISMATCH"
+
+	CodeCouponInactive         Code = "COUPON_INACTIVE"
+	CodeCouponNotYetValid      Code = "COUPON_NOT_YET_VALID"
+	CodeCouponExpired          Code = "COUPON_EXPIRED"
+	CodeCouponUsageExhausted   Code = "COUPON_USAGE_EXHAUSTED"
+	CodeCouponMinOrderNotMet   Code = "COUPON_MIN_ORDER_NOT_MET"
+	CodeCouponCurrencyMismatch Code = "COUPON_CURRENCY_MISMATCH"
+	CodeCouponNotCombinable    Code = "COUPON_NOT_COMBINABLE"
+	CodeCouponNotApplicable    Code = "COUPON_NOT_APPLICABLE"
+
+	CodeInvalidPayoutStatus     Code = "INVALID_PAYOUT_STATUS"
+	CodeDefaultRevenueShareRule Code = "DEFAULT_REVENUE_SHARE_RULE"
+
+	CodeOrderNotPaid         Code = "ORDER_NOT_PAID"
+	CodeInvalidPaymentStatus Code = "INVALID_PAYMENT_STATUS"
+
+	CodeUnsupportedCurrency Code = "UNSUPPORTED_CURRENCY"
+	CodeBasePriceRequired   Code = "BASE_PRICE_REQUIRED"
+
+	CodePromotionTargetNotFound Code = "PROMOTION_TARGET_NOT_FOUND"
+
+	CodeVoucherNotPaid           Code = "VOUCHER_NOT_PAID"
+	CodeVoucherCancelled         Code = "VOUCHER_CANCELLED"
+	CodeVoucherExpired           Code = "VOUCHER_EXPIRED"
+	CodeVoucherRecipientMismatch Code = "VOUCHER_RECIPIENT_MISMATCH"
+	CodeTooManyRecipients        Code = "TOO_MANY_RECIPIENTS"
+
+	CodeCourseAccessRequired      Code = "COURSE_ACCESS_REQUIRED"
+	CodePlanInactive              Code = "PLAN_INACTIVE"
+	CodePlanItemNotFound          Code = "PLAN_ITEM_NOT_FOUND"
+	CodeInvalidSubscriptionStatus Code = "INVALID_SUBSCRIPTION_STATUS"
+
+	CodeNoSeatsAvailable Code = "NO_SEATS_AVAILABLE"
+	CodeLastOwner        Code = "LAST_OWNER"
+)
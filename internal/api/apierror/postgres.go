@@ -0,0 +1,144 @@
+package apierror
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"internal/database"
+)
+
+// PostgreSQL SQLSTATE codes translated by FromDB.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+)
+
+// constraint describes how a named constraint surfaces to clients.
+type constraint struct {
+	Field   string
+	Code    Code
+	Message string
+}
+
+// uniqueConstraints maps unique indexes to 409 responses. Names are the
+// PostgreSQL defaults for the UNIQUE/PRIMARY KEY clauses in the migrations.
+var uniqueConstraints = map[string]constraint{
+	"users_email_key":                           {"email", CodeEmailTaken, "Email already exists"},
+	"users_username_key":                        {"username", CodeUsernameTaken, "Username already exists"},
+	"instructor_profiles_user_id_key":           {"user_id", CodeInstructorProfileExists, "Instructor profile already exists for this user"},
+	"categories_name_key":                       {"name", CodeCategoryNameTaken, "Category name already exists"},
+	"categories_slug_key":                       {"slug", CodeCategorySlugTaken, "Category slug already exists"},
+	"courses_slug_key":                          {"slug", CodeCourseSlugTaken, "Course slug already exists"},
+	"course_sections_course_id_sort_order_key":  {"sort_order", CodeSortOrderTaken, "Another section of this course already uses this sort order"},
+	"course_lectures_section_id_sort_order_key": {"sort_order", CodeSortOrderTaken, "Another lecture of this section already uses this sort order"},
+	"enrollments_user_id_course_id_key":         {"course_id", CodeAlreadyEnrolled, "User already enrolled in this course"},
+	"lecture_progress_user_id_lecture_id_key":   {"lecture_id", CodeLectureProgressExists, "Lecture progress already exists for this user and lecture"},
+	"course_reviews_user_id_course_id_key":      {"course_id", CodeAlreadyReviewed, "User has already reviewed this course"},
+	"wishlists_user_id_course_id_key":           {"course_id", CodeAlreadyInWishlist, "Course is already in user's wishlist"},
+	"coupons_code_key":                          {"code", CodeCouponCodeTaken, "Coupon code already exists"},
+	"tags_name_key":                             {"name", CodeTagNameTaken, "Tag name already exists"},
+	"tags_slug_key":                             {"slug", CodeTagSlugTaken, "Tag slug already exists"},
+	"course_tags_pkey":                          {"tag_id", CodeTagAlreadyOnCourse, "Tag already added to this course"},
+	"revenue_share_rules_scope_key":             {"instructor_id", CodeRevenueShareRuleExists, "A revenue share rule already exists for this instructor and coupon source"},
+	"uq_subscriptions_user_plan_open":           {"plan_id", CodeAlreadySubscribed, "User already has an open subscription to this plan"},
+	"organization_members_pkey":                 {"email", CodeAlreadyMember, "User is already a member of this organization"},
+	"uq_organization_course_assignments":        {"user_ids", CodeCourseAlreadyAssigned, "Course is already assigned to this member"},
+}
+
+// restrictConstraints are foreign keys that block deleting the parent row.
+var restrictConstraints = map[string]constraint{
+	"courses_category_id_fkey":   {"id", CodeCategoryHasCourses, "Cannot delete category that has associated courses"},
+	"subscriptions_plan_id_fkey": {"id", CodePlanHasSubscriptions, "Cannot delete a plan that has subscriptions; deactivate it instead"},
+}
+
+// referenceCodes names the "not found" code for a missing referenced row,
+// keyed by the referencing column.
+var referenceCodes = map[string]constraint{
+	"user_id":         {"user_id", CodeUserNotFound, "User not found"},
+	"instructor_id":   {"instructor_id", CodeInstructorNotFound, "Instructor not found"},
+	"category_id":     {"category_id", CodeCategoryNotFound, "Category not found"},
+	"parent_id":       {"parent_id", CodeCategoryNotFound, "Parent category not found"},
+	"course_id":       {"course_id", CodeCourseNotFound, "Course not found"},
+	"section_id":      {"section_id", CodeSectionNotFound, "Section not found"},
+	"lecture_id":      {"lecture_id", CodeLectureNotFound, "Lecture not found"},
+	"question_id":     {"question_id", CodeQuestionNotFound, "Question not found"},
+	"tag_id":          {"tag_id", CodeTagNotFound, "Tag not found"},
+	"plan_id":         {"plan_id", CodeSubscriptionPlanNotFound, "Subscription plan not found"},
+	"organization_id": {"organization_id", CodeOrganizationNotFound, "Organization not found"},
+}
+
+// FromDB translates constraint violations into client errors naming the
+// offending field: unique -> 409, foreign key / check / not null -> 422.
+// Anything else is an internal error reported with message.
+func FromDB(err error, message string) *Error {
+	pgErr, ok := database.AsPgError(err)
+	if !ok {
+		return Internal(err, message)
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		if c, ok := uniqueConstraints[pgErr.ConstraintName]; ok {
+			return fieldError(http.StatusConflict, c, "unique", err)
+		}
+		return New(http.StatusConflict, CodeConflict, "Resource already exists").WithCause(err)
+
+	case pgForeignKeyViolation:
+		// Deleting a row that is still referenced reports the child table.
+		if strings.Contains(pgErr.Detail, "is still referenced") {
+			if c, ok := restrictConstraints[pgErr.ConstraintName]; ok {
+				return New(http.StatusConflict, c.Code, c.Message).WithCause(err)
+			}
+			return New(http.StatusConflict, CodeStillReferenced, "Resource is still referenced").WithCause(err)
+		}
+		column := foreignKeyColumn(pgErr)
+		if c, ok := referenceCodes[column]; ok {
+			return fieldError(http.StatusUnprocessableEntity, c, "exists", err)
+		}
+		return fieldError(http.StatusUnprocessableEntity, constraint{column, CodeReferenceNotFound, "Referenced resource not found"}, "exists", err)
+
+	case pgCheckViolation:
+		column := checkColumn(pgErr)
+		return fieldError(http.StatusUnprocessableEntity, constraint{column, CodeValidationFailed, "Request validation failed"}, "check", err)
+
+	case pgNotNullViolation:
+		return fieldError(http.StatusUnprocessableEntity, constraint{pgErr.ColumnName, CodeValidationFailed, "Request validation failed"}, "required", err)
+	}
+	return Internal(err, message)
+}
+
+func fieldError(status int, c constraint, rule string, cause error) *Error {
+	var detail string
+	switch rule {
+	case "unique":
+		detail = "is already taken"
+	case "exists":
+		detail = "does not reference an existing record"
+	case "check":
+		detail = "has a value that is not allowed"
+	default:
+		detail = "is required"
+	}
+	return New(status, c.Code, c.Message).
+		WithDetails(FieldError{Field: c.Field, Rule: rule, Message: detail}).
+		WithCause(cause)
+}
+
+// foreignKeyColumn extracts the column from a default "<table>_<column>_fkey"
+// name.
+func foreignKeyColumn(pgErr *pgconn.PgError) string {
+	name := strings.TrimSuffix(pgErr.ConstraintName, "_fkey")
+	return strings.TrimPrefix(name, pgErr.TableName+"_")
+}
+
+// checkColumn extracts the column from a default "<table>_<column>_check" name.
+func checkColumn(pgErr *pgconn.PgError) string {
+	if pgErr.ColumnName != "" {
+		return pgErr.ColumnName
+	}
+	name := strings.TrimSuffix(pgErr.ConstraintName, "_check")
+	return strings.TrimPrefix(name, pgErr.TableName+"_")
+}
@@ -0,0 +1,63 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestFromDB(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    *pgconn.PgError
+		status int
+		code   Code
+		field  string
+		rule   string
+	}{
+		{"known unique index", &pgconn.PgError{Code: pgUniqueViolation, TableName: "users", ConstraintName: "users_email_key"},
+			http.StatusConflict, CodeEmailTaken, "email", "unique"},
+		{"unknown unique index", &pgconn.PgError{Code: pgUniqueViolation, TableName: "users", ConstraintName: "users_phone_key"},
+			http.StatusConflict, CodeConflict, "", ""},
+		{"missing reference", &pgconn.PgError{Code: pgForeignKeyViolation, TableName: "enrollments", ConstraintName: "enrollments_course_id_fkey",
+			Detail: `Key (course_id)=(0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c) is not present in table "courses".`},
+			http.StatusUnprocessableEntity, CodeCourseNotFound, "course_id", "exists"},
+		{"unknown reference", &pgconn.PgError{Code: pgForeignKeyViolation, TableName: "courses", ConstraintName: "courses_owner_id_fkey"},
+			http.StatusUnprocessableEntity, CodeReferenceNotFound, "owner_id", "exists"},
+		{"delete blocked by children", &pgconn.PgError{Code: pgForeignKeyViolation, TableName: "courses", ConstraintName: "courses_category_id_fkey",
+			Detail: `Key (id)=(7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d) is still referenced from table "courses".`},
+			http.StatusConflict, CodeCategoryHasCourses, "", ""},
+		{"check constraint", &pgconn.PgError{Code: pgCheckViolation, TableName: "courses", ConstraintName: "courses_level_check"},
+			http.StatusUnprocessableEntity, CodeValidationFailed, "level", "check"},
+		{"not null", &pgconn.PgError{Code: pgNotNullViolation, TableName: "courses", ColumnName: "title"},
+			http.StatusUnprocessableEntity, CodeValidationFailed, "title", "required"},
+		{"other server error", &pgconn.PgError{Code: "40001"}, http.StatusInternalServerError, CodeInternal, "", ""},
+	}
+	for _, tt := range tests {
+		// Errors reach handlers wrapped by database/sql and our own helpers.
+		e := FromDB(fmt.Errorf("insert: %w", tt.err), "Failed to save")
+		if e.Status != tt.status || e.Code != tt.code {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, e.Status, e.Code, tt.status, tt.code)
+			continue
+		}
+		if !errors.Is(e, tt.err) {
+			t.Errorf("%s: cause lost", tt.name)
+		}
+		if tt.field == "" {
+			if len(e.Details) != 0 {
+				t.Errorf("%s: details = %+v", tt.name, e.Details)
+			}
+			continue
+		}
+		if len(e.Details) != 1 || e.Details[0].Field != tt.field || e.Details[0].Rule != tt.rule {
+			t.Errorf("%s: details = %+v, want %s/%s", tt.name, e.Details, tt.field, tt.rule)
+		}
+	}
+
+	if e := FromDB(errors.New("connection reset"), "Failed to save"); e.Status != http.StatusInternalServerError || e.Message != "Failed to save" {
+		t.Errorf("non-database error = %d %q", e.Status, e.Message)
+	}
+}
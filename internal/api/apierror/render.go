@@ -0,0 +1,96 @@
+package apierror
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDKey is the gin context key set by middleware.RequestID.
+const requestIDKey = "request_id"
+
+// ProblemContentType is served when the client accepts it (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+type envelope struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message"`
+	Error   errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code      Code         `json:"code"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+type problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance"`
+	Code      Code         `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// Abort records err on the context for the request log, writes the error
+// response and stops the handler chain. Handlers return right after it.
+func Abort(c *gin.Context, err error) {
+	e := From(err)
+	_ = c.Error(e)
+
+	requestID := c.GetString(requestIDKey)
+	if wantsProblem(c.GetHeader("Accept")) {
+		c.Header("Content-Type", ProblemContentType)
+		c.AbortWithStatusJSON(e.Status, problem{
+			Type:      "about:blank",
+			Title:     http.StatusText(e.Status),
+			Status:    e.Status,
+			Detail:    e.Message,
+			Instance:  c.Request.URL.Path,
+			Code:      e.Code,
+			RequestID: requestID,
+			Errors:    e.Details,
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(e.Status, envelope{
+		Success: false,
+		Message: e.Message,
+		Error: errorBody{
+			Code:      e.Code,
+			Details:   e.Details,
+			RequestID: requestID,
+		},
+	})
+}
+
+func wantsProblem(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.EqualFold(strings.TrimSpace(mediaType), ProblemContentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// NoRoute and NoMethod render unmatched requests in the same shape.
+func NoRoute(c *gin.Context) {
+	Abort(c, NotFound(CodeRouteNotFound, "Route not found"))
+}
+
+func NoMethod(c *gin.Context) {
+	Abort(c, New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "Method not allowed"))
+}
+
+// Recovery turns a panic into a logged internal error.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		Abort(c, Internal(panicError{recovered}, ""))
+	})
+}
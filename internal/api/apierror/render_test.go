@@ -0,0 +1,138 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	UseJSONFieldNames()
+}
+
+// serve runs handler for GET /courses/:id with the request ID set as
+// middleware.RequestID would.
+func serve(t *testing.T, accept string, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	r := gin.New()
+	r.Use(Recovery(), func(c *gin.Context) { c.Set(requestIDKey, "req-7f3a9c") })
+	r.GET("/courses/:id", handler)
+	req := httptest.NewRequest(http.MethodGet, "/courses/42", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAbortEnvelope(t *testing.T) {
+	w := serve(t, "", func(c *gin.Context) {
+		Abort(c, NotFound(CodeCourseNotFound, "Course not found"))
+	})
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var got envelope
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := envelope{Message: "Course not found", Error: errorBody{Code: CodeCourseNotFound, RequestID: "req-7f3a9c"}}
+	if got.Success || got.Message != want.Message || got.Error.Code != want.Error.Code || got.Error.RequestID != want.Error.RequestID {
+		t.Errorf("body = %s", w.Body)
+	}
+}
+
+func TestAbortProblemDocument(t *testing.T) {
+	w := serve(t, "application/json;q=0.9, application/problem+json", func(c *gin.Context) {
+		Abort(c, BadRequest(CodeValidationFailed, "Request validation failed").
+			WithDetails(FieldError{Field: "title", Rule: "required", Message: "is required"}))
+	})
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, ProblemContentType) {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var got problem
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != http.StatusBadRequest || got.Title != "Bad Request" || got.Instance != "/courses/42" ||
+		got.Code != CodeValidationFailed || got.RequestID != "req-7f3a9c" || len(got.Errors) != 1 {
+		t.Errorf("problem = %+v", got)
+	}
+}
+
+// Internal causes and panics are logged, never shown to the client.
+func TestInternalErrorsHideCause(t *testing.T) {
+	errPassword := errors.New("dial postgres://app:hunter2@db:5432")
+	for name, handler := range map[string]gin.HandlerFunc{
+		"internal": func(c *gin.Context) { Abort(c, Internal(errPassword, "")) },
+		"panic":    func(c *gin.Context) { panic(errPassword) },
+	} {
+		w := serve(t, "", handler)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%s: status = %d", name, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "hunter2") || !strings.Contains(w.Body.String(), `"INTERNAL_ERROR"`) {
+			t.Errorf("%s: body = %s", name, w.Body)
+		}
+	}
+}
+
+type createCourseRequest struct {
+	Title    string   `json:"title" binding:"required,max=10"`
+	Email    string   `json:"contact_email" binding:"omitempty,email"`
+	Level    string   `json:"level" binding:"omitempty,oneof=beginner advanced"`
+	Tags     []string `json:"tags" binding:"omitempty,min=1"`
+	Duration int      `json:"duration"`
+}
+
+func bind(body string) *Error {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/courses", strings.NewReader(body))
+	var req createCourseRequest
+	return Validation(c.ShouldBindJSON(&req))
+}
+
+func TestValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		code    Code
+		details []FieldError
+	}{
+		{"rules", `{"title":"Giải tích 12 nâng cao","contact_email":"x","level":"expert","tags":[]}`, CodeValidationFailed, []FieldError{
+			{"title", "max", "must be at most 10 characters long"},
+			{"contact_email", "email", "must be a valid email address"},
+			{"level", "oneof", "must be one of: beginner, advanced"},
+			{"tags", "min", "must contain at least 1 items"},
+		}},
+		{"missing field", `{}`, CodeValidationFailed, []FieldError{{"title", "required", "is required"}}},
+		{"wrong type", `{"title":"Go","duration":"60"}`, CodeValidationFailed, []FieldError{{"duration", "type", "must be of type integer"}}},
+		{"malformed JSON", `{"title":`, CodeInvalidRequest, nil},
+		{"empty body", ``, CodeInvalidRequest, nil},
+	}
+	for _, tt := range tests {
+		e := bind(tt.body)
+		if e.Status != http.StatusBadRequest || e.Code != tt.code {
+			t.Errorf("%s: got %d %s, want 400 %s", tt.name, e.Status, e.Code, tt.code)
+		}
+		if len(e.Details) != len(tt.details) {
+			t.Errorf("%s: details = %+v, want %+v", tt.name, e.Details, tt.details)
+			continue
+		}
+		for i := range tt.details {
+			if e.Details[i] != tt.details[i] {
+				t.Errorf("%s: detail %d = %+v, want %+v", tt.name, i, e.Details[i], tt.details[i])
+			}
+		}
+		if strings.Contains(e.Message, "createCourseRequest") {
+			t.Errorf("%s: message leaks Go types: %q", tt.name, e.Message)
+		}
+	}
+}
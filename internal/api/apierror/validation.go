@@ -0,0 +1,153 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"internal/money"
+)
+
+// UseJSONFieldNames makes the Gin validator report fields by their json (or
+// form) tag instead of the Go field name, so details match the request body.
+func UseJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, tag := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "-" {
+				return ""
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+}
+
+// RegisterRules adds the validation tags shared by the request DTOs:
+// currency accepts the codes internal/money supports.
+func RegisterRules() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return money.IsSupported(fl.Field().String())
+	})
+}
+
+// Validation converts an error from c.ShouldBind* into a 400 with one detail
+// per invalid field. Decoder errors never expose Go type names.
+func Validation(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &validationErrs):
+		details := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			details = append(details, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: ruleMessage(fe),
+			})
+		}
+		return BadRequest(CodeValidationFailed, "Request validation failed").WithDetails(details...).WithCause(err)
+
+	case errors.As(err, &typeErr):
+		return BadRequest(CodeValidationFailed, "Request validation failed").WithDetails(FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be of type " + jsonKind(typeErr.Type),
+		}).WithCause(err)
+
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return BadRequest(CodeInvalidRequest, "Malformed JSON body").WithCause(err)
+
+	case errors.Is(err, io.EOF):
+		return BadRequest(CodeInvalidRequest, "Request body is required").WithCause(err)
+	}
+	return BadRequest(CodeInvalidRequest, "Invalid request").WithCause(err)
+}
+
+// fieldPath drops the top-level struct name: "CreateCourseRequest.title" -> "title".
+func fieldPath(namespace string) string {
+	if _, rest, ok := strings.Cut(namespace, "."); ok {
+		return rest
+	}
+	return namespace
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "url":
+		return "must be a valid URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "currency":
+		return "must be one of: " + strings.Join(money.Supported(), ", ")
+	case "required_with":
+		return "is required when " + strings.ToLower(fe.Param()) + " is set"
+	case "min":
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be at least %s characters long", fe.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must contain at least %s items", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be at most %s characters long", fe.Param())
+		case reflect.Slice, reflect.Map:
+			return fmt.Sprintf("must contain at most %s items", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	case "len":
+		return "must have length " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
+
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
@@ -0,0 +1,147 @@
+package dto
+
+// Instructor analytics DTOs
+
+// AnalyticsQuery selects the date range (inclusive, Vietnam time) and the
+// bucket size. Without from/to the last 30 days are returned.
+type AnalyticsQuery struct {
+	From        string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To          string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Granularity string `form:"granularity" binding:"omitempty,oneof=day week month"`
+	CourseID    string `form:"course_id" binding:"omitempty,uuid"`
+}
+
+// AnalyticsRange echoes the range a response was computed for.
+type AnalyticsRange struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Granularity string `json:"granularity,omitempty"`
+}
+
+type AnalyticsSummary struct {
+	AnalyticsRange
+	Enrollments         int64           `json:"enrollments"`
+	Sales               int64           `json:"sales"`
+	Revenue             []CurrencyTotal `json:"revenue"` // theo tiền tệ của đơn
+	Reviews             int64           `json:"reviews"`
+	AverageRating       *float64        `json:"average_rating"`
+	AverageWatchTime    float64         `json:"average_watch_time"` // giây trên mỗi lượt học bài giảng
+	UnansweredQuestions int64           `json:"unanswered_questions"`
+}
+
+type EnrollmentSeries struct {
+	AnalyticsRange
+	Points []EnrollmentPoint `json:"points"`
+}
+
+type EnrollmentPoint struct {
+	Bucket      string `json:"bucket"`
+	Enrollments int64  `json:"enrollments"`
+}
+
+type RevenueSeries struct {
+	AnalyticsRange
+	Points []RevenuePoint `json:"points"`
+}
+
+// RevenuePoint is one course in one currency and bucket of payment dates;
+// buckets without sales are omitted.
+type RevenuePoint struct {
+	Bucket      string `json:"bucket"`
+	CourseID    string `json:"course_id"`
+	CourseTitle string `json:"course_title"`
+	Currency    string `json:"currency"`
+	Sales       int64  `json:"sales"`
+	Revenue     int64  `json:"revenue"` // đơn vị nhỏ nhất của currency
+}
+
+type RatingSeries struct {
+	AnalyticsRange
+	Points []RatingPoint `json:"points"`
+}
+
+type RatingPoint struct {
+	Bucket        string   `json:"bucket"`
+	Reviews       int64    `json:"reviews"`
+	AverageRating *float64 `json:"average_rating"`
+}
+
+// CourseFunnel covers learners who started a section (or lecture) within
+// the range.
+type CourseFunnel struct {
+	AnalyticsRange
+	CourseID string           `json:"course_id"`
+	Title    string           `json:"title"`
+	Sections []SectionFunnel  `json:"sections"`
+	Lectures []LectureDropOff `json:"lectures"`
+}
+
+type SectionFunnel struct {
+	SectionID      string   `json:"section_id"`
+	Title          string   `json:"title"`
+	SortOrder      int32    `json:"sort_order"`
+	TotalLectures  int      `json:"total_lectures"`
+	Learners       int64    `json:"learners"`
+	Completed      int64    `json:"completed"`
+	CompletionRate *float64 `json:"completion_rate"`
+}
+
+// LectureDropOff compares a lecture with the one before it in curriculum
+// order: DropOff learners started the previous lecture but not this one.
+type LectureDropOff struct {
+	LectureID        string   `json:"lecture_id"`
+	SectionID        string   `json:"section_id"`
+	Title            string   `json:"title"`
+	Started          int64    `json:"started"`
+	Completed        int64    `json:"completed"`
+	AverageWatchTime float64  `json:"average_watch_time"` // tính bằng giây
+	DropOff          int64    `json:"drop_off"`
+	DropOffRate      *float64 `json:"drop_off_rate"`
+}
+
+// Admin analytics DTOs
+
+// AdminReportQuery selects the range of an admin report. Limit caps list
+// reports; exports without a limit contain every row.
+type AdminReportQuery struct {
+	From        string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To          string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Granularity string `form:"granularity" binding:"omitempty,oneof=day week month"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+	Format      string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+// ReportRow is one row of a report keyed by column name.
+type ReportRow map[string]interface{}
+
+type AdminReport struct {
+	AnalyticsRange
+	Report  string      `json:"report"`
+	Columns []string    `json:"columns"`
+	Rows    []ReportRow `json:"rows"`
+}
+
+// AdminDashboard holds the platform KPIs for the range. Revenue counts paid
+// orders per order currency: GMV before coupon discounts, net revenue after
+// discounts and refunds.
+type AdminDashboard struct {
+	AnalyticsRange
+	NewUsers           map[string]int64  `json:"new_users"` // theo role
+	Orders             int64             `json:"orders"`
+	Revenue            []CurrencyRevenue `json:"revenue"` // mỗi tiền tệ một dòng
+	PendingCourses     int64             `json:"pending_courses"`
+	PendingInstructors int64             `json:"pending_instructors"`
+	TopCourses         []ReportRow       `json:"top_courses"`
+	TopCategories      []ReportRow       `json:"top_categories"`
+}
+
+// CurrencyRevenue is the dashboard revenue of the orders in one currency,
+// in minor units of that currency.
+type CurrencyRevenue struct {
+	Currency   string `json:"currency"`
+	Orders     int64  `json:"orders"`
+	GMV        int64  `json:"gmv"`
+	Discounts  int64  `json:"discounts"`
+	Refunds    int64  `json:"refunds"`
+	NetRevenue int64  `json:"net_revenue"`
+}
@@ -1,85 +1,159 @@
-package dto
-
-import (
-	"time"
-)
-
-// Course Section DTOs
-type CreateCourseSectionRequest struct {
-	CourseID    string  `json:"course_id" binding:"required"`
-	Title       string  `json:"title" binding:"required"`
-	Description *string `json:"description"`
-	SortOrder   int32   `json:"sort_order" binding:"required"`
-}
-
-type UpdateCourseSectionRequest struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	SortOrder   *int32  `json:"sort_order"`
-}
-
-type CourseSectionResponse struct {
-	ID          string                  `json:"id"`
-	CourseID    string                  `json:"course_id"`
-	Title       string                  `json:"title"`
-	Description *string                 `json:"description"`
-	SortOrder   int32                   `json:"sort_order"`
-	CreatedAt   time.Time               `json:"created_at"`
-	UpdatedAt   time.Time               `json:"updated_at"`
-	Lectures    []CourseLectureResponse `json:"lectures,omitempty"`
-}
-
-type CourseSectionListResponse struct {
-	Sections   []CourseSectionResponse `json:"sections"`
-	Pagination PaginationResponse      `json:"pagination"`
-}
-
-// Course Lecture DTOs
-type CreateCourseLectureRequest struct {
-	SectionID       string  `json:"section_id" binding:"required"`
-	Title           string  `json:"title" binding:"required"`
-	Description     *string `json:"description"`
-	ContentType     string  `json:"content_type" binding:"required,oneof=video article quiz file"`
-	VideoURL        *string `json:"video_url"`
-	VideoDuration   *int32  `json:"video_duration"`
-	ArticleContent  *string `json:"article_content"`
-	FileURL         *string `json:"file_url"`
-	SortOrder       int32   `json:"sort_order" binding:"required"`
-	IsPreview       *bool   `json:"is_preview"`
-	IsDownloadable  *bool   `json:"is_downloadable"`
-}
-
-type UpdateCourseLectureRequest struct {
-	Title          *string `json:"title"`
-	Description    *string `json:"description"`
-	ContentType    *string `json:"content_type" binding:"omitempty,oneof=video article quiz file"`
-	VideoURL       *string `json:"video_url"`
-	VideoDuration  *int32  `json:"video_duration"`
-	ArticleContent *string `json:"article_content"`
-	FileURL        *string `json:"file_url"`
-	SortOrder      *int32  `json:"sort_order"`
-	IsPreview      *bool   `json:"is_preview"`
-	IsDownloadable *bool   `json:"is_downloadable"`
-}
-
-type CourseLectureResponse struct {
-	ID             string    `json:"id"`
-	SectionID      string    `json:"section_id"`
-	Title          string    `json:"title"`
-	Description    *string   `json:"description"`
-	ContentType    string    `json:"content_type"`
-	VideoURL       *string   `json:"video_url"`
-	VideoDuration  *int32    `json:"video_duration"`
-	ArticleContent *string   `json:"article_content"`
-	FileURL        *string   `json:"file_url"`
-	SortOrder      int32     `json:"sort_order"`
-	IsPreview      bool      `json:"is_preview"`
-	IsDownloadable bool      `json:"is_downloadable"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-}
-
-type CourseLectureListResponse struct {
-	Lectures   []CourseLectureResponse `json:"lectures"`
-	Pagination PaginationResponse      `json:"pagination"`
-}
+package dto
+
+import (
+	"time"
+)
+
+// Course Section DTOs
+type CreateCourseSectionRequest struct {
+	CourseID    string  `json:"course_id" binding:"required"`
+	Title       string  `json:"title" binding:"required"`
+	Description *string `json:"description"`
+	SortOrder   int32   `json:"sort_order" binding:"required"`
+}
+
+type UpdateCourseSectionRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	SortOrder   *int32  `json:"sort_order"`
+}
+
+type CourseSectionResponse struct {
+	ID          string                  `json:"id"`
+	CourseID    string                  `json:"course_id"`
+	Title       string                  `json:"title"`
+	Description *string                 `json:"description"`
+	SortOrder   int32                   `json:"sort_order"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	Lectures    []CourseLectureResponse `json:"lectures,omitempty"`
+}
+
+type CourseSectionListResponse struct {
+	Sections   []CourseSectionResponse `json:"sections"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// Course Lecture DTOs
+type CreateCourseLectureRequest struct {
+	SectionID       string  `json:"section_id" binding:"required"`
+	Title           string  `json:"title" binding:"required"`
+	Description     *string `json:"description"`
+	ContentType     string  `json:"content_type" binding:"required,oneof=video article quiz file"`
+	VideoURL        *string `json:"video_url"`
+	VideoDuration   *int32  `json:"video_duration"`
+	ArticleContent  *string `json:"article_content"`
+	FileURL         *string `json:"file_url"`
+	SortOrder       int32   `json:"sort_order" binding:"required"`
+	IsPreview       *bool   `json:"is_preview"`
+	IsDownloadable  *bool   `json:"is_downloadable"`
+}
+
+type UpdateCourseLectureRequest struct {
+	Title          *string `json:"title"`
+	Description    *string `json:"description"`
+	ContentType    *string `json:"content_type" binding:"omitempty,oneof=video article quiz file"`
+	VideoURL       *string `json:"video_url"`
+	VideoDuration  *int32  `json:"video_duration"`
+	ArticleContent *string `json:"article_content"`
+	FileURL        *string `json:"file_url"`
+	SortOrder      *int32  `json:"sort_order"`
+	IsPreview      *bool   `json:"is_preview"`
+	IsDownloadable *bool   `json:"is_downloadable"`
+}
+
+type CourseLectureResponse struct {
+	ID             string    `json:"id"`
+	SectionID      string    `json:"section_id"`
+	Title          string    `json:"title"`
+	Description    *string   `json:"description"`
+	ContentType    string    `json:"content_type"`
+	VideoURL       *string   `json:"video_url"`
+	VideoDuration  *int32    `json:"video_duration"`
+	ArticleContent *string   `json:"article_content"`
+	FileURL        *string   `json:"file_url"`
+	SortOrder      int32     `json:"sort_order"`
+	IsPreview      bool      `json:"is_preview"`
+	IsDownloadable bool      `json:"is_downloadable"`
+	Locked         bool      `json:"locked"` // nội dung (video_url, article_content, file_url) bị ẩn vì user chưa có quyền học
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CourseLectureDTO is the summary of a lecture embedded in other resources
+// (?include=lecture).
+type CourseLectureDTO struct {
+	ID          string `json:"id"`
+	SectionID   string `json:"section_id"`
+	Title       string `json:"title"`
+	ContentType string `json:"content_type"`
+	SortOrder   int32  `json:"sort_order"`
+}
+
+type CourseLectureListResponse struct {
+	Lectures   []CourseLectureResponse `json:"lectures"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// Curriculum DTOs
+
+// CurriculumResponse is the whole course outline for the learner player.
+// Progress and Resume are only set for an authenticated user. Lectures are
+// locked unless they are previews or the user has access to the course.
+type CurriculumResponse struct {
+	CourseID      string              `json:"course_id"`
+	Title         string              `json:"title"`
+	HasAccess     bool                `json:"has_access"`
+	AccessSource  *string             `json:"access_source,omitempty"` // enrollment, subscription, instructor hoặc admin
+	TotalLectures int                 `json:"total_lectures"`
+	TotalDuration int64               `json:"total_duration"` // tính bằng giây
+	Completed     *int                `json:"completed_lectures,omitempty"`
+	Sections      []CurriculumSection `json:"sections"`
+	Resume        *CurriculumResume   `json:"resume,omitempty"`
+}
+
+type CurriculumSection struct {
+	ID          string              `json:"id"`
+	Title       string              `json:"title"`
+	Description *string             `json:"description"`
+	SortOrder   int32               `json:"sort_order"`
+	Duration    int64               `json:"duration"` // tính bằng giây
+	Lectures    []CurriculumLecture `json:"lectures"`
+}
+
+type CurriculumLecture struct {
+	ID             string              `json:"id"`
+	Title          string              `json:"title"`
+	ContentType    string              `json:"content_type"`
+	Duration       *int32              `json:"duration"` // tính bằng giây
+	SortOrder      int32               `json:"sort_order"`
+	IsPreview      bool                `json:"is_preview"`
+	IsDownloadable bool                `json:"is_downloadable"`
+	Locked         bool                `json:"locked"`
+	Progress       *CurriculumProgress `json:"progress,omitempty"`
+}
+
+type CurriculumProgress struct {
+	IsCompleted bool       `json:"is_completed"`
+	WatchTime   int        `json:"watch_time"` // vị trí đang xem, tính bằng giây
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CurriculumResume points at the lecture the learner should continue with.
+type CurriculumResume struct {
+	SectionID string `json:"section_id"`
+	LectureID string `json:"lecture_id"`
+	WatchTime int    `json:"watch_time"`
+}
+
+// ReorderCurriculumRequest is the complete desired order of a course: every
+// section once, each with every lecture it should contain. Lectures listed
+// under a different section are moved there.
+type ReorderCurriculumRequest struct {
+	Sections []CurriculumOrderSection `json:"sections" binding:"required,min=1,dive"`
+}
+
+type CurriculumOrderSection struct {
+	ID       string   `json:"id" binding:"required,uuid"`
+	Lectures []string `json:"lectures" binding:"dive,uuid"`
+}
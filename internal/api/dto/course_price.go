@@ -0,0 +1,25 @@
+package dto
+
+import "time"
+
+// CoursePriceDTO - Giá của khóa học ở một tiền tệ, tính bằng đơn vị nhỏ nhất
+// (đồng, cent). IsBase là giá gốc lưu trên khóa học
+type CoursePriceDTO struct {
+	Currency      string    `json:"currency"`
+	Price         int64     `json:"price"`
+	DiscountPrice *int64    `json:"discount_price"`
+	IsBase        bool      `json:"is_base"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SetCoursePriceRequest - Đặt giá của khóa học ở tiền tệ trong URL
+type SetCoursePriceRequest struct {
+	Price         *int64 `json:"price" binding:"required,min=0"`
+	DiscountPrice *int64 `json:"discount_price" binding:"omitempty,min=0"`
+}
+
+// CurrencyQuery - Tiền tệ muốn hiển thị giá; khóa học chưa có giá ở tiền tệ
+// này thì trả về giá gốc (xem trường currency của khóa học)
+type CurrencyQuery struct {
+	Currency string `form:"currency" binding:"omitempty,currency"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CursorQuery selects keyset pagination. It is used when the request has an
+// `after` parameter (empty for the first page); rows are ordered newest
+// first by (created_at, id) and COUNT(*) only runs with include_total=true.
+type CursorQuery struct {
+	After        string `form:"after"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	IncludeTotal bool   `form:"include_total"`
+}
+
+func (q *CursorQuery) SetDefaults() {
+	if q.Limit == 0 {
+		q.Limit = 10
+	}
+}
+
+// CursorMeta replaces the page counters in cursor mode. NextCursor is empty
+// on the last page.
+type CursorMeta struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	Total      *int64 `json:"total,omitempty"`
+}
+
+// CursorListResponse is the cursor-mode body of list endpoints that return
+// {data, pagination}.
+type CursorListResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination CursorMeta  `json:"pagination"`
+}
+
+// Cursor is the position of the last row of a page. Clients treat the
+// encoded form as opaque.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,59 @@
+package dto
+
+import "time"
+
+// CreateGiftOrderRequest - Mua khóa học tặng người khác hoặc mua nhiều suất
+// cho công ty. Mỗi suất sinh một voucher
+type CreateGiftOrderRequest struct {
+	Items    []GiftItemRequest `json:"items" binding:"required,min=1,max=20,dive"`
+	Currency string            `json:"currency,omitempty" binding:"omitempty,currency"` // mặc định VND
+	Message  *string           `json:"message,omitempty" binding:"omitempty,max=1000"`
+}
+
+// GiftItemRequest - Số suất của một khóa học. recipient_emails (không quá
+// quantity) gắn lần lượt vào các voucher đầu; voucher còn lại ai có mã cũng đổi được
+type GiftItemRequest struct {
+	CourseID        string   `json:"course_id" binding:"required,uuid"`
+	Quantity        int      `json:"quantity" binding:"required,min=1,max=500"`
+	RecipientEmails []string `json:"recipient_emails,omitempty" binding:"omitempty,max=500,dive,email"`
+}
+
+// GiftOrderDTO - Đơn quà tặng; số tiền tính bằng đơn vị nhỏ nhất của currency
+type GiftOrderDTO struct {
+	ID             string           `json:"id"`
+	PaymentStatus  string           `json:"payment_status"`
+	Currency       string           `json:"currency"`
+	TotalAmount    int64            `json:"total_amount"`
+	DiscountAmount int64            `json:"discount_amount"`
+	FinalAmount    int64            `json:"final_amount"`
+	Message        *string          `json:"message,omitempty"`
+	VoucherCount   int              `json:"voucher_count"`
+	RedeemedCount  int              `json:"redeemed_count"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Vouchers       []GiftVoucherDTO `json:"vouchers,omitempty"`
+}
+
+// GiftVoucherDTO - Voucher đổi lấy một suất học
+type GiftVoucherDTO struct {
+	ID              string     `json:"id"`
+	Code            string     `json:"code"`
+	CourseID        string     `json:"course_id"`
+	CourseTitle     string     `json:"course_title"`
+	RecipientEmail  *string    `json:"recipient_email,omitempty"`
+	Status          string     `json:"status"` // pending_payment, available, redeemed, expired, cancelled
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RedeemedBy      *string    `json:"redeemed_by,omitempty"`
+	RedeemedByEmail *string    `json:"redeemed_by_email,omitempty"`
+	RedeemedAt      *time.Time `json:"redeemed_at,omitempty"`
+}
+
+// GiftOrderListResponse - Response danh sách đơn quà tặng
+type GiftOrderListResponse struct {
+	GiftOrders []GiftOrderDTO     `json:"gift_orders"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// RedeemVoucherRequest - Đổi voucher; mã không phân biệt hoa thường và dấu gạch
+type RedeemVoucherRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+// InvoiceDTO - Hóa đơn của một đơn hàng đã thanh toán. Giá đã gồm VAT; số
+// tiền tính bằng đơn vị nhỏ nhất của currency
+type InvoiceDTO struct {
+	ID           string           `json:"id"`
+	OrderID      string           `json:"order_id"`
+	InvoiceNo    string           `json:"invoice_no"`
+	IssuedAt     time.Time        `json:"issued_at"`
+	BuyerName    string           `json:"buyer_name"`
+	BuyerEmail   string           `json:"buyer_email"`
+	BuyerCompany *string          `json:"buyer_company,omitempty"`
+	BuyerTaxCode *string          `json:"buyer_tax_code,omitempty"`
+	BuyerAddress *string          `json:"buyer_address,omitempty"`
+	Currency     string           `json:"currency"`
+	CouponCode   *string          `json:"coupon_code,omitempty"`
+	Subtotal     int64            `json:"subtotal"`
+	Discount     int64            `json:"discount"`
+	VATRate      int64            `json:"vat_rate"` // basis point (1000 = 10%)
+	NetAmount    int64            `json:"net_amount"`
+	VATAmount    int64            `json:"vat_amount"`
+	Total        int64            `json:"total"`
+	Items        []InvoiceItemDTO `json:"items"`
+}
+
+// InvoiceItemDTO - Một dòng hàng trên hóa đơn; dòng giảm giá đơn hàng có số tiền âm
+type InvoiceItemDTO struct {
+	LineNo      int     `json:"line_no"`
+	CourseID    *string `json:"course_id,omitempty"`
+	Description string  `json:"description"`
+	UnitPrice   int64   `json:"unit_price"`
+	Discount    int64   `json:"discount"`
+	NetAmount   int64   `json:"net_amount"`
+	VATAmount   int64   `json:"vat_amount"`
+	Amount      int64   `json:"amount"`
+}
+
+// InvoiceQuery - Định dạng file hóa đơn khi tải về
+type InvoiceQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=pdf html"`
+}
+
+// IssueInvoiceRequest - Xuất hóa đơn, kèm thông tin công ty nếu người mua là
+// doanh nghiệp. Tên công ty, mã số thuế và địa chỉ đi cùng nhau; bỏ trống cả
+// ba là hóa đơn cá nhân
+type IssueInvoiceRequest struct {
+	CompanyName string `json:"company_name" binding:"required_with=TaxCode Address,max=255"`
+	TaxCode     string `json:"tax_code" binding:"required_with=CompanyName Address,max=20"`
+	Address     string `json:"address" binding:"required_with=CompanyName TaxCode"`
+}
@@ -0,0 +1,113 @@
+package dto
+
+import "time"
+
+// RevenueShareRuleDTO - Tỉ lệ doanh thu giảng viên nhận được.
+// InstructorID / CouponSource rỗng là áp dụng cho mọi giá trị
+type RevenueShareRuleDTO struct {
+	ID              string    `json:"id"`
+	InstructorID    *string   `json:"instructor_id"`
+	CouponSource    *string   `json:"coupon_source"`    // 'none', 'platform' hoặc 'instructor'
+	InstructorShare float64   `json:"instructor_share"` // 0..1
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CreateRevenueShareRuleRequest - Request tạo quy tắc chia doanh thu
+type CreateRevenueShareRuleRequest struct {
+	InstructorID    *string  `json:"instructor_id,omitempty" binding:"omitempty,uuid"`
+	CouponSource    *string  `json:"coupon_source,omitempty" binding:"omitempty,oneof=none platform instructor"`
+	InstructorShare *float64 `json:"instructor_share" binding:"required,gte=0,lte=1"`
+}
+
+// UpdateRevenueShareRuleRequest - Chỉ đổi được tỉ lệ; đổi phạm vi thì tạo quy tắc mới.
+// Tỉ lệ mới chỉ áp dụng cho đơn hàng hoàn tất sau thời điểm cập nhật
+type UpdateRevenueShareRuleRequest struct {
+	InstructorShare *float64 `json:"instructor_share" binding:"required,gte=0,lte=1"`
+}
+
+// PayoutDTO - Khoản chi trả cho một giảng viên trong một đợt
+type PayoutDTO struct {
+	ID             string     `json:"id"`
+	BatchID        string     `json:"batch_id"`
+	InstructorID   string     `json:"instructor_id"`
+	InstructorName string     `json:"instructor_name"`
+	Amount         int64      `json:"amount"` // đơn vị nhỏ nhất của currency
+	Currency       string     `json:"currency"`
+	Status         string     `json:"status"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PayoutBatchDTO - Đợt chi trả
+type PayoutBatchDTO struct {
+	ID             string          `json:"id"`
+	Status         string          `json:"status"` // pending, processing, paid, failed, cancelled
+	EligibleBefore time.Time       `json:"eligible_before"`
+	CreatedBy      *string         `json:"created_by,omitempty"`
+	PayoutCount    int             `json:"payout_count"`
+	Totals         []CurrencyTotal `json:"totals"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	Payouts []PayoutDTO `json:"payouts,omitempty"`
+}
+
+// CurrencyTotal - Tổng tiền theo từng loại tiền tệ, tính bằng đơn vị nhỏ nhất
+type CurrencyTotal struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// PayoutBatchListResponse - Response danh sách đợt chi trả
+type PayoutBatchListResponse struct {
+	Batches    []PayoutBatchDTO   `json:"batches"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// UpdatePayoutStatusRequest - Request đổi trạng thái đợt chi trả / khoản chi trả
+type UpdatePayoutStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=processing paid failed cancelled"`
+}
+
+// StatementQuery - Query string của sao kê giảng viên (mặc định 30 ngày gần nhất)
+type StatementQuery struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// InstructorStatement - Sao kê thu nhập của giảng viên trong khoảng thời gian
+type InstructorStatement struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Balances []StatementBalance `json:"balances"`
+	Entries  []StatementEntry   `json:"entries"`
+}
+
+// StatementBalance - Số dư theo tiền tệ, tính bằng đơn vị nhỏ nhất (VND: đồng,
+// USD/EUR: cent). Số dương là số tiền nền tảng nợ giảng viên
+type StatementBalance struct {
+	Currency       string `json:"currency"`
+	OpeningBalance int64  `json:"opening_balance"`
+	Earnings       int64  `json:"earnings"` // doanh thu bán hàng trong kỳ
+	Refunds        int64  `json:"refunds"`  // doanh thu bị trừ do hoàn tiền (số âm)
+	Payouts        int64  `json:"payouts"`  // đã chi trả, sau khi trừ khoản chi bị hủy (số âm)
+	ClosingBalance int64  `json:"closing_balance"`
+	Available      int64  `json:"available"` // số dư hiện tại đã qua thời gian giữ
+	OnHold         int64  `json:"on_hold"`   // số dư hiện tại còn trong thời gian giữ
+}
+
+// StatementEntry - Một dòng sao kê
+type StatementEntry struct {
+	TransactionID   string    `json:"transaction_id"`
+	Kind            string    `json:"kind"`   // sale, refund, payout, payout_reversal
+	Amount          int64     `json:"amount"` // đơn vị nhỏ nhất của tiền tệ
+	Currency        string    `json:"currency"`
+	InstructorShare *float64  `json:"instructor_share,omitempty"`
+	OrderID         *string   `json:"order_id,omitempty"`
+	CourseID        *string   `json:"course_id,omitempty"`
+	CourseTitle     *string   `json:"course_title,omitempty"`
+	PayoutID        *string   `json:"payout_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+// UpdateOrderStatusRequest - Admin ghi nhận kết quả thanh toán của một đơn
+// đang chờ sau khi đối soát với cổng thanh toán. Hoàn tất đơn phải có mã
+// giao dịch của cổng; lý do được lưu cùng người ghi nhận
+type UpdateOrderStatusRequest struct {
+	Status        string `json:"status" binding:"required,oneof=completed failed"`
+	TransactionID string `json:"transaction_id" binding:"required_if=Status completed,max=255"`
+	Reason        string `json:"reason" binding:"required,max=500"`
+}
+
+// OrderStatusDTO - Trạng thái thanh toán của đơn; số tiền tính bằng đơn vị
+// nhỏ nhất của currency
+type OrderStatusDTO struct {
+	ID                 string  `json:"id"`
+	Kind               string  `json:"kind"` // purchase, gift, seats
+	PaymentStatus      string  `json:"payment_status"`
+	Currency           string  `json:"currency"`
+	FinalAmount        int64   `json:"final_amount"`
+	TransactionID      *string `json:"transaction_id,omitempty"`
+	EnrollmentsCreated int     `json:"enrollments_created"` // ghi danh tạo cho đơn mua thường
+}
@@ -0,0 +1,221 @@
+package dto
+
+import "time"
+
+// OrganizationDTO - Tổ chức; role là vai trò của người đang đăng nhập (không
+// có với admin không phải thành viên)
+type OrganizationDTO struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Role        *string   `json:"role,omitempty"` // owner, manager hoặc learner
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateOrganizationRequest - Tạo tổ chức; người tạo là owner
+type CreateOrganizationRequest struct {
+	Name        string  `json:"name" binding:"required,max=200"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateOrganizationRequest - Cập nhật tổ chức (owner)
+type UpdateOrganizationRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,max=200"`
+	Description *string `json:"description,omitempty"`
+}
+
+// OrganizationListResponse - Response danh sách tổ chức
+type OrganizationListResponse struct {
+	Organizations []OrganizationDTO  `json:"organizations"`
+	Pagination    PaginationResponse `json:"pagination"`
+}
+
+// OrganizationMemberDTO - Thành viên của tổ chức
+type OrganizationMemberDTO struct {
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InviteOrganizationMemberRequest - Mời người dùng vào tổ chức theo email;
+// họ thành thành viên khi chấp nhận. Manager chỉ mời được learner
+type InviteOrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Role  string `json:"role,omitempty" binding:"omitempty,oneof=owner manager learner"` // mặc định learner
+}
+
+// OrganizationInvitationDTO - Lời mời vào tổ chức đang chờ người được mời
+// chấp nhận hoặc từ chối
+type OrganizationInvitationDTO struct {
+	ID               string    `json:"id"`
+	OrganizationID   string    `json:"organization_id"`
+	OrganizationName string    `json:"organization_name"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// OrganizationInvitationListResponse - Response danh sách lời mời
+type OrganizationInvitationListResponse struct {
+	Invitations []OrganizationInvitationDTO `json:"invitations"`
+}
+
+// UpdateOrganizationMemberRequest - Đổi vai trò thành viên (owner)
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner manager learner"`
+}
+
+// OrganizationMemberListQuery - Lọc thành viên
+type OrganizationMemberListQuery struct {
+	PaginationQuery
+	Role string `form:"role" binding:"omitempty,oneof=owner manager learner"`
+}
+
+// OrganizationMemberListResponse - Response danh sách thành viên
+type OrganizationMemberListResponse struct {
+	Members    []OrganizationMemberDTO `json:"members"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// PurchaseSeatsRequest - Mua suất học cho tổ chức; tạo một đơn pending
+type PurchaseSeatsRequest struct {
+	Items    []SeatItemRequest `json:"items" binding:"required,min=1,max=20,dive"`
+	Currency string            `json:"currency,omitempty" binding:"omitempty,currency"` // mặc định VND
+}
+
+// SeatItemRequest - Số suất của một khóa học
+type SeatItemRequest struct {
+	CourseID string `json:"course_id" binding:"required,uuid"`
+	Quantity int    `json:"quantity" binding:"required,min=1,max=1000"`
+}
+
+// SeatOrderDTO - Đơn mua suất; số tiền tính bằng đơn vị nhỏ nhất của currency
+type SeatOrderDTO struct {
+	ID             string        `json:"id"`
+	PaymentStatus  string        `json:"payment_status"`
+	Currency       string        `json:"currency"`
+	TotalAmount    int64         `json:"total_amount"`
+	DiscountAmount int64         `json:"discount_amount"`
+	FinalAmount    int64         `json:"final_amount"`
+	SeatPools      []SeatPoolDTO `json:"seat_pools"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// SeatPoolDTO - Suất học của một khóa học trong một đơn
+type SeatPoolDTO struct {
+	ID             string    `json:"id"`
+	OrderID        string    `json:"order_id"`
+	CourseID       string    `json:"course_id"`
+	CourseTitle    string    `json:"course_title"`
+	Status         string    `json:"status"` // pending_payment, available, cancelled
+	Seats          int       `json:"seats"`
+	SeatsUsed      int       `json:"seats_used"`
+	SeatsAvailable int       `json:"seats_available"` // chỉ tính suất của đơn đã thanh toán
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// SeatPoolListQuery - Lọc suất học theo khóa học
+type SeatPoolListQuery struct {
+	CourseID string `form:"course_id" binding:"omitempty,uuid"`
+}
+
+// CourseAssignmentDTO - Khóa học giao cho một thành viên, kèm tiến độ học
+type CourseAssignmentDTO struct {
+	ID                 string     `json:"id"`
+	OrganizationID     string     `json:"organization_id"`
+	UserID             string     `json:"user_id"`
+	UserEmail          string     `json:"user_email"`
+	CourseID           string     `json:"course_id"`
+	CourseTitle        string     `json:"course_title"`
+	DueDate            *string    `json:"due_date,omitempty"` // YYYY-MM-DD
+	Status             string     `json:"status"`             // not_started, in_progress, completed
+	Overdue            bool       `json:"overdue"`
+	ProgressPercentage float64    `json:"progress_percentage"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	EnrollmentID       *string    `json:"enrollment_id,omitempty"`
+	UsedSeat           bool       `json:"used_seat"` // false nếu thành viên đã ghi danh từ trước
+	AssignedBy         *string    `json:"assigned_by,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// CreateCourseAssignmentRequest - Giao một khóa học cho các thành viên.
+// Thành viên chưa ghi danh dùng một suất đã thanh toán của tổ chức
+type CreateCourseAssignmentRequest struct {
+	CourseID string   `json:"course_id" binding:"required,uuid"`
+	UserIDs  []string `json:"user_ids" binding:"required,min=1,max=500,dive,uuid"`
+	DueDate  *string  `json:"due_date,omitempty" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// UpdateCourseAssignmentRequest - Đổi hạn hoàn thành; bỏ trống due_date để gỡ hạn
+type UpdateCourseAssignmentRequest struct {
+	DueDate *string `json:"due_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// CourseAssignmentListQuery - Lọc khóa học được giao; learner chỉ thấy của mình
+type CourseAssignmentListQuery struct {
+	PaginationQuery
+	UserID   string `form:"user_id" binding:"omitempty,uuid"`
+	CourseID string `form:"course_id" binding:"omitempty,uuid"`
+	Status   string `form:"status" binding:"omitempty,oneof=not_started in_progress completed overdue"`
+}
+
+// CourseAssignmentListResponse - Response danh sách khóa học được giao
+type CourseAssignmentListResponse struct {
+	Assignments []CourseAssignmentDTO `json:"assignments"`
+	Pagination  PaginationResponse    `json:"pagination"`
+}
+
+// OrganizationReportQuery - Lọc báo cáo tiến độ
+type OrganizationReportQuery struct {
+	UserID   string `form:"user_id" binding:"omitempty,uuid"`
+	CourseID string `form:"course_id" binding:"omitempty,uuid"`
+}
+
+// OrganizationProgressReport - Tiến độ học của thành viên trên các khóa học
+// được giao; average_progress là trung bình enrollments.progress_percentage
+type OrganizationProgressReport struct {
+	Summary ProgressSummaryDTO  `json:"summary"`
+	Members []MemberProgressDTO `json:"members"`
+	Courses []CourseProgressDTO `json:"courses"`
+}
+
+// ProgressSummaryDTO - Tổng hợp trên toàn bộ khóa học được giao
+type ProgressSummaryDTO struct {
+	Members         int     `json:"members"` // thành viên có khóa học được giao
+	Assignments     int     `json:"assignments"`
+	NotStarted      int     `json:"not_started"`
+	InProgress      int     `json:"in_progress"`
+	Completed       int     `json:"completed"`
+	Overdue         int     `json:"overdue"`
+	AverageProgress float64 `json:"average_progress"`
+}
+
+// MemberProgressDTO - Tiến độ của một thành viên
+type MemberProgressDTO struct {
+	UserID          string  `json:"user_id"`
+	Email           string  `json:"email"`
+	FirstName       string  `json:"first_name"`
+	LastName        string  `json:"last_name"`
+	Role            string  `json:"role"`
+	Assignments     int     `json:"assignments"`
+	Completed       int     `json:"completed"`
+	Overdue         int     `json:"overdue"`
+	AverageProgress float64 `json:"average_progress"`
+}
+
+// CourseProgressDTO - Tiến độ của thành viên trên một khóa học
+type CourseProgressDTO struct {
+	CourseID        string  `json:"course_id"`
+	CourseTitle     string  `json:"course_title"`
+	Assignments     int     `json:"assignments"`
+	Completed       int     `json:"completed"`
+	Overdue         int     `json:"overdue"`
+	AverageProgress float64 `json:"average_progress"`
+}
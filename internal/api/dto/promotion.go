@@ -0,0 +1,111 @@
+package dto
+
+import "time"
+
+// PromotionDTO - Chương trình khuyến mãi theo thời gian
+type PromotionDTO struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Description   *string              `json:"description,omitempty"`
+	DiscountType  string               `json:"discount_type"`      // 'percentage' hoặc 'fixed_price'
+	DiscountValue int64                `json:"discount_value"`     // percentage: basis point (1000 = 10%), fixed_price: giá bán theo đơn vị nhỏ nhất của currency
+	Currency      *string              `json:"currency,omitempty"` // chỉ có với fixed_price
+	Priority      int                  `json:"priority"`
+	CouponPolicy  string               `json:"coupon_policy"` // 'stack', 'exclusive' hoặc 'best_of'
+	StartsAt      time.Time            `json:"starts_at"`
+	EndsAt        time.Time            `json:"ends_at"`
+	IsActive      bool                 `json:"is_active"`
+	Status        string               `json:"status"` // 'scheduled', 'running', 'ended' hoặc 'inactive'
+	Targets       []PromotionTargetDTO `json:"targets"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// PromotionTargetDTO - Khóa học, danh mục (gồm danh mục con) hoặc giảng viên
+// được khuyến mãi
+type PromotionTargetDTO struct {
+	Type string `json:"type" binding:"required,oneof=course category instructor"`
+	ID   string `json:"id" binding:"required,uuid"`
+}
+
+// CreatePromotionRequest - Request tạo khuyến mãi. Khuyến mãi fixed_price cần currency
+type CreatePromotionRequest struct {
+	Name          string               `json:"name" binding:"required,max=200"`
+	Description   *string              `json:"description,omitempty"`
+	DiscountType  string               `json:"discount_type" binding:"required,oneof=percentage fixed_price"`
+	DiscountValue *int64               `json:"discount_value" binding:"required,gte=0"`
+	Currency      *string              `json:"currency,omitempty" binding:"required_if=DiscountType fixed_price,omitempty,currency"`
+	Priority      int                  `json:"priority"`
+	CouponPolicy  string               `json:"coupon_policy,omitempty" binding:"omitempty,oneof=stack exclusive best_of"` // mặc định theo cấu hình
+	StartsAt      time.Time            `json:"starts_at" binding:"required"`
+	EndsAt        time.Time            `json:"ends_at" binding:"required,gtfield=StartsAt"`
+	IsActive      *bool                `json:"is_active,omitempty"`
+	Targets       []PromotionTargetDTO `json:"targets" binding:"required,min=1,max=500,dive"`
+}
+
+// UpdatePromotionRequest - Request cập nhật khuyến mãi; targets thay toàn bộ
+// phạm vi cũ. Đổi loại giảm giá phải gửi kèm giá trị (và currency với fixed_price)
+type UpdatePromotionRequest struct {
+	Name          *string              `json:"name,omitempty" binding:"omitempty,max=200"`
+	Description   *string              `json:"description,omitempty"`
+	DiscountType  *string              `json:"discount_type,omitempty" binding:"omitempty,oneof=percentage fixed_price"`
+	DiscountValue *int64               `json:"discount_value,omitempty" binding:"required_with=DiscountType Currency,omitempty,gte=0"`
+	Currency      *string              `json:"currency,omitempty" binding:"omitempty,currency"`
+	Priority      *int                 `json:"priority,omitempty"`
+	CouponPolicy  *string              `json:"coupon_policy,omitempty" binding:"omitempty,oneof=stack exclusive best_of"`
+	StartsAt      *time.Time           `json:"starts_at,omitempty"`
+	EndsAt        *time.Time           `json:"ends_at,omitempty"`
+	IsActive      *bool                `json:"is_active,omitempty"`
+	Targets       []PromotionTargetDTO `json:"targets,omitempty" binding:"omitempty,min=1,max=500,dive"`
+}
+
+// PromotionListQuery - Lọc danh sách khuyến mãi theo trạng thái
+type PromotionListQuery struct {
+	PaginationQuery
+	Status string `form:"status" binding:"omitempty,oneof=scheduled running ended inactive"`
+}
+
+// PromotionListResponse - Response danh sách khuyến mãi
+type PromotionListResponse struct {
+	Promotions []PromotionDTO     `json:"promotions"`
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// AppliedPromotionDTO - Khuyến mãi đang quyết định giá bán của khóa học
+type AppliedPromotionDTO struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	EndsAt       time.Time `json:"ends_at"`
+	CouponPolicy string    `json:"coupon_policy"`
+}
+
+// CheckoutQuoteRequest - Tính giá giỏ hàng trước khi thanh toán. Mọi khóa học
+// phải có giá ở currency
+type CheckoutQuoteRequest struct {
+	CourseIDs  []string `json:"course_ids" binding:"required,min=1,max=50,dive,uuid"`
+	Currency   string   `json:"currency,omitempty" binding:"omitempty,currency"` // mặc định VND
+	CouponCode string   `json:"coupon_code,omitempty" binding:"omitempty,max=50"`
+}
+
+// CheckoutQuoteDTO - Giá giỏ hàng; số tiền tính bằng đơn vị nhỏ nhất của currency.
+// total = subtotal - promotion_discount - coupon_discount
+type CheckoutQuoteDTO struct {
+	Currency          string                 `json:"currency"`
+	Items             []CheckoutQuoteItemDTO `json:"items"`
+	Subtotal          int64                  `json:"subtotal"`           // tổng giá niêm yết
+	PromotionDiscount int64                  `json:"promotion_discount"` // giảm từ giá giảm và khuyến mãi
+	CouponCode        *string                `json:"coupon_code,omitempty"`
+	CouponDiscount    int64                  `json:"coupon_discount"`
+	Total             int64                  `json:"total"`
+}
+
+// CheckoutQuoteItemDTO - Một khóa học trong giỏ hàng
+type CheckoutQuoteItemDTO struct {
+	CourseID       string               `json:"course_id"`
+	Title          string               `json:"title"`
+	ListPrice      int64                `json:"list_price"`
+	Price          int64                `json:"price"` // giá bán trước coupon
+	Promotion      *AppliedPromotionDTO `json:"promotion,omitempty"`
+	CouponDiscount int64                `json:"coupon_discount"`
+	Total          int64                `json:"total"`
+}
@@ -0,0 +1,132 @@
+package dto
+
+import "time"
+
+// SubscriptionPlanDTO - Gói thuê bao; price tính bằng đơn vị nhỏ nhất của currency
+type SubscriptionPlanDTO struct {
+	ID              string                    `json:"id"`
+	Name            string                    `json:"name"`
+	Description     *string                   `json:"description,omitempty"`
+	BillingInterval string                    `json:"billing_interval"` // 'month' hoặc 'year'
+	IntervalCount   int                       `json:"interval_count"`
+	Price           int64                     `json:"price"`
+	Currency        string                    `json:"currency"`
+	Scope           string                    `json:"scope"` // 'all', 'categories' hoặc 'courses'
+	Items           []SubscriptionPlanItemDTO `json:"items"`
+	IsActive        bool                      `json:"is_active"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+}
+
+// SubscriptionPlanItemDTO - Danh mục (gồm danh mục con) hoặc khóa học thuộc gói
+type SubscriptionPlanItemDTO struct {
+	Type string `json:"type" binding:"required,oneof=category course"`
+	ID   string `json:"id" binding:"required,uuid"`
+}
+
+// CreateSubscriptionPlanRequest - Request tạo gói thuê bao. Gói scope
+// categories/courses cần items cùng loại; gói all không có items
+type CreateSubscriptionPlanRequest struct {
+	Name            string                    `json:"name" binding:"required,max=200"`
+	Description     *string                   `json:"description,omitempty"`
+	BillingInterval string                    `json:"billing_interval" binding:"required,oneof=month year"`
+	IntervalCount   *int                      `json:"interval_count,omitempty" binding:"omitempty,min=1,max=12"` // mặc định 1
+	Price           int64                     `json:"price" binding:"required,gt=0"`
+	Currency        string                    `json:"currency,omitempty" binding:"omitempty,currency"` // mặc định VND
+	Scope           string                    `json:"scope" binding:"required,oneof=all categories courses"`
+	Items           []SubscriptionPlanItemDTO `json:"items,omitempty" binding:"omitempty,max=1000,dive"`
+	IsActive        *bool                     `json:"is_active,omitempty"`
+}
+
+// UpdateSubscriptionPlanRequest - Request cập nhật gói thuê bao; items thay
+// toàn bộ danh sách cũ. Giá mới áp dụng từ kỳ gia hạn tiếp theo; chu kỳ
+// thanh toán không đổi được khi đã có người thuê bao
+type UpdateSubscriptionPlanRequest struct {
+	Name        *string                   `json:"name,omitempty" binding:"omitempty,max=200"`
+	Description *string                   `json:"description,omitempty"`
+	Price       *int64                    `json:"price,omitempty" binding:"omitempty,gt=0"`
+	Scope       *string                   `json:"scope,omitempty" binding:"omitempty,oneof=all categories courses"`
+	Items       []SubscriptionPlanItemDTO `json:"items,omitempty" binding:"omitempty,max=1000,dive"`
+	IsActive    *bool                     `json:"is_active,omitempty"`
+}
+
+// SubscriptionPlanListQuery - Lọc gói thuê bao; user thường chỉ thấy gói đang bán
+type SubscriptionPlanListQuery struct {
+	PaginationQuery
+	IsActive *bool `form:"is_active"`
+}
+
+// SubscriptionPlanListResponse - Response danh sách gói thuê bao
+type SubscriptionPlanListResponse struct {
+	Plans      []SubscriptionPlanDTO `json:"plans"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+// SubscriptionDTO - Thuê bao của user. access_until là thời điểm mất quyền
+// học (cuối kỳ, hoặc hết thời gian gia hạn khi past_due)
+type SubscriptionDTO struct {
+	ID                 string                  `json:"id"`
+	UserID             string                  `json:"user_id"`
+	Plan               SubscriptionPlanRefDTO  `json:"plan"`
+	Status             string                  `json:"status"` // incomplete, active, past_due, cancelled, expired
+	CurrentPeriodStart *time.Time              `json:"current_period_start,omitempty"`
+	CurrentPeriodEnd   *time.Time              `json:"current_period_end,omitempty"`
+	CancelAtPeriodEnd  bool                    `json:"cancel_at_period_end"`
+	AccessUntil        *time.Time              `json:"access_until,omitempty"`
+	CancelledAt        *time.Time              `json:"cancelled_at,omitempty"`
+	EndedAt            *time.Time              `json:"ended_at,omitempty"`
+	PendingPayment     *SubscriptionPaymentDTO `json:"pending_payment,omitempty"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
+}
+
+// SubscriptionPlanRefDTO - Tóm tắt gói của một thuê bao
+type SubscriptionPlanRefDTO struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	BillingInterval string `json:"billing_interval"`
+	IntervalCount   int    `json:"interval_count"`
+}
+
+// SubscriptionPaymentDTO - Khoản thanh toán cho một kỳ thuê bao
+type SubscriptionPaymentDTO struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	PeriodStart    time.Time  `json:"period_start"`
+	PeriodEnd      time.Time  `json:"period_end"`
+	Amount         int64      `json:"amount"`
+	Currency       string     `json:"currency"`
+	Status         string     `json:"status"` // pending, paid, failed, void
+	TransactionID  *string    `json:"transaction_id,omitempty"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// SubscriptionListResponse - Response danh sách thuê bao
+type SubscriptionListResponse struct {
+	Subscriptions []SubscriptionDTO  `json:"subscriptions"`
+	Pagination    PaginationResponse `json:"pagination"`
+}
+
+// CreateSubscriptionRequest - Đăng ký gói thuê bao; thuê bao hiệu lực khi
+// khoản thanh toán đầu tiên được xác nhận
+type CreateSubscriptionRequest struct {
+	PlanID string `json:"plan_id" binding:"required,uuid"`
+}
+
+// UpdateSubscriptionPaymentStatusRequest - Cổng thanh toán báo kết quả một
+// khoản đang chờ
+type UpdateSubscriptionPaymentStatusRequest struct {
+	Status        string  `json:"status" binding:"required,oneof=paid failed"`
+	TransactionID *string `json:"transaction_id,omitempty" binding:"omitempty,max=255"`
+}
+
+// CourseAccessDTO - Quyền học khóa học của user đang đăng nhập
+type CourseAccessDTO struct {
+	CourseID       string     `json:"course_id"`
+	HasAccess      bool       `json:"has_access"`
+	Source         *string    `json:"source,omitempty"` // enrollment, subscription, instructor hoặc admin
+	EnrollmentID   *string    `json:"enrollment_id,omitempty"`
+	SubscriptionID *string    `json:"subscription_id,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"` // chỉ có với quyền từ thuê bao
+}
@@ -0,0 +1,435 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/export"
+)
+
+// Mặc định số dòng của báo cáo dạng danh sách khi trả JSON
+const (
+	adminReportDefaultLimit = 100
+	adminDashboardTopLimit  = 5
+	exportFlushEvery        = 500
+)
+
+// AdminAnalyticsHandler phục vụ /admin/analytics: KPI toàn nền tảng và các
+// báo cáo có thể xuất CSV/XLSX. Số liệu đọc trực tiếp từ các bảng gốc.
+type AdminAnalyticsHandler struct {
+	db *sql.DB
+}
+
+func NewAdminAnalyticsHandler(db *sql.DB) *AdminAnalyticsHandler {
+	return &AdminAnalyticsHandler{db: db}
+}
+
+// adminReport là một báo cáo: tên cột theo thứ tự SELECT và hàm dựng tham số
+// cho query (mỗi query chỉ dùng các tham số nó cần).
+type adminReport struct {
+	title   string
+	columns []string
+	query   string
+	args    func(rng dto.AnalyticsRange, limit interface{}) []interface{}
+}
+
+// Lọc theo ngày giờ Việt Nam, vẫn dùng được index trên cột timestamp
+func inVNDateRange(col string) string {
+	return col + ` >= ($1::date::timestamp AT TIME ZONE 'Asia/Ho_Chi_Minh') AND ` +
+		col + ` < (($2::date + 1)::timestamp AT TIME ZONE 'Asia/Ho_Chi_Minh')`
+}
+
+func vnBucket(col string) string {
+	return `to_char(date_trunc($3, ` + col + ` AT TIME ZONE 'Asia/Ho_Chi_Minh'), 'YYYY-MM-DD')`
+}
+
+// Giá trị coupon theo đơn vị của API: basis point hoặc đơn vị nhỏ nhất
+const couponValue = `CASE WHEN cp.discount_type = 'percentage' THEN (cp.discount_value * 100)::bigint
+				   ELSE minor_units(cp.discount_value, cp.currency) END`
+
+func bucketedArgs(rng dto.AnalyticsRange, _ interface{}) []interface{} {
+	return []interface{}{rng.From, rng.To, rng.Granularity}
+}
+
+func limitedArgs(rng dto.AnalyticsRange, limit interface{}) []interface{} {
+	return []interface{}{rng.From, rng.To, limit}
+}
+
+// Doanh thu chỉ tính đơn đã thanh toán; đơn refunded vẫn thuộc GMV. Số tiền
+// luôn tách theo tiền tệ của đơn, không cộng lẫn VND với USD/EUR, và tính bằng
+// đơn vị nhỏ nhất của tiền tệ đó
+var adminReports = map[string]adminReport{
+	"users": {
+		title:   "New users",
+		columns: []string{"bucket", "role", "new_users"},
+		query: `
+			SELECT ` + vnBucket("u.created_at") + ` AS bucket, u.role, COUNT(*)::bigint
+			FROM users u
+			WHERE ` + inVNDateRange("u.created_at") + `
+			GROUP BY bucket, u.role
+			ORDER BY bucket, u.role`,
+		args: bucketedArgs,
+	},
+	"revenue": {
+		title:   "Revenue",
+		columns: []string{"bucket", "currency", "orders", "gmv", "discounts", "refunds", "net_revenue"},
+		query: `
+			SELECT ` + vnBucket("o.created_at") + ` AS bucket, o.currency,
+				   COUNT(*)::bigint,
+				   minor_units(SUM(o.total_amount), o.currency),
+				   minor_units(SUM(COALESCE(o.discount_amount, 0)), o.currency),
+				   minor_units(COALESCE(SUM(o.final_amount) FILTER (WHERE o.payment_status = 'refunded'), 0), o.currency),
+				   minor_units(COALESCE(SUM(o.final_amount) FILTER (WHERE o.payment_status = 'completed'), 0), o.currency)
+			FROM orders o
+			WHERE o.payment_status IN ('completed', 'refunded') AND ` + inVNDateRange("o.created_at") + `
+			GROUP BY bucket, o.currency
+			ORDER BY bucket, o.currency`,
+		args: bucketedArgs,
+	},
+	"top-courses": {
+		title:   "Top courses",
+		columns: []string{"course_id", "title", "instructor", "currency", "sales", "revenue"},
+		query: `
+			SELECT c.id::text, c.title, u.first_name || ' ' || u.last_name, o.currency,
+				   COUNT(*)::bigint AS sales, minor_units(SUM(oi.final_price), o.currency) AS revenue
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			JOIN courses c ON c.id = oi.course_id
+			JOIN users u ON u.id = c.instructor_id
+			WHERE o.payment_status = 'completed' AND ` + inVNDateRange("o.created_at") + `
+			GROUP BY c.id, u.id, o.currency
+			ORDER BY revenue DESC, sales DESC
+			LIMIT $3`,
+		args: limitedArgs,
+	},
+	"top-categories": {
+		title:   "Top categories",
+		columns: []string{"category_id", "name", "currency", "courses_sold", "sales", "revenue"},
+		query: `
+			SELECT cat.id::text, cat.name, o.currency, COUNT(DISTINCT oi.course_id)::bigint,
+				   COUNT(*)::bigint AS sales, minor_units(SUM(oi.final_price), o.currency) AS revenue
+			FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			JOIN courses c ON c.id = oi.course_id
+			JOIN categories cat ON cat.id = c.category_id
+			WHERE o.payment_status = 'completed' AND ` + inVNDateRange("o.created_at") + `
+			GROUP BY cat.id, o.currency
+			ORDER BY revenue DESC, sales DESC
+			LIMIT $3`,
+		args: limitedArgs,
+	},
+	"coupons": {
+		title: "Coupons",
+		columns: []string{"coupon_id", "code", "discount_type", "discount_value", "is_active",
+			"used_count", "currency", "orders", "discounts", "net_revenue", "average_order_value"},
+		query: `
+			SELECT cp.id::text, cp.code, cp.discount_type, ` + couponValue + `, cp.is_active,
+				   COALESCE(cp.used_count, 0)::bigint, o.currency,
+				   COUNT(o.id)::bigint AS orders,
+				   COALESCE(minor_units(SUM(o.discount_amount), o.currency), 0),
+				   COALESCE(minor_units(SUM(o.final_amount), o.currency), 0) AS net_revenue,
+				   COALESCE(minor_units(AVG(o.final_amount), o.currency), 0)
+			FROM coupons cp
+			LEFT JOIN orders o ON o.coupon_id = cp.id AND o.payment_status = 'completed'
+				 AND ` + inVNDateRange("o.created_at") + `
+			GROUP BY cp.id, o.currency
+			ORDER BY orders DESC, net_revenue DESC, cp.code
+			LIMIT $3`,
+		args: limitedArgs,
+	},
+	// Hàng chờ duyệt hiện tại, không phụ thuộc khoảng thời gian
+	"pending": {
+		title:   "Pending approvals",
+		columns: []string{"kind", "id", "name", "submitted_by", "submitted_at"},
+		query: `
+			SELECT 'course', c.id::text, c.title, u.email::text, c.updated_at AS submitted_at
+			FROM courses c
+			JOIN users u ON u.id = c.instructor_id
+			WHERE c.status = 'pending'
+			UNION ALL
+			SELECT 'instructor', p.id::text, u.first_name || ' ' || u.last_name, u.email::text, p.created_at
+			FROM instructor_profiles p
+			JOIN users u ON u.id = p.user_id
+			WHERE p.is_approved = FALSE
+			ORDER BY submitted_at
+			LIMIT $1`,
+		args: func(_ dto.AnalyticsRange, limit interface{}) []interface{} {
+			return []interface{}{limit}
+		},
+	},
+}
+
+// reportQuery đọc query string chung của dashboard và báo cáo.
+func (h *AdminAnalyticsHandler) reportQuery(c *gin.Context) (dto.AdminReportQuery, dto.AnalyticsRange, bool) {
+	var query dto.AdminReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return query, dto.AnalyticsRange{}, false
+	}
+	rng, apiErr := parseAnalyticsRange(query.From, query.To, query.Granularity)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return query, dto.AnalyticsRange{}, false
+	}
+	return query, rng, true
+}
+
+// runReport chạy báo cáo và gọi fn cho từng dòng, theo thứ tự rep.columns.
+// onStart được gọi sau khi query thành công và trước dòng đầu tiên, để lỗi
+// query vẫn trả về được dưới dạng JSON.
+func (h *AdminAnalyticsHandler) runReport(ctx context.Context, rep adminReport, rng dto.AnalyticsRange,
+	limit interface{}, onStart func() error, fn func(row []interface{}) error) error {
+	rows, err := h.db.QueryContext(ctx, rep.query, rep.args(rng, limit)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if onStart != nil {
+		if err := onStart(); err != nil {
+			return err
+		}
+	}
+
+	dest := make([]interface{}, len(rep.columns))
+	for rows.Next() {
+		row := make([]interface{}, len(rep.columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// collectReport trả về toàn bộ dòng của báo cáo dưới dạng object.
+func (h *AdminAnalyticsHandler) collectReport(ctx context.Context, rep adminReport, rng dto.AnalyticsRange, limit int) ([]dto.ReportRow, error) {
+	result := []dto.ReportRow{}
+	err := h.runReport(ctx, rep, rng, limit, nil, func(row []interface{}) error {
+		r := make(dto.ReportRow, len(row))
+		for i, col := range rep.columns {
+			r[col] = row[i]
+		}
+		result = append(result, r)
+		return nil
+	})
+	return result, err
+}
+
+// GET /api/admin/analytics/dashboard
+// KPI toàn nền tảng trong khoảng thời gian kèm top khóa học/danh mục và số
+// mục đang chờ duyệt.
+func (h *AdminAnalyticsHandler) GetDashboard(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+	_, rng, ok := h.reportQuery(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	dashboard := dto.AdminDashboard{
+		AnalyticsRange: dto.AnalyticsRange{From: rng.From, To: rng.To},
+		NewUsers:       map[string]int64{"student": 0, "instructor": 0, "admin": 0},
+		Revenue:        []dto.CurrencyRevenue{},
+	}
+
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT role, COUNT(*) FROM users u
+		WHERE `+inVNDateRange("u.created_at")+`
+		GROUP BY role
+	`, rng.From, rng.To)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch new users"))
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var role string
+		var count int64
+		if err := rows.Scan(&role, &count); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan new users"))
+			return
+		}
+		dashboard.NewUsers[role] = count
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch new users"))
+		return
+	}
+
+	if err := h.db.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM courses WHERE status = 'pending'),
+			   (SELECT COUNT(*) FROM instructor_profiles WHERE is_approved = FALSE)
+	`).Scan(&dashboard.PendingCourses, &dashboard.PendingInstructors); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch pending approvals"))
+		return
+	}
+
+	// Doanh thu theo tiền tệ của đơn; Orders là tổng số đơn mọi tiền tệ
+	revenueRows, err := h.db.QueryContext(ctx, `
+		SELECT o.currency, COUNT(*),
+			   minor_units(COALESCE(SUM(o.total_amount), 0), o.currency),
+			   minor_units(COALESCE(SUM(o.discount_amount), 0), o.currency),
+			   minor_units(COALESCE(SUM(o.final_amount) FILTER (WHERE o.payment_status = 'refunded'), 0), o.currency),
+			   minor_units(COALESCE(SUM(o.final_amount) FILTER (WHERE o.payment_status = 'completed'), 0), o.currency)
+		FROM orders o
+		WHERE o.payment_status IN ('completed', 'refunded') AND `+inVNDateRange("o.created_at")+`
+		GROUP BY o.currency
+		ORDER BY o.currency
+	`, rng.From, rng.To)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue"))
+		return
+	}
+	defer revenueRows.Close()
+	for revenueRows.Next() {
+		var r dto.CurrencyRevenue
+		if err := revenueRows.Scan(&r.Currency, &r.Orders, &r.GMV, &r.Discounts, &r.Refunds, &r.NetRevenue); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan revenue"))
+			return
+		}
+		dashboard.Orders += r.Orders
+		dashboard.Revenue = append(dashboard.Revenue, r)
+	}
+	if err := revenueRows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue"))
+		return
+	}
+
+	if dashboard.TopCourses, err = h.collectReport(ctx, adminReports["top-courses"], rng, adminDashboardTopLimit); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch top courses"))
+		return
+	}
+	if dashboard.TopCategories, err = h.collectReport(ctx, adminReports["top-categories"], rng, adminDashboardTopLimit); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch top categories"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Dashboard retrieved successfully",
+		Data:    dashboard,
+	})
+}
+
+// GET /api/admin/analytics/reports/:report
+func (h *AdminAnalyticsHandler) GetReport(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+	name := c.Param("report")
+	rep, found := adminReports[name]
+	if !found {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeReportNotFound, "Report not found"))
+		return
+	}
+	query, rng, ok := h.reportQuery(c)
+	if !ok {
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = adminReportDefaultLimit
+	}
+
+	rows, err := h.collectReport(c.Request.Context(), rep, rng, query.Limit)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to run report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Report retrieved successfully",
+		Data: dto.AdminReport{
+			AnalyticsRange: rng,
+			Report:         name,
+			Columns:        rep.columns,
+			Rows:           rows,
+		},
+	})
+}
+
+// GET /api/admin/analytics/reports/:report/export?format=csv|xlsx
+// Stream báo cáo ra file, không giới hạn số dòng nếu không truyền limit và
+// không bị cắt bởi server.write_timeout.
+// Lỗi xảy ra khi đã bắt đầu gửi file chỉ được ghi log (file bị cắt ngang).
+func (h *AdminAnalyticsHandler) ExportReport(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+	name := c.Param("report")
+	rep, found := adminReports[name]
+	if !found {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeReportNotFound, "Report not found"))
+		return
+	}
+	query, rng, ok := h.reportQuery(c)
+	if !ok {
+		return
+	}
+	if query.Format == "" {
+		query.Format = export.CSV
+	}
+	var limit interface{} // NULL = không giới hạn
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+
+	var w export.Writer
+	written := 0
+	start := func() error {
+		// File lớn stream lâu hơn server.write_timeout; bỏ deadline ghi cho
+		// riêng request này thay vì nâng timeout của mọi request
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			middleware.Log(c).WithError(err).Warn("Cannot clear write deadline for report export")
+		}
+		c.Header("Content-Type", export.ContentType(query.Format))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s_%s_%s.%s"`,
+			name, rng.From, rng.To, query.Format))
+		c.Status(http.StatusOK)
+
+		var err error
+		if w, err = export.New(query.Format, c.Writer, rep.title); err != nil {
+			return err
+		}
+		header := make([]interface{}, len(rep.columns))
+		for i, col := range rep.columns {
+			header[i] = col
+		}
+		return w.Write(header)
+	}
+
+	err := h.runReport(c.Request.Context(), rep, rng, limit, start, func(row []interface{}) error {
+		if err := w.Write(row); err != nil {
+			return err
+		}
+		if written++; written%exportFlushEvery == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		if !c.Writer.Written() {
+			apierror.Abort(c, apierror.Internal(err, "Failed to run report"))
+			return
+		}
+		middleware.Log(c).WithError(err).WithField("report", name).Error("Report export aborted")
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"internal/api/dto"
+	"internal/api/middleware"
+)
+
+func expectDashboardLists(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM order_items oi`).WillReturnRows(sqlmock.NewRows([]string{"course_id"}))
+	mock.ExpectQuery(`FROM order_items oi`).WillReturnRows(sqlmock.NewRows([]string{"category_id"}))
+}
+
+func TestGetDashboardRevenuePerCurrency(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "admin")
+	mock.ExpectQuery(`SELECT role, COUNT\(\*\) FROM users`).
+		WillReturnRows(sqlmock.NewRows([]string{"role", "count"}).AddRow("student", 3))
+	mock.ExpectQuery(`FROM courses WHERE status = 'pending'`).
+		WillReturnRows(sqlmock.NewRows([]string{"courses", "instructors"}).AddRow(1, 2))
+	mock.ExpectQuery(`GROUP BY o.currency`).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "orders", "gmv", "discounts", "refunds", "net"}).
+			AddRow("USD", 2, 5998, 1000, 0, 4998).
+			AddRow("VND", 5, 2500000, 0, 500000, 2000000))
+	expectDashboardLists(mock)
+
+	status, res := serve(t, http.MethodGet, "/admin/analytics/dashboard", "/admin/analytics/dashboard?from=2024-03-01&to=2024-03-31",
+		testLearnerID, "", NewAdminAnalyticsHandler(db).GetDashboard)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var dashboard dto.AdminDashboard
+	if err := json.Unmarshal(res.Data, &dashboard); err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.Orders != 7 || len(dashboard.Revenue) != 2 {
+		t.Fatalf("dashboard = %+v", dashboard)
+	}
+	if usd := dashboard.Revenue[0]; usd.Currency != "USD" || usd.Orders != 2 || usd.NetRevenue != 4998 {
+		t.Errorf("USD revenue = %+v", usd)
+	}
+	if vnd := dashboard.Revenue[1]; vnd.Currency != "VND" || vnd.NetRevenue != 2000000 {
+		t.Errorf("VND revenue = %+v", vnd)
+	}
+}
+
+// A server whose write deadline passes before the report query returns must
+// still deliver the whole export.
+func TestExportReportOutlivesWriteTimeout(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "admin")
+	mock.ExpectQuery(`FROM users u`).WillDelayFor(200 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "role", "new_users"}).AddRow("2024-03-01", "student", 3))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(middleware.UserIDKey, testLearnerID) })
+	r.GET("/admin/analytics/reports/:report/export", NewAdminAnalyticsHandler(db).ExportReport)
+
+	srv := httptest.NewUnstartedServer(r)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/admin/analytics/reports/users/export?from=2024-03-01&to=2024-03-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "2024-03-01,student,3") {
+		t.Fatalf("got %d %q", resp.StatusCode, body)
+	}
+}
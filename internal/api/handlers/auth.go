@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/auth"
+)
+
+// dummyPasswordHash được so khi email không tồn tại để thời gian trả lời
+// không cho biết email nào đã đăng ký.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+const userColumns = `id, email, username, first_name, last_name, avatar_url, bio, role, is_verified, created_at, updated_at`
+
+type AuthHandler struct {
+	db     *sql.DB
+	tokens *auth.Tokens
+}
+
+func NewAuthHandler(db *sql.DB, tokens *auth.Tokens) *AuthHandler {
+	return &AuthHandler{db: db, tokens: tokens}
+}
+
+func scanUser(row interface{ Scan(...interface{}) error }, user *dto.UserResponse, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&user.ID, &user.Email, &user.Username, &user.FirstName, &user.LastName, &user.AvatarURL,
+		&user.Bio, &user.Role, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt,
+	}, extra...)...)
+}
+
+// POST /api/auth/login
+// Đăng nhập bằng email và mật khẩu, trả về access token dùng trong header
+// "Authorization: Bearer <token>"
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	var user dto.UserResponse
+	var passwordHash string
+	err := scanUser(h.db.QueryRowContext(c.Request.Context(),
+		"SELECT "+userColumns+", password_hash FROM users WHERE email = $1", req.Email), &user, &passwordHash)
+	if err == sql.ErrNoRows {
+		// Đặt passwordHash là hash giả để thời gian trả lời như khi sai mật khẩu
+		passwordHash = string(dummyPasswordHash)
+	} else if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch user"))
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(req.Password)) != nil || err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeInvalidCredentials, "Invalid email or password"))
+		return
+	}
+
+	token, expiresAt, err := h.tokens.Issue(user.ID, time.Now())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to issue access token"))
+		return
+	}
+	middleware.Log(c).WithField("user_id", user.ID).Info("User logged in")
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Logged in successfully",
+		Data: dto.LoginResponse{
+			AccessToken: token,
+			TokenType:   "Bearer",
+			ExpiresAt:   expiresAt,
+			User:        user,
+		},
+	})
+}
+
+// GET /api/auth/me
+// Thông tin user đang đăng nhập
+func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var user dto.UserResponse
+	err := scanUser(h.db.QueryRowContext(c.Request.Context(), "SELECT "+userColumns+" FROM users WHERE id = $1", userID), &user)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch user"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "User retrieved successfully",
+		Data:    user,
+	})
+}
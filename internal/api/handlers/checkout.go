@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/money"
+	"internal/pricing"
+)
+
+// CheckoutHandler tính giá giỏ hàng: giá niêm yết, khuyến mãi đang chạy và
+// coupon theo coupon_policy của từng khuyến mãi.
+type CheckoutHandler struct {
+	db *sql.DB
+}
+
+func NewCheckoutHandler(db *sql.DB) *CheckoutHandler {
+	return &CheckoutHandler{db: db}
+}
+
+// POST /api/checkout/quote
+// Báo giá giỏ hàng trước khi thanh toán
+func (h *CheckoutHandler) Quote(c *gin.Context) {
+	var req dto.CheckoutQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	currency := money.Default
+	if req.Currency != "" {
+		currency = req.Currency
+	}
+	ctx := c.Request.Context()
+	now := time.Now()
+
+	ids := collectIDs(len(req.CourseIDs), func(i int) string { return req.CourseIDs[i] })
+	loaded, err := pricing.Load(ctx, h.db, ids, currency, now)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to price courses"))
+		return
+	}
+	lines := make([]pricing.Line, 0, len(ids))
+	for _, id := range ids {
+		line, ok := loaded[id]
+		if !ok {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course "+id+" not found"))
+			return
+		}
+		if line.Currency != currency {
+			apierror.Abort(c, apierror.Unprocessable(apierror.CodeCoursePriceNotFound,
+				fmt.Sprintf("Course %q has no price in %s", line.Title, currency)))
+			return
+		}
+		lines = append(lines, line)
+	}
+
+	quote := dto.CheckoutQuoteDTO{Currency: currency}
+	if req.CouponCode != "" {
+		coupon, err := loadCoupon(ctx, h.db, req.CouponCode)
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCouponNotFound, "Coupon not found"))
+			return
+		}
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to fetch coupon"))
+			return
+		}
+		// Đơn tối thiểu tính trên giá sau khuyến mãi
+		if code, message := couponProblem(coupon, currency, pricing.Subtotal(lines), now); code != "" {
+			apierror.Abort(c, apierror.Unprocessable(code, message))
+			return
+		}
+		applied := pricing.Coupon{DiscountType: coupon.DiscountType, Value: coupon.DiscountValue}
+		if coupon.CourseID != nil {
+			applied.CourseID = *coupon.CourseID
+			if !hasCourse(lines, applied.CourseID) {
+				apierror.Abort(c, apierror.Unprocessable(apierror.CodeCouponNotApplicable,
+					"Coupon only applies to course "+applied.CourseID))
+				return
+			}
+		}
+		if !pricing.Eligible(lines, applied) {
+			apierror.Abort(c, apierror.Unprocessable(apierror.CodeCouponNotCombinable,
+				"Coupons cannot be combined with the promotions in this order"))
+			return
+		}
+		pricing.ApplyCoupon(lines, applied)
+		quote.CouponCode = &coupon.Code
+	}
+
+	quote.Items = make([]dto.CheckoutQuoteItemDTO, len(lines))
+	for i, l := range lines {
+		quote.Items[i] = dto.CheckoutQuoteItemDTO{
+			CourseID:       l.CourseID,
+			Title:          l.Title,
+			ListPrice:      l.List.Amount,
+			Price:          l.Price.Amount,
+			Promotion:      appliedPromotion(l.Promotion),
+			CouponDiscount: l.CouponDiscount.Amount,
+			Total:          l.Total().Amount,
+		}
+		quote.Subtotal += l.List.Amount
+		quote.PromotionDiscount += l.List.Amount - l.Price.Amount
+		quote.CouponDiscount += l.CouponDiscount.Amount
+		quote.Total += l.Total().Amount
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Checkout quote calculated successfully",
+		Data:    quote,
+	})
+}
+
+func hasCourse(lines []pricing.Line, courseID string) bool {
+	for _, l := range lines {
+		if l.CourseID == courseID {
+			return true
+		}
+	}
+	return false
+}
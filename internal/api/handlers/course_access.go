@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/entitlement"
+)
+
+// viewerGrants trả về quyền học của user đăng nhập bằng access token
+// (middleware.Authenticate) với các khóa học; khách chưa đăng nhập không có
+// quyền nào. Bài học thử (is_preview) luôn mở nên không cần kiểm tra.
+func viewerGrants(c *gin.Context, db *sql.DB, courseIDs []string) (map[string]entitlement.Grant, error) {
+	userID := c.GetString(middleware.UserIDKey)
+	if _, err := uuid.Parse(userID); err != nil {
+		return map[string]entitlement.Grant{}, nil
+	}
+	return entitlement.CheckMany(c.Request.Context(), db, userID, courseIDs, time.Now())
+}
+
+// gateLectures ẩn nội dung các bài giảng (trừ bài học thử) của khóa học mà
+// user đang đăng nhập chưa có quyền học. courseIDs[i] là khóa học của lectures[i].
+func gateLectures(c *gin.Context, db *sql.DB, lectures []dto.CourseLectureResponse, courseIDs []string) error {
+	grants, err := viewerGrants(c, db, collectIDs(len(courseIDs), func(i int) string { return courseIDs[i] }))
+	if err != nil {
+		return err
+	}
+	for i := range lectures {
+		if _, ok := grants[courseIDs[i]]; !ok {
+			lockLecture(&lectures[i])
+		}
+	}
+	return nil
+}
+
+// gateSectionLectures là gateLectures cho bài giảng của các chương, sửa
+// trực tiếp các phần tử của sections[i].Lectures.
+func gateSectionLectures(c *gin.Context, db *sql.DB, sections []dto.CourseSectionResponse) error {
+	grants, err := viewerGrants(c, db, collectIDs(len(sections), func(i int) string { return sections[i].CourseID }))
+	if err != nil {
+		return err
+	}
+	for _, s := range sections {
+		if _, ok := grants[s.CourseID]; ok {
+			continue
+		}
+		for i := range s.Lectures {
+			lockLecture(&s.Lectures[i])
+		}
+	}
+	return nil
+}
+
+// lockLecture ẩn nội dung bài giảng, trừ bài học thử.
+func lockLecture(l *dto.CourseLectureResponse) {
+	if l.IsPreview {
+		return
+	}
+	l.Locked = true
+	l.VideoURL, l.ArticleContent, l.FileURL = nil, nil, nil
+}
+
+// GET /api/courses/:id/access
+// Quyền học khóa học của user đang đăng nhập: ghi danh, thuê bao, giảng viên
+// của khóa học hoặc admin
+func (h *CourseHandler) GetCourseAccess(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	var exists bool
+	if err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", id).Scan(&exists); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+	if !exists {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+
+	grant, err := entitlement.Check(ctx, h.db, userID, id, time.Now())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to check course access"))
+		return
+	}
+
+	access := dto.CourseAccessDTO{CourseID: id, HasAccess: grant != nil}
+	if grant != nil {
+		access.Source = &grant.Source
+		access.EnrollmentID = grant.EnrollmentID
+		access.SubscriptionID = grant.SubscriptionID
+		access.ExpiresAt = grant.ExpiresAt
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course access retrieved successfully",
+		Data:    access,
+	})
+}
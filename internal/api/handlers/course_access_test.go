@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+const testLectureID = "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"
+
+func expectLecture(mock sqlmock.Sqlmock, preview bool) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM course_lectures l JOIN course_sections s`).WithArgs(testLectureID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id", "title", "description", "content_type",
+			"video_url", "video_duration", "article_content", "file_url", "sort_order", "is_preview",
+			"is_downloadable", "created_at", "updated_at", "course_id"}).
+			AddRow(testLectureID, "9c8b7a6d-5e4f-4a3b-9c1d-0e1f2a3b4c5d", "Đạo hàm", nil, "video",
+				"https://cdn.example.com/dao-ham.mp4", 600, nil, nil, 1, preview, false, now, now, testCourseID))
+}
+
+// expectGrant answers entitlement.CheckMany; an empty source means no access.
+func expectGrant(mock sqlmock.Sqlmock, source string) {
+	rows := sqlmock.NewRows([]string{"id", "source", "enrollment_id", "subscription_id", "expires_at"})
+	if source == "" {
+		rows.AddRow(testCourseID, nil, nil, nil, nil)
+	} else {
+		rows.AddRow(testCourseID, source, "1d2c3b4a-5e6f-4a7b-8c9d-0e1f2a3b4c5d", nil, nil)
+	}
+	mock.ExpectQuery(`course_access\(u.id, co.id, \$3\)`).
+		WithArgs(testLearnerID, []string{testCourseID}, sqlmock.AnyArg()).WillReturnRows(rows)
+}
+
+func TestGetCourseLectureGating(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  string
+		preview bool
+		source  string // "" khi user không có quyền học; bỏ qua với khách
+		locked  bool
+	}{
+		{name: "enrolled learner", userID: testLearnerID, source: "enrollment"},
+		{name: "subscriber", userID: testLearnerID, source: "subscription"},
+		{name: "not enrolled", userID: testLearnerID, locked: true},
+		{name: "anonymous", locked: true},
+		{name: "anonymous preview", preview: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			expectLecture(mock, tt.preview)
+			if tt.userID != "" {
+				expectGrant(mock, tt.source)
+			}
+
+			status, res := serve(t, http.MethodGet, "/course-lectures/:id", "/course-lectures/"+testLectureID,
+				tt.userID, "", NewCourseLectureHandler(db).GetCourseLecture)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d (%s)", status, res.Error.Code)
+			}
+			var lecture dto.CourseLectureResponse
+			if err := json.Unmarshal(res.Data, &lecture); err != nil {
+				t.Fatal(err)
+			}
+			if lecture.Locked != tt.locked {
+				t.Errorf("locked = %v, want %v", lecture.Locked, tt.locked)
+			}
+			if hasContent := lecture.VideoURL != nil; hasContent == tt.locked {
+				t.Errorf("video_url = %v with locked = %v", lecture.VideoURL, tt.locked)
+			}
+		})
+	}
+}
+
+// expectSectionLectures answers loadSectionLectures for testSectionID with a
+// preview lecture and a paid one.
+func expectSectionLectures(mock sqlmock.Sqlmock) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, section_id, title, description, content_type, video_url, video_duration`).
+		WithArgs([]string{testSectionID}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id", "title", "description", "content_type",
+			"video_url", "video_duration", "article_content", "file_url", "sort_order", "is_preview",
+			"is_downloadable", "created_at", "updated_at"}).
+			AddRow(lecture1ID, testSectionID, "Giới thiệu", nil, "video", "https://cdn.example.com/gioi-thieu.mp4",
+				120, nil, nil, 1, true, false, now, now).
+			AddRow(lecture2ID, testSectionID, "Đạo hàm", nil, "video", "https://cdn.example.com/dao-ham.mp4",
+				600, nil, nil, 2, false, false, now, now))
+}
+
+func expectSection(mock sqlmock.Sqlmock) *sqlmock.Rows {
+	now := time.Now()
+	return sqlmock.NewRows([]string{"id", "course_id", "title", "description", "sort_order", "created_at", "updated_at"}).
+		AddRow(testSectionID, testCourseID, "Đạo hàm", nil, 1, now, now)
+}
+
+func checkLocked(t *testing.T, lectures []dto.CourseLectureResponse) {
+	t.Helper()
+	if len(lectures) != 2 {
+		t.Fatalf("lectures = %+v", lectures)
+	}
+	if preview := lectures[0]; preview.Locked || preview.VideoURL == nil {
+		t.Errorf("preview lecture = %+v, want its content", preview)
+	}
+	if paid := lectures[1]; !paid.Locked || paid.VideoURL != nil || paid.ArticleContent != nil || paid.FileURL != nil {
+		t.Errorf("paid lecture = %+v, want it locked", paid)
+	}
+}
+
+// Lectures read through their section are gated like /course-lectures.
+func TestCourseSectionLectureGating(t *testing.T) {
+	t.Run("section of a course the learner has no access to", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery(`FROM course_sections WHERE id = \$1`).WithArgs(testSectionID).WillReturnRows(expectSection(mock))
+		expectSectionLectures(mock)
+		expectGrant(mock, "")
+
+		status, res := serve(t, http.MethodGet, "/course-sections/:id", "/course-sections/"+testSectionID,
+			testLearnerID, "", NewCourseSectionHandler(db).GetCourseSection)
+		if status != http.StatusOK {
+			t.Fatalf("status = %d (%s)", status, res.Error.Code)
+		}
+		var section dto.CourseSectionResponse
+		if err := json.Unmarshal(res.Data, &section); err != nil {
+			t.Fatal(err)
+		}
+		checkLocked(t, section.Lectures)
+	})
+
+	t.Run("anonymous section list with lectures", func(t *testing.T) {
+		db, mock := newMockDB(t)
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM course_sections`).WithArgs(testCourseID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(`FROM course_sections`).WithArgs(testCourseID, 10, 0).WillReturnRows(expectSection(mock))
+		expectSectionLectures(mock)
+
+		status, res := serve(t, http.MethodGet, "/course-sections",
+			"/course-sections?course_id="+testCourseID+"&include_lectures=true", "", "",
+			NewCourseSectionHandler(db).GetCourseSections)
+		if status != http.StatusOK {
+			t.Fatalf("status = %d (%s)", status, res.Error.Code)
+		}
+		var list dto.CourseSectionListResponse
+		if err := json.Unmarshal(res.Data, &list); err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Sections) != 1 {
+			t.Fatalf("sections = %+v", list.Sections)
+		}
+		checkLocked(t, list.Sections[0].Lectures)
+	})
+}
+
+// ?include=sections.lectures is an outline: content is never selected, so
+// it cannot leak whoever asks.
+func TestCourseIncludeLecturesOmitsContent(t *testing.T) {
+	db, mock := newMockDB(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM courses WHERE id = \$1`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "slug", "description", "short_description",
+			"thumbnail_url", "preview_video_url", "instructor_id", "category_id", "price", "discount_price",
+			"currency", "language", "level", "duration_hours", "total_lectures", "status", "requirements",
+			"what_you_learn", "target_audience", "rating", "total_students", "total_reviews", "published_at",
+			"created_at", "updated_at"}).
+			AddRow(testCourseID, "Giải tích 12", "giai-tich-12", nil, nil, nil, nil, testLearnerID, "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b",
+				49900000, nil, "VND", "vi", "beginner", 10, 2, "published", "{}", "{}", "{}",
+				0, 0, 0, now, now, now))
+	mock.ExpectQuery(`FROM courses c`).WillReturnRows(sqlmock.NewRows(nil))
+	mock.ExpectQuery(`FROM course_sections`).WithArgs([]string{testCourseID}).WillReturnRows(expectSection(mock))
+	mock.ExpectQuery(`SELECT id, section_id, title, description, content_type, NULL, video_duration, NULL, NULL`).
+		WithArgs([]string{testSectionID}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id", "title", "description", "content_type",
+			"video_url", "video_duration", "article_content", "file_url", "sort_order", "is_preview",
+			"is_downloadable", "created_at", "updated_at"}).
+			AddRow(lecture2ID, testSectionID, "Đạo hàm", nil, "video", nil, 600, nil, nil, 2, false, false, now, now))
+
+	status, res := serve(t, http.MethodGet, "/courses/:id", "/courses/"+testCourseID+"?include=sections.lectures",
+		"", "", NewCourseHandler(db).GetCourse)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var course dto.CourseResponse
+	if err := json.Unmarshal(res.Data, &course); err != nil {
+		t.Fatal(err)
+	}
+	if len(course.Sections) != 1 || len(course.Sections[0].Lectures) != 1 {
+		t.Fatalf("sections = %+v", course.Sections)
+	}
+	if l := course.Sections[0].Lectures[0]; l.VideoURL != nil || l.VideoDuration == nil || *l.VideoDuration != 600 {
+		t.Errorf("lecture = %+v, want duration without content", l)
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/slug"
+)
+
+// POST /api/courses/:id/clone
+// Sao chép khóa học (sections, lectures kể cả quiz, tags và tùy chọn
+// announcements, coupons của khóa học) thành một bản nháp mới trong một
+// transaction. Chỉ giảng viên của khóa học hoặc admin được sao chép; bản sao
+// thuộc về người sao chép, admin có thể giao cho giảng viên khác.
+func (h *CourseHandler) CloneCourse(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	// Body có thể để trống
+	var req dto.CloneCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	instructorID, role, ok := requireCourseOwner(c, h.db, id)
+	if !ok {
+		return
+	}
+	if req.InstructorID != nil && *req.InstructorID != instructorID && role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Only an admin can clone a course for another instructor"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to start transaction"))
+		return
+	}
+	defer tx.Rollback()
+
+	var title, courseSlug string
+	err = tx.QueryRowContext(ctx, "SELECT title, slug FROM courses WHERE id = $1", id).Scan(&title, &courseSlug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+			return
+		}
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.InstructorID != nil && *req.InstructorID != instructorID {
+		var instructorRole string
+		err := tx.QueryRowContext(ctx, "SELECT role FROM users WHERE id = $1", *req.InstructorID).Scan(&instructorRole)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				apierror.Abort(c, apierror.BadRequest(apierror.CodeInstructorNotFound, "Instructor not found"))
+				return
+			}
+			apierror.Abort(c, apierror.Internal(err, "Failed to verify instructor"))
+			return
+		}
+		if instructorRole != "instructor" && instructorRole != "admin" {
+			apierror.Abort(c, apierror.BadRequest(apierror.CodeNotInstructor, "User is not an instructor"))
+			return
+		}
+		instructorID = *req.InstructorID
+	}
+	// Mặc định "<slug>-copy", "<slug>-copy-2", ...; slug gửi lên cũng được
+	// chuẩn hóa và thêm hậu tố nếu đã có
+	base := strings.TrimSuffix(courseSlug, "-copy")
+	if len(base) > courseSlugMaxLength-len("-copy") {
+		base = base[:courseSlugMaxLength-len("-copy")]
+	}
+	base += "-copy"
+	if req.Slug != nil {
+		base = slug.Make(*req.Slug, courseSlugMaxLength)
+	}
+	courseSlug, err = slug.Unique(ctx, tx, base, courseSlugMaxLength, courseSlugTables...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to generate course slug"))
+		return
+	}
+
+	// Khóa học mới luôn là draft; các bộ đếm do trigger tính khi sao chép lectures
+	newID := uuid.New().String()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO courses (
+			id, title, slug, description, short_description, thumbnail_url, preview_video_url,
+			instructor_id, category_id, price, discount_price, currency, language, level,
+			status, requirements, what_you_learn, target_audience, created_at, updated_at
+		)
+		SELECT $2, $3, $4, description, short_description,
+			   CASE WHEN $6 THEN thumbnail_url END, CASE WHEN $6 THEN preview_video_url END,
+			   $5, category_id, price, discount_price, currency, language, level,
+			   'draft', requirements, what_you_learn, target_audience,
+			   CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+		FROM courses WHERE id = $1
+	`, id, newID, title, courseSlug, instructorID, req.CopyMedia)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to clone course"))
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO course_prices (course_id, currency, price, discount_price)
+		SELECT $2, currency, price, discount_price FROM course_prices WHERE course_id = $1
+	`, id, newID); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to copy course prices"))
+		return
+	}
+
+	// Map section cũ → mới để gắn lectures vào đúng section
+	oldSections, err := queryIDs(ctx, tx, "SELECT id FROM course_sections WHERE course_id = $1", id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course sections"))
+		return
+	}
+	oldIDs, newIDs := make([]string, 0, len(oldSections)), make([]string, 0, len(oldSections))
+	for sectionID := range oldSections {
+		oldIDs = append(oldIDs, sectionID)
+		newIDs = append(newIDs, uuid.New().String())
+	}
+
+	if len(oldIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO course_sections (id, course_id, title, description, sort_order, created_at, updated_at)
+			SELECT m.new_id, $3, s.title, s.description, s.sort_order, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+			FROM course_sections s
+			JOIN unnest($1::uuid[], $2::uuid[]) AS m(old_id, new_id) ON m.old_id = s.id
+		`, oldIDs, newIDs, newID)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to clone course sections"))
+			return
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO course_lectures (
+				section_id, title, description, content_type, video_url, video_duration,
+				article_content, file_url, sort_order, is_preview, is_downloadable, created_at, updated_at
+			)
+			SELECT m.new_id, l.title, l.description, l.content_type,
+				   CASE WHEN $3 THEN l.video_url END, l.video_duration,
+				   l.article_content, CASE WHEN $3 THEN l.file_url END,
+				   l.sort_order, l.is_preview, l.is_downloadable, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+			FROM course_lectures l
+			JOIN unnest($1::uuid[], $2::uuid[]) AS m(old_id, new_id) ON m.old_id = l.section_id
+		`, oldIDs, newIDs, req.CopyMedia)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to clone course lectures"))
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO course_tags (course_id, tag_id)
+		SELECT $2, tag_id FROM course_tags WHERE course_id = $1
+	`, id, newID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to clone course tags"))
+		return
+	}
+
+	if req.CopyAnnouncements {
+		// Announcements được sao chép ở trạng thái chưa publish
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO course_announcements (course_id, title, content, is_published, created_at, updated_at)
+			SELECT $2, title, content, FALSE, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+			FROM course_announcements WHERE course_id = $1
+		`, id, newID)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to clone course announcements"))
+			return
+		}
+	}
+
+	if req.CopyCoupons {
+		// Coupon sao chép có mã mới, chưa kích hoạt và chưa được dùng
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO coupons (code, description, discount_type, discount_value, min_order_amount, max_uses,
+			                     used_count, is_active, valid_from, valid_until, source, currency, course_id)
+			SELECT LEFT(code, 41) || '-' || UPPER(LEFT(md5(random()::text || id::text), 8)),
+			       description, discount_type, discount_value, min_order_amount, max_uses,
+			       0, FALSE, valid_from, valid_until, source, currency, $2
+			FROM coupons WHERE course_id = $1
+		`, id, newID)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to clone course coupons"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to clone course"))
+		return
+	}
+
+	course, err := fetchCourse(ctx, h.db, newID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch cloned course"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Course cloned successfully",
+		Data:    course,
+	})
+}
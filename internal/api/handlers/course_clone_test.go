@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+func TestCloneCourse(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	expectCourseOwner(mock, testInstructorID, "instructor", testInstructorID)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT title, slug FROM courses`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"title", "slug"}).AddRow("Giải tích 12", "giai-tich-12"))
+	// The requested slug is normalised and suffixed when taken.
+	mock.ExpectQuery(`SELECT slug FROM courses`).WithArgs("giai-tich-12-2025", "giai-tich-12-2025-%").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("giai-tich-12-2025"))
+	mock.ExpectExec(`INSERT INTO courses`).
+		WithArgs(testCourseID, sqlmock.AnyArg(), "Giải tích 12", "giai-tich-12-2025-2", testInstructorID, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO course_prices`).WithArgs(testCourseID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id FROM course_sections`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testSectionID))
+	mock.ExpectExec(`INSERT INTO course_sections`).
+		WithArgs([]string{testSectionID}, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Without media the URLs are dropped but the video duration is kept.
+	mock.ExpectExec(`CASE WHEN \$3 THEN l.video_url END, l.video_duration,`).
+		WithArgs([]string{testSectionID}, sqlmock.AnyArg(), false).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO course_tags`).WithArgs(testCourseID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO coupons[\s\S]+0, FALSE[\s\S]+WHERE course_id = \$1`).
+		WithArgs(testCourseID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM courses WHERE id = \$1`).WillReturnRows(sqlmock.NewRows([]string{
+		"id", "title", "slug", "description", "short_description", "thumbnail_url", "preview_video_url",
+		"instructor_id", "category_id", "price", "discount_price", "currency", "language", "level", "duration_hours",
+		"total_lectures", "status", "requirements", "what_you_learn", "target_audience",
+		"rating", "total_students", "total_reviews", "published_at", "created_at", "updated_at",
+	}).AddRow("5f4e3d2c-1b0a-4f9e-8d7c-6b5a4f3e2d1c", "Giải tích 12", "giai-tich-12-2025-2", nil, nil, nil, nil,
+		testInstructorID, "7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d", 1299000, nil, "VND", "vi", "beginner", 0,
+		3, "draft", "{}", "{}", "{}", 0, 0, 0, nil, now, now))
+
+	status, res := serve(t, http.MethodPost, "/courses/:id/clone", "/courses/"+testCourseID+"/clone", testInstructorID,
+		`{"slug":"Giải Tích 12 (2025)","copy_coupons":true}`, NewCourseHandler(db).CloneCourse)
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var course dto.CourseResponse
+	if err := json.Unmarshal(res.Data, &course); err != nil {
+		t.Fatal(err)
+	}
+	if course.Slug != "giai-tich-12-2025-2" || course.Status != "draft" {
+		t.Errorf("course = %+v", course)
+	}
+}
+
+// Only the course's instructor or an admin may clone it, and only an admin
+// may hand the clone to another instructor.
+func TestCloneCourseForbidden(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		role   string
+		body   string
+		status int
+		code   string
+	}{
+		{"anonymous", "", "", `{}`, http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{"another instructor", testLearnerID, "instructor", `{}`, http.StatusForbidden, "FORBIDDEN"},
+		{"owner gives the clone away", testInstructorID, "instructor",
+			`{"instructor_id":"` + testLearnerID + `"}`, http.StatusForbidden, "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			if tt.userID != "" {
+				expectCourseOwner(mock, tt.userID, tt.role, testInstructorID)
+			}
+
+			status, res := serve(t, http.MethodPost, "/courses/:id/clone", "/courses/"+testCourseID+"/clone",
+				tt.userID, tt.body, NewCourseHandler(db).CloneCourse)
+			if status != tt.status || res.Error.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", status, res.Error.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,389 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+)
+
+// GET /api/courses/:id/curriculum
+// Trả về toàn bộ sections → lectures của khóa học trong một query. Nếu có
+// user đăng nhập thì kèm tiến độ từng bài và bài cần học tiếp (resume). Bài
+// không phải học thử bị khóa (locked) khi user chưa có quyền học khóa học.
+func (h *CourseHandler) GetCourseCurriculum(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	// Chỉ lấy tiến độ khi có user đăng nhập
+	var userID interface{}
+	if uid := c.GetString(middleware.UserIDKey); uid != "" {
+		if _, err := uuid.Parse(uid); err == nil {
+			userID = uid
+		}
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(), `
+		SELECT c.title,
+			   s.id, s.title, s.description, s.sort_order,
+			   l.id, l.title, l.content_type, l.video_duration, l.sort_order, l.is_preview, l.is_downloadable,
+			   lp.is_completed, lp.watch_time, lp.completed_at, lp.updated_at
+		FROM courses c
+		LEFT JOIN course_sections s ON s.course_id = c.id
+		LEFT JOIN course_lectures l ON l.section_id = s.id
+		LEFT JOIN lecture_progress lp ON lp.lecture_id = l.id AND lp.user_id = $2::uuid
+		WHERE c.id = $1
+		ORDER BY s.sort_order ASC, s.created_at ASC, l.sort_order ASC, l.created_at ASC
+	`, id, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course curriculum"))
+		return
+	}
+	defer rows.Close()
+
+	curriculum := dto.CurriculumResponse{CourseID: id, Sections: []dto.CurriculumSection{}}
+	found := false
+
+	// Bài giảng được xem gần nhất, dùng để tính resume
+	lastSection, lastLecture := -1, -1
+	var lastTouched time.Time
+
+	for rows.Next() {
+		var (
+			sectionID, sectionTitle, lectureID, lectureTitle, contentType sql.NullString
+			sectionDesc                                                   sql.NullString
+			sectionOrder, lectureOrder, duration                          sql.NullInt32
+			isPreview, isDownloadable, isCompleted                        sql.NullBool
+			watchTime                                                     sql.NullInt32
+			completedAt, progressAt                                       sql.NullTime
+		)
+		err := rows.Scan(
+			&curriculum.Title,
+			&sectionID, &sectionTitle, &sectionDesc, &sectionOrder,
+			&lectureID, &lectureTitle, &contentType, &duration, &lectureOrder, &isPreview, &isDownloadable,
+			&isCompleted, &watchTime, &completedAt, &progressAt,
+		)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan course curriculum"))
+			return
+		}
+		found = true
+
+		if !sectionID.Valid {
+			continue
+		}
+		n := len(curriculum.Sections)
+		if n == 0 || curriculum.Sections[n-1].ID != sectionID.String {
+			section := dto.CurriculumSection{
+				ID:        sectionID.String,
+				Title:     sectionTitle.String,
+				SortOrder: sectionOrder.Int32,
+				Lectures:  []dto.CurriculumLecture{},
+			}
+			if sectionDesc.Valid {
+				section.Description = &sectionDesc.String
+			}
+			curriculum.Sections = append(curriculum.Sections, section)
+			n++
+		}
+		section := &curriculum.Sections[n-1]
+
+		if !lectureID.Valid {
+			continue
+		}
+		lecture := dto.CurriculumLecture{
+			ID:             lectureID.String,
+			Title:          lectureTitle.String,
+			ContentType:    contentType.String,
+			SortOrder:      lectureOrder.Int32,
+			IsPreview:      isPreview.Bool,
+			IsDownloadable: isDownloadable.Bool,
+		}
+		if duration.Valid {
+			lecture.Duration = &duration.Int32
+			section.Duration += int64(duration.Int32)
+		}
+		if userID != nil {
+			lecture.Progress = &dto.CurriculumProgress{}
+			if progressAt.Valid {
+				lecture.Progress.IsCompleted = isCompleted.Bool
+				lecture.Progress.WatchTime = int(watchTime.Int32)
+				if completedAt.Valid {
+					lecture.Progress.CompletedAt = &completedAt.Time
+				}
+				if progressAt.Time.After(lastTouched) {
+					lastTouched = progressAt.Time
+					lastSection, lastLecture = n-1, len(section.Lectures)
+				}
+			}
+		}
+		section.Lectures = append(section.Lectures, lecture)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course curriculum"))
+		return
+	}
+	if !found {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+
+	grants, err := viewerGrants(c, h.db, []string{id})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to check course access"))
+		return
+	}
+	if grant, ok := grants[id]; ok {
+		curriculum.HasAccess = true
+		curriculum.AccessSource = &grant.Source
+	}
+
+	completed := 0
+	for s := range curriculum.Sections {
+		section := &curriculum.Sections[s]
+		for l := range section.Lectures {
+			section.Lectures[l].Locked = !curriculum.HasAccess && !section.Lectures[l].IsPreview
+		}
+	}
+	for _, section := range curriculum.Sections {
+		curriculum.TotalLectures += len(section.Lectures)
+		curriculum.TotalDuration += section.Duration
+		for _, lecture := range section.Lectures {
+			if lecture.Progress != nil && lecture.Progress.IsCompleted {
+				completed++
+			}
+		}
+	}
+	if userID != nil {
+		curriculum.Completed = &completed
+		curriculum.Resume = resumePoint(curriculum.Sections, lastSection, lastLecture)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course curriculum retrieved successfully",
+		Data:    curriculum,
+	})
+}
+
+// resumePoint chọn bài để học tiếp: bài xem gần nhất nếu chưa hoàn thành,
+// nếu không thì bài chưa hoàn thành đầu tiên sau nó (vòng lại từ đầu khóa).
+// Trả về nil khi đã hoàn thành tất cả.
+func resumePoint(sections []dto.CurriculumSection, lastSection, lastLecture int) *dto.CurriculumResume {
+	type position struct{ s, l int }
+	var order []position
+	start := 0
+	for s := range sections {
+		for l := range sections[s].Lectures {
+			if s == lastSection && l == lastLecture {
+				start = len(order)
+			}
+			order = append(order, position{s, l})
+		}
+	}
+
+	for i := range order {
+		p := order[(start+i)%len(order)]
+		lecture := sections[p.s].Lectures[p.l]
+		if lecture.Progress != nil && lecture.Progress.IsCompleted {
+			continue
+		}
+		resume := &dto.CurriculumResume{SectionID: sections[p.s].ID, LectureID: lecture.ID}
+		if lecture.Progress != nil {
+			resume.WatchTime = lecture.Progress.WatchTime
+		}
+		return resume
+	}
+	return nil
+}
+
+// PUT /api/courses/:id/curriculum/order
+// Sắp xếp lại toàn bộ sections và lectures của khóa học (kể cả chuyển lecture
+// sang section khác) trong một transaction. Request phải liệt kê đủ mọi
+// section và lecture của khóa học, mỗi ID đúng một lần. Chỉ giảng viên của
+// khóa học hoặc admin.
+func (h *CourseHandler) ReorderCurriculum(c *gin.Context) {
+	id := c.Param("id")
+
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	var req dto.ReorderCurriculumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	if _, _, ok := requireCourseOwner(c, h.db, id); !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to start transaction"))
+		return
+	}
+	defer tx.Rollback()
+
+	// Khóa sections và lectures hiện tại để so với request
+	sectionSet, err := queryIDs(ctx, tx, `
+		SELECT id FROM course_sections WHERE course_id = $1 FOR UPDATE
+	`, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course sections"))
+		return
+	}
+	lectureSet, err := queryIDs(ctx, tx, `
+		SELECT l.id FROM course_lectures l
+		JOIN course_sections s ON s.id = l.section_id
+		WHERE s.course_id = $1
+		FOR UPDATE OF l
+	`, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course lectures"))
+		return
+	}
+
+	sectionIDs := make([]string, 0, len(req.Sections))
+	var lectureIDs, lectureSections []string
+	var lectureOrders []int32
+	var problems []apierror.FieldError
+	for i, section := range req.Sections {
+		sectionIDs = append(sectionIDs, section.ID)
+		for j, lectureID := range section.Lectures {
+			lectureIDs = append(lectureIDs, lectureID)
+			lectureSections = append(lectureSections, section.ID)
+			lectureOrders = append(lectureOrders, int32(j+1))
+			if !lectureSet.take(lectureID) {
+				problems = append(problems, apierror.FieldError{
+					Field: fmt.Sprintf("sections[%d].lectures[%d]", i, j), Rule: "member",
+					Message: "is not a lecture of this course or is listed more than once",
+				})
+			}
+		}
+		if !sectionSet.take(section.ID) {
+			problems = append(problems, apierror.FieldError{
+				Field: fmt.Sprintf("sections[%d].id", i), Rule: "member",
+				Message: "is not a section of this course or is listed more than once",
+			})
+		}
+	}
+	for _, missing := range sectionSet.remaining() {
+		problems = append(problems, apierror.FieldError{Field: "sections", Rule: "complete", Message: "is missing section " + missing})
+	}
+	for _, missing := range lectureSet.remaining() {
+		problems = append(problems, apierror.FieldError{Field: "sections", Rule: "complete", Message: "is missing lecture " + missing})
+	}
+	if len(problems) > 0 {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeCurriculumOrderMismatch,
+			"Order must list every section and lecture of the course exactly once").WithDetails(problems...))
+		return
+	}
+
+	// Kiểm tra unique sort_order ở cuối transaction thay vì từng dòng
+	_, err = tx.ExecContext(ctx, `
+		SET CONSTRAINTS course_sections_course_id_sort_order_key, course_lectures_section_id_sort_order_key DEFERRED
+	`)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to defer sort order constraints"))
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE course_sections s
+		SET sort_order = o.ord, updated_at = NOW()
+		FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, ord)
+		WHERE s.id = o.id AND s.sort_order <> o.ord
+	`, sectionIDs)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to reorder course sections"))
+		return
+	}
+
+	if len(lectureIDs) > 0 {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE course_lectures l
+			SET section_id = o.section_id, sort_order = o.ord, updated_at = NOW()
+			FROM unnest($1::uuid[], $2::uuid[], $3::int[]) AS o(id, section_id, ord)
+			WHERE l.id = o.id AND (l.section_id, l.sort_order) IS DISTINCT FROM (o.section_id, o.ord)
+		`, lectureIDs, lectureSections, lectureOrders)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to reorder course lectures"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to save curriculum order"))
+		return
+	}
+
+	sections, err := loadCourseSections(ctx, h.db, []string{id}, true)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course sections"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Curriculum order updated successfully",
+		Data:    sections[id],
+	})
+}
+
+// idSet tracks which existing IDs a request has referenced.
+type idSet map[string]bool
+
+// queryIDs collects the single ID column of query into an idSet.
+func queryIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (idSet, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	set := idSet{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		set[id] = false
+	}
+	return set, rows.Err()
+}
+
+// take marks id as used; false if it is unknown or was already used.
+func (s idSet) take(id string) bool {
+	used, ok := s[id]
+	if !ok || used {
+		return false
+	}
+	s[id] = true
+	return true
+}
+
+func (s idSet) remaining() []string {
+	var ids []string
+	for id, used := range s {
+		if !used {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
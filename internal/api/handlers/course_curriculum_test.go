@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+const (
+	testInstructorID = "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b"
+	lecture3ID       = "33333333-3333-4333-8333-333333333333"
+)
+
+// expectCurriculum answers the curriculum query for a course with one
+// section of three lectures; the first is a preview. withProgress adds the
+// learner's progress: lecture 1 completed, lecture 2 watched last.
+func expectCurriculum(mock sqlmock.Sqlmock, userID interface{}, withProgress bool) {
+	rows := sqlmock.NewRows([]string{"title", "s_id", "s_title", "s_description", "s_sort_order",
+		"l_id", "l_title", "content_type", "video_duration", "l_sort_order", "is_preview", "is_downloadable",
+		"is_completed", "watch_time", "completed_at", "updated_at"})
+	yesterday, hourAgo := time.Now().Add(-24*time.Hour), time.Now().Add(-time.Hour)
+	lecture := func(id string, order int, preview bool) []driver.Value {
+		return []driver.Value{"Giải tích 12", testSectionID, "Đạo hàm", nil, 1, id, "Bài " + id[:1], "video", 600, order, preview, false}
+	}
+	if withProgress {
+		rows.AddRow(append(lecture(lecture1ID, 1, true), true, 600, yesterday, yesterday)...)
+		rows.AddRow(append(lecture(lecture2ID, 2, false), false, 120, nil, hourAgo)...)
+	} else {
+		rows.AddRow(append(lecture(lecture1ID, 1, true), nil, nil, nil, nil)...)
+		rows.AddRow(append(lecture(lecture2ID, 2, false), nil, nil, nil, nil)...)
+	}
+	rows.AddRow(append(lecture(lecture3ID, 3, false), nil, nil, nil, nil)...)
+
+	mock.ExpectQuery(`LEFT JOIN lecture_progress lp`).WithArgs(testCourseID, userID).WillReturnRows(rows)
+}
+
+func getCurriculum(t *testing.T, h *CourseHandler, userID string) dto.CurriculumResponse {
+	t.Helper()
+	status, res := serve(t, http.MethodGet, "/courses/:id/curriculum", "/courses/"+testCourseID+"/curriculum",
+		userID, "", h.GetCourseCurriculum)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var curriculum dto.CurriculumResponse
+	if err := json.Unmarshal(res.Data, &curriculum); err != nil {
+		t.Fatal(err)
+	}
+	return curriculum
+}
+
+func TestGetCourseCurriculumEnrolledLearner(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectCurriculum(mock, testLearnerID, true)
+	expectGrant(mock, "enrollment")
+
+	curriculum := getCurriculum(t, NewCourseHandler(db), testLearnerID)
+
+	if !curriculum.HasAccess || curriculum.AccessSource == nil || *curriculum.AccessSource != "enrollment" {
+		t.Fatalf("has_access = %v, access_source = %v", curriculum.HasAccess, curriculum.AccessSource)
+	}
+	if curriculum.Completed == nil || *curriculum.Completed != 1 {
+		t.Errorf("completed_lectures = %v, want 1", curriculum.Completed)
+	}
+	if r := curriculum.Resume; r == nil || r.LectureID != lecture2ID || r.WatchTime != 120 {
+		t.Errorf("resume = %+v, want lecture 2 at 120s", r)
+	}
+	lectures := curriculum.Sections[0].Lectures
+	for _, l := range lectures {
+		if l.Locked {
+			t.Errorf("lecture %s locked for an enrolled learner", l.ID)
+		}
+		if l.Progress == nil {
+			t.Errorf("lecture %s has no progress", l.ID)
+		}
+	}
+	if !lectures[0].Progress.IsCompleted || lectures[2].Progress.IsCompleted {
+		t.Errorf("progress = %+v, %+v", lectures[0].Progress, lectures[2].Progress)
+	}
+}
+
+func TestGetCourseCurriculumAnonymous(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectCurriculum(mock, nil, false)
+
+	curriculum := getCurriculum(t, NewCourseHandler(db), "")
+
+	if curriculum.HasAccess || curriculum.Completed != nil || curriculum.Resume != nil {
+		t.Fatalf("anonymous curriculum has learner data: %+v", curriculum)
+	}
+	for i, l := range curriculum.Sections[0].Lectures {
+		if l.Locked != !l.IsPreview {
+			t.Errorf("lecture %d: locked = %v, is_preview = %v", i, l.Locked, l.IsPreview)
+		}
+		if l.Progress != nil {
+			t.Errorf("lecture %d has progress", i)
+		}
+	}
+}
+
+func reorder(t *testing.T, db *sql.DB, userID, body string) (int, testResponse) {
+	t.Helper()
+	return serve(t, http.MethodPut, "/courses/:id/curriculum/order", "/courses/"+testCourseID+"/curriculum/order",
+		userID, body, NewCourseHandler(db).ReorderCurriculum)
+}
+
+// The order must name every section and lecture of the course exactly once.
+func TestReorderCurriculumMismatch(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectCourseOwner(mock, testInstructorID, "instructor", testInstructorID)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM course_sections WHERE course_id = \$1 FOR UPDATE`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testSectionID))
+	mock.ExpectQuery(`FOR UPDATE OF l`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(lecture1ID).AddRow(lecture2ID))
+	mock.ExpectRollback()
+
+	status, res := reorder(t, db, testInstructorID,
+		`{"sections":[{"id":"`+testSectionID+`","lectures":["`+lecture1ID+`","`+lecture3ID+`"]}]}`)
+	if status != http.StatusUnprocessableEntity || res.Error.Code != "CURRICULUM_ORDER_MISMATCH" {
+		t.Fatalf("got %d %s, want 422 CURRICULUM_ORDER_MISMATCH", status, res.Error.Code)
+	}
+	if len(res.Error.Details) != 2 {
+		t.Errorf("details = %+v, want the foreign lecture 3 and the missing lecture 2", res.Error.Details)
+	}
+}
+
+func TestReorderCurriculumForbidden(t *testing.T) {
+	body := `{"sections":[{"id":"` + testSectionID + `","lectures":[]}]}`
+
+	db, _ := newMockDB(t)
+	if status, res := reorder(t, db, "", body); status != http.StatusUnauthorized || res.Error.Code != "UNAUTHENTICATED" {
+		t.Errorf("anonymous: got %d %s, want 401 UNAUTHENTICATED", status, res.Error.Code)
+	}
+
+	db, mock := newMockDB(t)
+	expectCourseOwner(mock, testLearnerID, "instructor", testInstructorID)
+	if status, res := reorder(t, db, testLearnerID, body); status != http.StatusForbidden || res.Error.Code != "FORBIDDEN" {
+		t.Errorf("another instructor: got %d %s, want 403 FORBIDDEN", status, res.Error.Code)
+	}
+}
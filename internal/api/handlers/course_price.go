@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/money"
+	"internal/pricing"
+)
+
+// Bảng giá theo tiền tệ. Giá gốc nằm trên courses (price, discount_price,
+// currency); course_prices giữ giá ở các tiền tệ khác. Mọi số tiền trong API
+// là số nguyên đơn vị nhỏ nhất của tiền tệ.
+
+// applyCoursePrices đổi giá của các khóa học sang currency nếu khóa học có giá
+// ở tiền tệ đó (khóa học không có giữ nguyên giá gốc), rồi điền giá bán sau
+// khuyến mãi đang chạy.
+func applyCoursePrices(ctx context.Context, db *sql.DB, courses []dto.CourseResponse, currency string) error {
+	if len(courses) == 0 {
+		return nil
+	}
+	ids := collectIDs(len(courses), func(i int) string { return courses[i].ID })
+	if currency != "" {
+		if err := convertCoursePrices(ctx, db, courses, ids, currency); err != nil {
+			return err
+		}
+	}
+
+	lines, err := pricing.Load(ctx, db, ids, currency, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := range courses {
+		line, ok := lines[courses[i].ID]
+		if !ok {
+			continue
+		}
+		courses[i].EffectivePrice = &line.Price.Amount
+		courses[i].Promotion = appliedPromotion(line.Promotion)
+	}
+	return nil
+}
+
+func appliedPromotion(p *pricing.Promotion) *dto.AppliedPromotionDTO {
+	if p == nil {
+		return nil
+	}
+	return &dto.AppliedPromotionDTO{ID: p.ID, Name: p.Name, EndsAt: p.EndsAt, CouponPolicy: p.CouponPolicy}
+}
+
+func convertCoursePrices(ctx context.Context, db *sql.DB, courses []dto.CourseResponse, ids []string, currency string) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT course_id, minor_units(price, currency), minor_units(discount_price, currency)
+		FROM course_prices
+		WHERE course_id = ANY($1) AND currency = $2
+	`, ids, currency)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type price struct {
+		amount   int64
+		discount *int64
+	}
+	prices := map[string]price{}
+	for rows.Next() {
+		var id string
+		var p price
+		if err := rows.Scan(&id, &p.amount, &p.discount); err != nil {
+			return err
+		}
+		prices[id] = p
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range courses {
+		if p, ok := prices[courses[i].ID]; ok && courses[i].Currency != currency {
+			courses[i].Price, courses[i].DiscountPrice, courses[i].Currency = p.amount, p.discount, currency
+		}
+	}
+	return nil
+}
+
+// GET /api/courses/:id/prices
+// Giá gốc và giá ở mọi tiền tệ khác của khóa học
+func (h *CourseHandler) GetCoursePrices(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(), `
+		SELECT currency, minor_units(price, currency), minor_units(discount_price, currency), TRUE, updated_at
+		FROM courses WHERE id = $1
+		UNION ALL
+		SELECT p.currency, minor_units(p.price, p.currency), minor_units(p.discount_price, p.currency), FALSE, p.updated_at
+		FROM course_prices p
+		JOIN courses co ON co.id = p.course_id
+		WHERE p.course_id = $1 AND p.currency <> co.currency
+		ORDER BY 4 DESC, 1
+	`, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course prices"))
+		return
+	}
+	defer rows.Close()
+
+	prices := []dto.CoursePriceDTO{}
+	for rows.Next() {
+		var p dto.CoursePriceDTO
+		if err := rows.Scan(&p.Currency, &p.Price, &p.DiscountPrice, &p.IsBase, &p.UpdatedAt); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan course price"))
+			return
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course prices"))
+		return
+	}
+	if len(prices) == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course prices retrieved successfully",
+		Data:    prices,
+	})
+}
+
+// PUT /api/courses/:id/prices/:currency
+// Đặt giá ở một tiền tệ; tiền tệ gốc thì cập nhật giá gốc của khóa học.
+// Chỉ giảng viên của khóa học hoặc admin
+func (h *CourseHandler) SetCoursePrice(c *gin.Context) {
+	id, currency, ok := coursePriceParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetCoursePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	if _, _, ok := requireCourseOwner(c, h.db, id); !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	var base string
+	err := h.db.QueryRowContext(ctx, "SELECT currency FROM courses WHERE id = $1", id).Scan(&base)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+
+	price := dto.CoursePriceDTO{Currency: currency, IsBase: currency == base}
+	if price.IsBase {
+		err = h.db.QueryRowContext(ctx, `
+			UPDATE courses
+			SET price = from_minor_units($2, currency), discount_price = from_minor_units($3, currency),
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING minor_units(price, currency), minor_units(discount_price, currency), updated_at
+		`, id, *req.Price, req.DiscountPrice).Scan(&price.Price, &price.DiscountPrice, &price.UpdatedAt)
+	} else {
+		err = h.db.QueryRowContext(ctx, `
+			INSERT INTO course_prices (course_id, currency, price, discount_price)
+			VALUES ($1, $2, from_minor_units($3, $2), from_minor_units($4, $2))
+			ON CONFLICT (course_id, currency) DO UPDATE
+			SET price = EXCLUDED.price, discount_price = EXCLUDED.discount_price, updated_at = CURRENT_TIMESTAMP
+			RETURNING minor_units(price, currency), minor_units(discount_price, currency), updated_at
+		`, id, currency, *req.Price, req.DiscountPrice).Scan(&price.Price, &price.DiscountPrice, &price.UpdatedAt)
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to set course price"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course price set successfully",
+		Data:    price,
+	})
+}
+
+// DELETE /api/courses/:id/prices/:currency
+// Xóa giá ở một tiền tệ; không xóa được giá gốc. Chỉ giảng viên của khóa học
+// hoặc admin
+func (h *CourseHandler) DeleteCoursePrice(c *gin.Context) {
+	id, currency, ok := coursePriceParams(c)
+	if !ok {
+		return
+	}
+	if _, _, ok := requireCourseOwner(c, h.db, id); !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	var base string
+	err := h.db.QueryRowContext(ctx, "SELECT currency FROM courses WHERE id = $1", id).Scan(&base)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+	if currency == base {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeBasePriceRequired, "The base price of a course cannot be removed"))
+		return
+	}
+
+	result, err := h.db.ExecContext(ctx, "DELETE FROM course_prices WHERE course_id = $1 AND currency = $2", id, currency)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to delete course price"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCoursePriceNotFound, "Course has no price in "+currency))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course price deleted successfully",
+	})
+}
+
+func coursePriceParams(c *gin.Context) (id, currency string, ok bool) {
+	id = c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return "", "", false
+	}
+	currency = c.Param("currency")
+	if !money.IsSupported(currency) {
+		apierror.Abort(c, apierror.BadRequest(apierror.CodeUnsupportedCurrency, "Unsupported currency "+currency))
+		return "", "", false
+	}
+	return id, currency, true
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+func TestSetCoursePrice(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectCourseOwner(mock, testInstructorID, "instructor", testInstructorID)
+	mock.ExpectQuery(`SELECT currency FROM courses WHERE id = \$1`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"currency"}).AddRow("VND"))
+	mock.ExpectQuery(`INSERT INTO course_prices`).WithArgs(testCourseID, "USD", int64(4999), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"price", "discount_price", "updated_at"}).AddRow(4999, nil, time.Now()))
+
+	status, res := serve(t, http.MethodPut, "/courses/:id/prices/:currency", "/courses/"+testCourseID+"/prices/USD",
+		testInstructorID, `{"price":4999}`, NewCourseHandler(db).SetCoursePrice)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var price dto.CoursePriceDTO
+	if err := json.Unmarshal(res.Data, &price); err != nil {
+		t.Fatal(err)
+	}
+	if price.Currency != "USD" || price.IsBase || price.Price != 4999 {
+		t.Errorf("price = %+v", price)
+	}
+}
+
+// Only the course's instructor or an admin may change its prices.
+func TestCoursePriceRequiresOwner(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		userID string
+		status int
+		code   string
+	}{
+		{"anonymous sets a price", http.MethodPut, "", http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{"another instructor sets a price", http.MethodPut, testLearnerID, http.StatusForbidden, "FORBIDDEN"},
+		{"anonymous deletes a price", http.MethodDelete, "", http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{"another instructor deletes a price", http.MethodDelete, testLearnerID, http.StatusForbidden, "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			if tt.userID != "" {
+				expectCourseOwner(mock, tt.userID, "instructor", testInstructorID)
+			}
+			handler := NewCourseHandler(db).SetCoursePrice
+			if tt.method == http.MethodDelete {
+				handler = NewCourseHandler(db).DeleteCoursePrice
+			}
+
+			status, res := serve(t, tt.method, "/courses/:id/prices/:currency", "/courses/"+testCourseID+"/prices/USD",
+				tt.userID, `{"price":4999}`, handler)
+			if status != tt.status || res.Error.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", status, res.Error.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/middleware"
+)
+
+// currentUser trả về ID và role của user đang đăng nhập. Khi chưa đăng nhập
+// (hoặc user đã bị xóa) request bị dừng với 401.
+func currentUser(c *gin.Context, db *sql.DB) (id, role string, ok bool) {
+	id = c.GetString(middleware.UserIDKey)
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthenticated, "Authentication required"))
+		return "", "", false
+	}
+
+	err := db.QueryRowContext(c.Request.Context(), "SELECT role FROM users WHERE id = $1", id).Scan(&role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeUnauthenticated, "Authentication required"))
+			return "", "", false
+		}
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch current user"))
+		return "", "", false
+	}
+	return id, role, true
+}
+
+// requireAdmin dừng request nếu user đang đăng nhập không phải admin.
+func requireAdmin(c *gin.Context, db *sql.DB) bool {
+	_, role, ok := currentUser(c, db)
+	if !ok {
+		return false
+	}
+	if role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Admin access required"))
+		return false
+	}
+	return true
+}
+
+// requireCourseOwner dừng request nếu user đang đăng nhập không phải giảng
+// viên của course hay admin; course không tồn tại trả 404.
+func requireCourseOwner(c *gin.Context, db *sql.DB, courseID string) (id, role string, ok bool) {
+	id, role, ok = currentUser(c, db)
+	if !ok {
+		return "", "", false
+	}
+
+	var instructorID string
+	err := db.QueryRowContext(c.Request.Context(), "SELECT instructor_id FROM courses WHERE id = $1", courseID).Scan(&instructorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+			return "", "", false
+		}
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return "", "", false
+	}
+	if role != "admin" && instructorID != id {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Only the course instructor can change this course"))
+		return "", "", false
+	}
+	return id, role, true
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/dto"
+)
+
+// cursorPage is a keyset-paginated list request: rows newest first by
+// (timestamp, id), starting strictly after the cursor.
+type cursorPage struct {
+	dto.CursorQuery
+	after *dto.Cursor
+}
+
+// bindCursorPage returns nil when the request uses page/limit pagination,
+// i.e. has no `after` parameter at all.
+func bindCursorPage(c *gin.Context) (*cursorPage, *apierror.Error) {
+	if _, ok := c.GetQuery("after"); !ok {
+		return nil, nil
+	}
+	var q dto.CursorQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		return nil, apierror.Validation(err)
+	}
+	q.SetDefaults()
+
+	p := &cursorPage{CursorQuery: q}
+	if q.After != "" {
+		cur, err := dto.DecodeCursor(q.After)
+		if err != nil {
+			return nil, apierror.BadRequest(apierror.CodeInvalidCursor, "Invalid pagination cursor").WithCause(err)
+		}
+		p.after = &cur
+	}
+	return p, nil
+}
+
+// clause appends the keyset condition plus ORDER BY and LIMIT to a query
+// whose WHERE clause is already open. One row more than the limit is
+// fetched to tell whether another page follows.
+func (p *cursorPage) clause(timeColumn, idColumn string, args []interface{}) (string, []interface{}) {
+	var q string
+	if p.after != nil {
+		q += fmt.Sprintf(" AND (%s, %s) < ($%d::timestamptz, $%d::uuid)", timeColumn, idColumn, len(args)+1, len(args)+2)
+		args = append(args, p.after.CreatedAt, p.after.ID)
+	}
+	q += fmt.Sprintf(" ORDER BY %s DESC, %s DESC LIMIT $%d", timeColumn, idColumn, len(args)+1)
+	args = append(args, p.Limit+1)
+	return q, args
+}
+
+// page returns how many of the n fetched rows belong to this page and the
+// pagination metadata; cursorAt gives the cursor of row i.
+func (p *cursorPage) page(n int, cursorAt func(i int) dto.Cursor) (int, dto.CursorMeta) {
+	meta := dto.CursorMeta{Limit: p.Limit}
+	if n > p.Limit {
+		n = p.Limit
+		meta.HasMore = true
+		meta.NextCursor = cursorAt(n - 1).Encode()
+	}
+	return n, meta
+}
+
+// total runs countQuery only when the client asked for it.
+func (p *cursorPage) total(ctx context.Context, db *sql.DB, countQuery string, args []interface{}) (*int64, error) {
+	if !p.IncludeTotal {
+		return nil, nil
+	}
+	var total int64
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/dto"
+)
+
+const (
+	cursorRowID  = "0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c"
+	cursorUserID = "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := dto.Cursor{CreatedAt: time.Date(2024, 3, 1, 8, 30, 0, 123456000, time.UTC), ID: cursorRowID}
+	got, err := dto.DecodeCursor(want.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+
+	for _, s := range []string{"not base64!", "e30", "eyJpZCI6IngifQ"} { // garbage, {}, {"id":"x"}
+		if _, err := dto.DecodeCursor(s); err != dto.ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) err = %v", s, err)
+		}
+	}
+}
+
+func bindCursor(t *testing.T, query string) (*cursorPage, string) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/enrollments?"+query, nil)
+	p, err := bindCursorPage(c)
+	if err != nil {
+		return p, string(err.Code)
+	}
+	return p, ""
+}
+
+func TestBindCursorPage(t *testing.T) {
+	if p, code := bindCursor(t, "page=2&limit=20"); p != nil || code != "" {
+		t.Errorf("page/limit request: page = %+v, code = %q", p, code)
+	}
+	if _, code := bindCursor(t, "after=bm9wZQ"); code != "INVALID_CURSOR" {
+		t.Errorf("bad cursor: code = %q", code)
+	}
+	if _, code := bindCursor(t, "after=&limit=500"); code != "VALIDATION_FAILED" {
+		t.Errorf("limit over 100: code = %q", code)
+	}
+
+	// First page: no keyset condition, one extra row to detect the next page.
+	p, code := bindCursor(t, "after=")
+	if code != "" {
+		t.Fatal(code)
+	}
+	q, args := p.clause("e.enrolled_at", "e.id", []interface{}{cursorUserID})
+	if q != " ORDER BY e.enrolled_at DESC, e.id DESC LIMIT $2" || !reflect.DeepEqual(args, []interface{}{cursorUserID, 11}) {
+		t.Errorf("first page: %q %v", q, args)
+	}
+
+	at := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	p, _ = bindCursor(t, "limit=2&after="+dto.Cursor{CreatedAt: at, ID: cursorRowID}.Encode())
+	q, args = p.clause("e.enrolled_at", "e.id", []interface{}{cursorUserID})
+	want := " AND (e.enrolled_at, e.id) < ($2::timestamptz, $3::uuid) ORDER BY e.enrolled_at DESC, e.id DESC LIMIT $4"
+	if q != want || len(args) != 4 || !args[1].(time.Time).Equal(at) || args[2] != cursorRowID || args[3] != 3 {
+		t.Errorf("next page: %q %v", q, args)
+	}
+}
+
+func TestCursorPageMeta(t *testing.T) {
+	p := &cursorPage{CursorQuery: dto.CursorQuery{Limit: 2}}
+	ids := []string{"11111111-1111-4111-8111-111111111111", "22222222-2222-4222-8222-222222222222", cursorRowID}
+	at := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	cursorAt := func(i int) dto.Cursor { return dto.Cursor{CreatedAt: at, ID: ids[i]} }
+
+	n, meta := p.page(3, cursorAt)
+	if n != 2 || !meta.HasMore || meta.NextCursor != cursorAt(1).Encode() {
+		t.Errorf("full page: n = %d, meta = %+v", n, meta)
+	}
+	n, meta = p.page(2, cursorAt)
+	if n != 2 || meta.HasMore || meta.NextCursor != "" {
+		t.Errorf("last page: n = %d, meta = %+v", n, meta)
+	}
+}
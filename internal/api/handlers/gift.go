@@ -0,0 +1,499 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/gift"
+	"internal/metrics"
+	"internal/money"
+	"internal/pricing"
+)
+
+// maxGiftSeats giới hạn tổng số suất của một đơn quà tặng.
+const maxGiftSeats = 500
+
+// GiftHandler xử lý đơn quà tặng và voucher khóa học. Đơn được tạo ở trạng
+// thái pending như đơn thường; voucher chỉ đổi được khi đơn đã completed.
+type GiftHandler struct {
+	db       *sql.DB
+	validity time.Duration
+}
+
+// NewGiftHandler - voucher hết hạn sau validity kể từ khi đặt đơn
+func NewGiftHandler(db *sql.DB, validity time.Duration) *GiftHandler {
+	return &GiftHandler{db: db, validity: validity}
+}
+
+const giftOrderSelect = `
+	SELECT o.id, o.payment_status, o.currency,
+	       minor_units(o.total_amount, o.currency), minor_units(COALESCE(o.discount_amount, 0), o.currency),
+	       minor_units(o.final_amount, o.currency), o.gift_message,
+	       COUNT(v.id), COUNT(v.redeemed_at), o.created_at
+	FROM orders o
+	LEFT JOIN gift_vouchers v ON v.order_id = o.id`
+
+func scanGiftOrder(row interface{ Scan(...interface{}) error }, o *dto.GiftOrderDTO) error {
+	return row.Scan(&o.ID, &o.PaymentStatus, &o.Currency, &o.TotalAmount, &o.DiscountAmount,
+		&o.FinalAmount, &o.Message, &o.VoucherCount, &o.RedeemedCount, &o.CreatedAt)
+}
+
+const giftVoucherSelect = `
+	SELECT v.id, v.code, v.course_id, c.title, v.recipient_email,` + gift.Status + `,
+	       v.expires_at, v.redeemed_by, u.email, v.redeemed_at
+	FROM gift_vouchers v
+	JOIN orders o ON o.id = v.order_id
+	JOIN courses c ON c.id = v.course_id
+	LEFT JOIN users u ON u.id = v.redeemed_by`
+
+func queryGiftVouchers(ctx context.Context, q querier, where string, args ...interface{}) ([]dto.GiftVoucherDTO, error) {
+	rows, err := q.QueryContext(ctx, giftVoucherSelect+" WHERE "+where+" ORDER BY c.title, v.created_at, v.code", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := []dto.GiftVoucherDTO{}
+	for rows.Next() {
+		var v dto.GiftVoucherDTO
+		if err := rows.Scan(&v.ID, &v.Code, &v.CourseID, &v.CourseTitle, &v.RecipientEmail, &v.Status,
+			&v.ExpiresAt, &v.RedeemedBy, &v.RedeemedByEmail, &v.RedeemedAt); err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, v)
+	}
+	return vouchers, rows.Err()
+}
+
+// fetchGiftOrder đọc đơn quà tặng kèm voucher; sql.ErrNoRows nếu không có.
+func fetchGiftOrder(ctx context.Context, q querier, id string) (*dto.GiftOrderDTO, string, error) {
+	var order dto.GiftOrderDTO
+	var purchaserID string
+	err := q.QueryRowContext(ctx, `SELECT o.user_id FROM orders o WHERE o.id = $1 AND o.is_gift`, id).Scan(&purchaserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := scanGiftOrder(q.QueryRowContext(ctx, giftOrderSelect+" WHERE o.id = $1 GROUP BY o.id", id), &order); err != nil {
+		return nil, "", err
+	}
+	order.Vouchers, err = queryGiftVouchers(ctx, q, "v.order_id = $1", id)
+	if err != nil {
+		return nil, "", err
+	}
+	return &order, purchaserID, nil
+}
+
+// POST /api/gifts
+// Mua khóa học làm quà: tạo đơn pending và một voucher cho mỗi suất
+func (h *GiftHandler) CreateGiftOrder(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateGiftOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	currency := money.Default
+	if req.Currency != "" {
+		currency = req.Currency
+	}
+
+	seats := 0
+	var problems []apierror.FieldError
+	for i, item := range req.Items {
+		seats += item.Quantity
+		if len(item.RecipientEmails) > item.Quantity {
+			problems = append(problems, apierror.FieldError{
+				Field: fmt.Sprintf("items[%d].recipient_emails", i), Rule: "max",
+				Message: "must not list more recipients than quantity",
+			})
+		}
+	}
+	if len(problems) > 0 {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeTooManyRecipients,
+			"Each item can name at most one recipient per seat").WithDetails(problems...))
+		return
+	}
+	if seats > maxGiftSeats {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeValidationFailed,
+			fmt.Sprintf("A gift order can hold at most %d seats", maxGiftSeats)).WithDetails(apierror.FieldError{
+			Field: "items", Rule: "max", Message: fmt.Sprintf("must add up to at most %d seats", maxGiftSeats),
+		}))
+		return
+	}
+	ctx := c.Request.Context()
+	now := time.Now()
+
+	ids := collectIDs(len(req.Items), func(i int) string { return req.Items[i].CourseID })
+	var unpublished string
+	err := h.db.QueryRowContext(ctx, `
+		SELECT title FROM courses WHERE id = ANY($1) AND status <> 'published' LIMIT 1`, ids).Scan(&unpublished)
+	if err == nil {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeCourseNotPublished,
+			fmt.Sprintf("Course %q is not published", unpublished)))
+		return
+	}
+	if err != sql.ErrNoRows {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch courses"))
+		return
+	}
+
+	lines, err := pricing.Load(ctx, h.db, ids, currency, now)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to price courses"))
+		return
+	}
+	var total, final int64
+	for _, item := range req.Items {
+		line, ok := lines[item.CourseID]
+		if !ok {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course "+item.CourseID+" not found"))
+			return
+		}
+		if line.Currency != currency {
+			apierror.Abort(c, apierror.Unprocessable(apierror.CodeCoursePriceNotFound,
+				fmt.Sprintf("Course %q has no price in %s", line.Title, currency)))
+			return
+		}
+		total += line.List.Amount * int64(item.Quantity)
+		final += line.Price.Amount * int64(item.Quantity)
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create gift order"))
+		return
+	}
+	defer tx.Rollback()
+
+	var orderID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, total_amount, discount_amount, final_amount, currency,
+		                    payment_status, is_gift, gift_message)
+		VALUES ($1, from_minor_units($2, $5), from_minor_units($3, $5), from_minor_units($4, $5), $5,
+		        'pending', TRUE, $6)
+		RETURNING id`,
+		userID, total, total-final, final, currency, req.Message,
+	).Scan(&orderID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create gift order"))
+		return
+	}
+
+	expiresAt := now.Add(h.validity)
+	for _, item := range req.Items {
+		if err := h.addSeats(ctx, tx, orderID, userID, currency, lines[item.CourseID], item, expiresAt); err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to create gift vouchers"))
+			return
+		}
+	}
+
+	order, _, err := fetchGiftOrder(ctx, tx, orderID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch gift order"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create gift order"))
+		return
+	}
+
+	middleware.Log(c).WithField("order_id", orderID).WithField("seats", seats).Info("Gift order created")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Gift order created successfully",
+		Data:    order,
+	})
+}
+
+// addSeats thêm một order_item và một voucher cho mỗi suất của item.
+func (h *GiftHandler) addSeats(ctx context.Context, tx *sql.Tx, orderID, purchaserID, currency string,
+	line pricing.Line, item dto.GiftItemRequest, expiresAt time.Time) error {
+	var discount *int64
+	if line.Price.Amount < line.List.Amount {
+		discount = &line.Price.Amount
+	}
+	rows, err := tx.QueryContext(ctx, `
+		INSERT INTO order_items (order_id, course_id, price, discount_price, final_price)
+		SELECT $1, $2, from_minor_units($3, $6), from_minor_units($4, $6), from_minor_units($5, $6)
+		FROM generate_series(1, $7::int)
+		RETURNING id`,
+		orderID, item.CourseID, line.List.Amount, discount, line.Price.Amount, currency, item.Quantity)
+	if err != nil {
+		return err
+	}
+	itemIDs := make([]string, 0, item.Quantity)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		itemIDs = append(itemIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	codes := make([]string, len(itemIDs))
+	emails := make([]string, len(itemIDs))
+	for i := range itemIDs {
+		if codes[i], err = gift.NewCode(); err != nil {
+			return err
+		}
+		if i < len(item.RecipientEmails) {
+			emails[i] = strings.ToLower(strings.TrimSpace(item.RecipientEmails[i]))
+		}
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO gift_vouchers (code, order_id, order_item_id, course_id, purchaser_id, recipient_email, expires_at)
+		SELECT v.code, $1, v.item_id, $2, $3, NULLIF(v.email, ''), $4
+		FROM unnest($5::uuid[], $6::text[], $7::text[]) AS v(item_id, code, email)`,
+		orderID, item.CourseID, purchaserID, expiresAt, itemIDs, codes, emails)
+	return err
+}
+
+// GET /api/gifts
+// Đơn quà tặng của người đang đăng nhập, kèm số voucher đã được đổi
+func (h *GiftHandler) GetGiftOrders(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+	ctx := c.Request.Context()
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders WHERE user_id = $1 AND is_gift", userID).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count gift orders"))
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, giftOrderSelect+`
+		WHERE o.user_id = $1 AND o.is_gift
+		GROUP BY o.id
+		ORDER BY o.created_at DESC
+		LIMIT $2 OFFSET $3`, userID, query.Limit, query.GetOffset())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch gift orders"))
+		return
+	}
+	defer rows.Close()
+
+	orders := []dto.GiftOrderDTO{}
+	for rows.Next() {
+		var order dto.GiftOrderDTO
+		if err := scanGiftOrder(rows, &order); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan gift order"))
+			return
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch gift orders"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Gift orders retrieved successfully",
+		Data: dto.GiftOrderListResponse{
+			GiftOrders: orders,
+			Pagination: dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/gifts/:id
+// Đơn quà tặng và trạng thái từng voucher; chỉ người mua hoặc admin xem được
+func (h *GiftHandler) GetGiftOrder(c *gin.Context) {
+	userID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid gift order ID format"))
+		return
+	}
+
+	order, purchaserID, err := fetchGiftOrder(c.Request.Context(), h.db, id)
+	// Đơn của người khác trả về 404 để không lộ sự tồn tại
+	if err == sql.ErrNoRows || (err == nil && purchaserID != userID && role != "admin") {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeGiftOrderNotFound, "Gift order not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch gift order"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Gift order retrieved successfully",
+		Data:    order,
+	})
+}
+
+// GET /api/gifts/received
+// Voucher chưa đổi được tặng tới email của người đang đăng nhập
+func (h *GiftHandler) GetReceivedVouchers(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	vouchers, err := queryGiftVouchers(c.Request.Context(), h.db, `
+		LOWER(v.recipient_email) = (SELECT LOWER(email) FROM users WHERE id = $1)
+		AND v.redeemed_at IS NULL AND o.payment_status = 'completed'`, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch vouchers"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Vouchers retrieved successfully",
+		Data:    vouchers,
+	})
+}
+
+// POST /api/gifts/redeem
+// Đổi voucher: ghi danh người đang đăng nhập vào khóa học của voucher
+func (h *GiftHandler) RedeemVoucher(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req dto.RedeemVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	code := gift.NormalizeCode(req.Code)
+	if code == "" {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeVoucherNotFound, "Voucher not found"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to redeem voucher"))
+		return
+	}
+	defer tx.Rollback()
+
+	var voucherID, courseID, courseTitle, purchaserID, status string
+	var recipient sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT v.id, v.course_id, c.title, v.purchaser_id, v.recipient_email,`+gift.Status+`
+		FROM gift_vouchers v
+		JOIN orders o ON o.id = v.order_id
+		JOIN courses c ON c.id = v.course_id
+		WHERE v.code = $1
+		FOR UPDATE OF v`, code,
+	).Scan(&voucherID, &courseID, &courseTitle, &purchaserID, &recipient, &status)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeVoucherNotFound, "Voucher not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch voucher"))
+		return
+	}
+
+	switch status {
+	case gift.StatusRedeemed:
+		apierror.Abort(c, apierror.Conflict(apierror.CodeVoucherAlreadyRedeemed, "Voucher has already been redeemed"))
+		return
+	case gift.StatusPendingPayment:
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeVoucherNotPaid, "The gift order has not been paid yet"))
+		return
+	case gift.StatusCancelled:
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeVoucherCancelled, "The gift order was cancelled or refunded"))
+		return
+	case gift.StatusExpired:
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeVoucherExpired, "Voucher has expired"))
+		return
+	}
+
+	var email string
+	if err := tx.QueryRowContext(ctx, "SELECT email FROM users WHERE id = $1", userID).Scan(&email); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch user"))
+		return
+	}
+	if recipient.Valid && !strings.EqualFold(recipient.String, email) {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeVoucherRecipientMismatch,
+			"This voucher was sent to a different email address"))
+		return
+	}
+
+	// Đã ghi danh thì giữ nguyên voucher để tặng người khác (409 ALREADY_ENROLLED)
+	var enrollment dto.EnrollmentResponse
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO enrollments (user_id, course_id)
+		VALUES ($1, $2)
+		RETURNING id, user_id, course_id, enrolled_at, completed_at, progress_percentage,
+		          last_accessed_at, certificate_url`, userID, courseID,
+	).Scan(&enrollment.ID, &enrollment.UserID, &enrollment.CourseID, &enrollment.EnrolledAt,
+		&enrollment.CompletedAt, &enrollment.ProgressPercentage, &enrollment.LastAccessedAt, &enrollment.CertificateURL)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to enroll"))
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE gift_vouchers
+		SET redeemed_by = $2, redeemed_at = CURRENT_TIMESTAMP, enrollment_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, voucherID, userID, enrollment.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to redeem voucher"))
+		return
+	}
+
+	if purchaserID != userID {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO notifications (user_id, title, message, type, related_id)
+			VALUES ($1, $2, $3, 'gift_redeemed', $4)`,
+			purchaserID, "Quà tặng đã được nhận",
+			fmt.Sprintf("%s đã nhận khóa học \"%s\" bạn tặng", email, courseTitle), voucherID)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to notify purchaser"))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to redeem voucher"))
+		return
+	}
+	metrics.EnrollmentsCreated.Inc("voucher")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Voucher redeemed successfully",
+		Data:    enrollment,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/middleware"
+)
+
+// TestMain sets up request binding as routes.SetupRoutes does.
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	apierror.UseJSONFieldNames()
+	apierror.RegisterRules()
+	os.Exit(m.Run())
+}
+
+// arrayConverter lets sqlmock accept the []string arguments that pgx binds
+// as PostgreSQL arrays.
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]string); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return db, mock
+}
+
+// expectRole answers currentUser's role lookup for userID.
+func expectRole(mock sqlmock.Sqlmock, userID, role string) {
+	mock.ExpectQuery(`SELECT role FROM users WHERE id = \$1`).WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(role))
+}
+
+// expectCourseOwner answers requireCourseOwner for userID with the given
+// role, on testCourseID taught by instructorID.
+func expectCourseOwner(mock sqlmock.Sqlmock, userID, role, instructorID string) {
+	expectRole(mock, userID, role)
+	mock.ExpectQuery(`SELECT instructor_id FROM courses WHERE id = \$1`).WithArgs(testCourseID).
+		WillReturnRows(sqlmock.NewRows([]string{"instructor_id"}).AddRow(instructorID))
+}
+
+type testResponse struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   struct {
+		Code    string                `json:"code"`
+		Details []apierror.FieldError `json:"details"`
+	} `json:"error"`
+}
+
+// serve runs one request through handler registered at pattern, as userID
+// when it is not empty (what middleware.Authenticate sets from the token).
+func serve(t *testing.T, method, pattern, path, userID, body string, handler gin.HandlerFunc) (int, testResponse) {
+	t.Helper()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set(middleware.UserIDKey, userID)
+		}
+	})
+	r.Handle(method, pattern, handler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	var res testResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("%s %s: decode %q: %v", method, path, w.Body.String(), err)
+	}
+	return w.Code, res
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+)
+
+// expansion holds the ?include= and ?fields= parameters of a read request.
+// Relations are loaded in batches after the main query; fields trims the
+// rendered JSON of the primary resource.
+type expansion struct {
+	include map[string]bool
+	fields  map[string]bool
+}
+
+// parseExpansion validates ?include= against the relations the resource
+// supports and ?fields= against the JSON fields of resource (a DTO value).
+// A nested relation such as "sections.lectures" implies its parent.
+func parseExpansion(c *gin.Context, resource interface{}, relations ...string) (expansion, *apierror.Error) {
+	e := expansion{include: map[string]bool{}}
+
+	allowed := map[string]bool{}
+	for _, r := range relations {
+		allowed[r] = true
+	}
+	for _, name := range splitList(c.Query("include")) {
+		if !allowed[name] {
+			return e, apierror.BadRequest(apierror.CodeInvalidInclude,
+				"Unknown relation in include; supported: "+strings.Join(relations, ", "))
+		}
+		e.include[name] = true
+		if parent, _, nested := strings.Cut(name, "."); nested {
+			e.include[parent] = true
+		}
+	}
+
+	if names := splitList(c.Query("fields")); len(names) > 0 {
+		known := jsonFields(resource)
+		e.fields = map[string]bool{}
+		for _, name := range names {
+			if !known[name] {
+				return e, apierror.BadRequest(apierror.CodeInvalidFields, "Unknown field "+name+" in fields")
+			}
+			e.fields[name] = true
+		}
+	}
+	return e, nil
+}
+
+func (e expansion) has(relation string) bool {
+	return e.include[relation]
+}
+
+// project applies ?fields= to v. listKey names the array of resources
+// inside v (e.g. "courses"); empty means v is a single resource. Included
+// relations are kept even when not listed in fields.
+func (e expansion) project(v interface{}, listKey string) (interface{}, error) {
+	if e.fields == nil {
+		return v, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	if listKey == "" {
+		return e.trim(obj), nil
+	}
+
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(obj[listKey], &items); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i] = e.trim(items[i])
+	}
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[k] = v
+	}
+	out[listKey] = items
+	return out, nil
+}
+
+func (e expansion) trim(obj map[string]json.RawMessage) map[string]json.RawMessage {
+	for k := range obj {
+		if !e.fields[k] && !e.include[k] {
+			delete(obj, k)
+		}
+	}
+	return obj
+}
+
+// collectIDs returns the distinct non-empty IDs produced by id for n items,
+// in a stable order for the batch queries.
+func collectIDs(n int, id func(i int) string) []string {
+	seen := map[string]bool{}
+	ids := []string{}
+	for i := 0; i < n; i++ {
+		if v := id(i); v != "" && !seen[v] {
+			seen[v] = true
+			ids = append(ids, v)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// jsonFields lists the top-level JSON names of a struct value.
+func jsonFields(v interface{}) map[string]bool {
+	fields := map[string]bool{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"internal/api/dto"
+)
+
+const (
+	testCourseID  = "0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c"
+	testSectionID = "9c8b7a6d-5e4f-4a3b-9c1d-0e1f2a3b4c5d"
+	lecture1ID    = "11111111-1111-4111-8111-111111111111"
+	lecture2ID    = "22222222-2222-4222-8222-222222222222"
+)
+
+func parseQuery(t *testing.T, query string) (expansion, string) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/courses?"+query, nil)
+	e, err := parseExpansion(c, dto.CourseResponse{}, "instructor", "tags", "sections", "sections.lectures")
+	if err != nil {
+		return e, string(err.Code)
+	}
+	return e, ""
+}
+
+func TestParseExpansion(t *testing.T) {
+	tests := []struct {
+		query   string
+		include []string
+		code    string
+	}{
+		{"", nil, ""},
+		{"include=instructor,tags", []string{"instructor", "tags"}, ""},
+		{"include=sections.lectures", []string{"sections", "sections.lectures"}, ""},
+		{"include=%20tags%20,,", []string{"tags"}, ""},
+		{"include=lectures", nil, "INVALID_INCLUDE"},
+		{"fields=id,title", nil, ""},
+		{"fields=id,password_hash", nil, "INVALID_FIELDS"},
+	}
+	for _, tt := range tests {
+		e, code := parseQuery(t, tt.query)
+		if code != tt.code {
+			t.Errorf("%q: code = %q, want %q", tt.query, code, tt.code)
+			continue
+		}
+		if code != "" {
+			continue
+		}
+		for _, name := range tt.include {
+			if !e.has(name) {
+				t.Errorf("%q: %s not included", tt.query, name)
+			}
+		}
+		if len(e.include) != len(tt.include) {
+			t.Errorf("%q: include = %v, want %v", tt.query, e.include, tt.include)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	e, code := parseQuery(t, "fields=id,title&include=tags")
+	if code != "" {
+		t.Fatal(code)
+	}
+	course := dto.CourseResponse{ID: testCourseID, Title: "Giải tích 12", Slug: "giai-tich-12", Tags: []dto.TagDTO{{Name: "Toán"}}}
+	list := struct {
+		Courses []dto.CourseResponse `json:"courses"`
+		Total   int                  `json:"total"`
+	}{Courses: []dto.CourseResponse{course}, Total: 1}
+
+	v, err := e.project(list, "courses")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(v)
+	var got struct {
+		Courses []map[string]json.RawMessage `json:"courses"`
+		Total   int                          `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 1 || len(got.Courses) != 1 {
+		t.Fatalf("projected = %s", raw)
+	}
+	fields := got.Courses[0]
+	_, id := fields["id"]
+	_, title := fields["title"]
+	_, tags := fields["tags"]
+	if len(fields) != 3 || !id || !title || !tags {
+		t.Errorf("course = %s, want id, title and the included tags", raw)
+	}
+
+	// Without ?fields= the value is passed through untouched.
+	if v, _ := (expansion{}).project(course, ""); !reflect.DeepEqual(v, course) {
+		t.Errorf("project without fields = %+v", v)
+	}
+}
+
+// Sections and their lectures are loaded with one query each for the whole
+// page, and the lectures are only an outline.
+func TestExpandCourseSections(t *testing.T) {
+	db, mock := newMockDB(t)
+	const otherCourseID = "7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d"
+	now := time.Now()
+	mock.ExpectQuery(`FROM course_sections\s+WHERE course_id = ANY\(\$1\)`).
+		WithArgs([]string{testCourseID, otherCourseID}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "course_id", "title", "description", "sort_order", "created_at", "updated_at"}).
+			AddRow(testSectionID, testCourseID, "Đạo hàm", nil, 1, now, now))
+	mock.ExpectQuery(`SELECT id, section_id, title, description, content_type, NULL, video_duration, NULL, NULL`).
+		WithArgs([]string{testSectionID}).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "section_id", "title", "description", "content_type",
+			"video_url", "video_duration", "article_content", "file_url", "sort_order", "is_preview",
+			"is_downloadable", "created_at", "updated_at"}).
+			AddRow(lecture1ID, testSectionID, "Giới thiệu", nil, "video", nil, 120, nil, nil, 1, true, false, now, now).
+			AddRow(lecture2ID, testSectionID, "Đạo hàm", nil, "video", nil, 600, nil, nil, 2, false, false, now, now))
+
+	courses := []dto.CourseResponse{{ID: testCourseID}, {ID: otherCourseID}}
+	e := expansion{include: map[string]bool{"sections": true, "sections.lectures": true}}
+	if err := expandCourses(context.Background(), db, e, courses); err != nil {
+		t.Fatal(err)
+	}
+	if len(courses[0].Sections) != 1 || len(courses[0].Sections[0].Lectures) != 2 {
+		t.Fatalf("sections = %+v", courses[0].Sections)
+	}
+	if courses[1].Sections != nil {
+		t.Errorf("course without sections = %+v", courses[1].Sections)
+	}
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+)
+
+// Thống kê được gom theo ngày giờ Việt Nam, giống các materialized view
+var analyticsZone = time.FixedZone("ICT", 7*60*60)
+
+const (
+	analyticsDefaultDays = 30
+	analyticsMaxDays     = 3 * 366
+	analyticsDateLayout  = "2006-01-02"
+)
+
+// InstructorAnalyticsHandler phục vụ /instructors/me/analytics. Số liệu đọc từ
+// các materialized view (migration 011) nên có thể trễ tối đa một chu kỳ
+// analytics.refresh_interval; riêng số câu hỏi chưa trả lời là số liệu trực tiếp.
+type InstructorAnalyticsHandler struct {
+	db *sql.DB
+}
+
+func NewInstructorAnalyticsHandler(db *sql.DB) *InstructorAnalyticsHandler {
+	return &InstructorAnalyticsHandler{db: db}
+}
+
+// analyticsScope là giảng viên đang đăng nhập và khoảng thời gian cần thống kê
+type analyticsScope struct {
+	instructorID string
+	courseID     interface{} // nil = tất cả khóa học của giảng viên
+	rng          dto.AnalyticsRange
+}
+
+// scope xác thực giảng viên, đọc query và kiểm tra course_id thuộc giảng viên.
+func (h *InstructorAnalyticsHandler) scope(c *gin.Context, courseID string) (analyticsScope, bool) {
+	userID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return analyticsScope{}, false
+	}
+	if role != "instructor" && role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeNotInstructor, "User is not an instructor"))
+		return analyticsScope{}, false
+	}
+
+	var query dto.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return analyticsScope{}, false
+	}
+	rng, apiErr := parseAnalyticsRange(query.From, query.To, query.Granularity)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return analyticsScope{}, false
+	}
+	s := analyticsScope{instructorID: userID, rng: rng}
+
+	if courseID == "" {
+		courseID = query.CourseID
+	}
+	if courseID != "" {
+		var owned bool
+		err := h.db.QueryRowContext(c.Request.Context(),
+			"SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND instructor_id = $2)", courseID, userID).Scan(&owned)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to verify course"))
+			return analyticsScope{}, false
+		}
+		if !owned {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+			return analyticsScope{}, false
+		}
+		s.courseID = courseID
+	}
+	return s, true
+}
+
+// parseAnalyticsRange áp dụng mặc định (30 ngày gần nhất, theo ngày) cho
+// from/to/granularity đã được validate định dạng và kiểm tra độ dài khoảng.
+func parseAnalyticsRange(fromDate, toDate, granularity string) (dto.AnalyticsRange, *apierror.Error) {
+	if toDate == "" {
+		toDate = time.Now().In(analyticsZone).Format(analyticsDateLayout)
+	}
+	to, _ := time.Parse(analyticsDateLayout, toDate)
+	from := to.AddDate(0, 0, 1-analyticsDefaultDays)
+	if fromDate != "" {
+		from, _ = time.Parse(analyticsDateLayout, fromDate)
+	}
+	if granularity == "" {
+		granularity = "day"
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days < 1 {
+		return dto.AnalyticsRange{}, apierror.BadRequest(apierror.CodeInvalidDateRange, "from must not be after to")
+	}
+	if days > analyticsMaxDays {
+		return dto.AnalyticsRange{}, apierror.BadRequest(apierror.CodeInvalidDateRange, "Date range must not exceed 3 years")
+	}
+	return dto.AnalyticsRange{
+		From:        from.Format(analyticsDateLayout),
+		To:          to.Format(analyticsDateLayout),
+		Granularity: granularity,
+	}, nil
+}
+
+// args: $1 from, $2 to, $3 granularity, $4 instructor, $5 course_id hoặc NULL
+func (s analyticsScope) args() []interface{} {
+	return []interface{}{s.rng.From, s.rng.To, s.rng.Granularity, s.instructorID, s.courseID}
+}
+
+// bucketQuery gom view theo granularity, trả về đủ mọi bucket trong khoảng
+// (bucket không có dữ liệu có giá trị 0). columns là các biểu thức tổng hợp
+// trên alias v.
+func bucketQuery(view, columns string) string {
+	return `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($3, $1::date::timestamp),
+				date_trunc($3, $2::date::timestamp),
+				('1 ' || $3)::interval
+			)::date AS bucket
+		), v AS (
+			SELECT date_trunc($3, m.day::timestamp)::date AS bucket, m.*
+			FROM ` + view + ` m
+			JOIN courses c ON c.id = m.course_id
+			WHERE c.instructor_id = $4 AND ($5::uuid IS NULL OR c.id = $5::uuid)
+			  AND m.day BETWEEN $1::date AND $2::date
+		)
+		SELECT to_char(b.bucket, 'YYYY-MM-DD'), ` + columns + `
+		FROM buckets b
+		LEFT JOIN v ON v.bucket = b.bucket
+		GROUP BY b.bucket
+		ORDER BY b.bucket
+	`
+}
+
+// GET /api/instructors/me/analytics/summary
+// Tổng hợp trong khoảng thời gian: đăng ký, doanh thu, đánh giá, thời gian
+// xem trung bình và số câu hỏi đang chờ trả lời.
+func (h *InstructorAnalyticsHandler) GetSummary(c *gin.Context) {
+	s, ok := h.scope(c, "")
+	if !ok {
+		return
+	}
+
+	summary := dto.AnalyticsSummary{AnalyticsRange: dto.AnalyticsRange{From: s.rng.From, To: s.rng.To}}
+	var avgRating, avgWatch sql.NullFloat64
+	var revenue string
+	err := h.db.QueryRowContext(c.Request.Context(), `
+		WITH own AS (
+			SELECT id FROM courses
+			WHERE instructor_id = $3 AND ($4::uuid IS NULL OR id = $4::uuid)
+		)
+		SELECT
+			(SELECT COALESCE(SUM(enrollments), 0) FROM mv_course_daily_enrollments
+			  WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date),
+			(SELECT COALESCE(SUM(sales), 0) FROM mv_course_daily_revenue
+			  WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date),
+			COALESCE((
+			  SELECT json_agg(json_build_object('currency', t.currency, 'amount', t.amount) ORDER BY t.currency)
+			  FROM (
+			      SELECT currency, minor_units(SUM(revenue), currency) AS amount FROM mv_course_daily_revenue
+			      WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date
+			      GROUP BY currency
+			  ) t
+			), '[]')::text,
+			(SELECT COALESCE(SUM(reviews), 0) FROM mv_course_daily_ratings
+			  WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date),
+			(SELECT ROUND(SUM(rating_sum)::numeric / NULLIF(SUM(reviews), 0), 2) FROM mv_course_daily_ratings
+			  WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date),
+			(SELECT ROUND(SUM(watch_time)::numeric / NULLIF(SUM(started), 0), 1) FROM mv_lecture_daily_progress
+			  WHERE course_id IN (SELECT id FROM own) AND day BETWEEN $1::date AND $2::date),
+			(SELECT COUNT(*) FROM course_questions
+			  WHERE course_id IN (SELECT id FROM own) AND is_answered = FALSE)
+	`, s.rng.From, s.rng.To, s.instructorID, s.courseID).Scan(
+		&summary.Enrollments, &summary.Sales, &revenue, &summary.Reviews,
+		&avgRating, &avgWatch, &summary.UnansweredQuestions,
+	)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch analytics summary"))
+		return
+	}
+	if err := json.Unmarshal([]byte(revenue), &summary.Revenue); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to decode analytics revenue"))
+		return
+	}
+	if avgRating.Valid {
+		summary.AverageRating = &avgRating.Float64
+	}
+	summary.AverageWatchTime = avgWatch.Float64
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Analytics summary retrieved successfully",
+		Data:    summary,
+	})
+}
+
+// GET /api/instructors/me/analytics/enrollments
+func (h *InstructorAnalyticsHandler) GetEnrollments(c *gin.Context) {
+	s, ok := h.scope(c, "")
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(),
+		bucketQuery("mv_course_daily_enrollments", "COALESCE(SUM(v.enrollments), 0)"), s.args()...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch enrollment analytics"))
+		return
+	}
+	defer rows.Close()
+
+	series := dto.EnrollmentSeries{AnalyticsRange: s.rng, Points: []dto.EnrollmentPoint{}}
+	for rows.Next() {
+		var p dto.EnrollmentPoint
+		if err := rows.Scan(&p.Bucket, &p.Enrollments); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan enrollment analytics"))
+			return
+		}
+		series.Points = append(series.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch enrollment analytics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Enrollment analytics retrieved successfully",
+		Data:    series,
+	})
+}
+
+// GET /api/instructors/me/analytics/revenue
+// Doanh thu theo khóa học, tiền tệ và bucket ngày thanh toán, chỉ tính đơn hàng
+// đã thanh toán.
+func (h *InstructorAnalyticsHandler) GetRevenue(c *gin.Context) {
+	s, ok := h.scope(c, "")
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(), `
+		SELECT to_char(date_trunc($3, v.day::timestamp), 'YYYY-MM-DD') AS bucket,
+			   c.id, c.title, v.currency, SUM(v.sales), minor_units(SUM(v.revenue), v.currency)
+		FROM mv_course_daily_revenue v
+		JOIN courses c ON c.id = v.course_id
+		WHERE c.instructor_id = $4 AND ($5::uuid IS NULL OR c.id = $5::uuid)
+		  AND v.day BETWEEN $1::date AND $2::date
+		GROUP BY bucket, c.id, c.title, v.currency
+		ORDER BY bucket, c.title, v.currency
+	`, s.args()...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue analytics"))
+		return
+	}
+	defer rows.Close()
+
+	series := dto.RevenueSeries{AnalyticsRange: s.rng, Points: []dto.RevenuePoint{}}
+	for rows.Next() {
+		var p dto.RevenuePoint
+		if err := rows.Scan(&p.Bucket, &p.CourseID, &p.CourseTitle, &p.Currency, &p.Sales, &p.Revenue); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan revenue analytics"))
+			return
+		}
+		series.Points = append(series.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue analytics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Revenue analytics retrieved successfully",
+		Data:    series,
+	})
+}
+
+// GET /api/instructors/me/analytics/ratings
+// Xu hướng đánh giá: số review đã duyệt và điểm trung bình của từng bucket.
+func (h *InstructorAnalyticsHandler) GetRatings(c *gin.Context) {
+	s, ok := h.scope(c, "")
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(), bucketQuery("mv_course_daily_ratings",
+		"COALESCE(SUM(v.reviews), 0), ROUND(SUM(v.rating_sum)::numeric / NULLIF(SUM(v.reviews), 0), 2)"),
+		s.args()...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch rating analytics"))
+		return
+	}
+	defer rows.Close()
+
+	series := dto.RatingSeries{AnalyticsRange: s.rng, Points: []dto.RatingPoint{}}
+	for rows.Next() {
+		var p dto.RatingPoint
+		var avg sql.NullFloat64
+		if err := rows.Scan(&p.Bucket, &p.Reviews, &avg); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan rating analytics"))
+			return
+		}
+		if avg.Valid {
+			p.AverageRating = &avg.Float64
+		}
+		series.Points = append(series.Points, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch rating analytics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Rating analytics retrieved successfully",
+		Data:    series,
+	})
+}
+
+// GET /api/instructors/me/analytics/courses/:course_id/funnel
+// Funnel hoàn thành theo section và tỉ lệ rời bỏ theo bài giảng, tính trên
+// học viên bắt đầu học trong khoảng thời gian.
+func (h *InstructorAnalyticsHandler) GetCourseFunnel(c *gin.Context) {
+	courseID := c.Param("course_id")
+	if _, err := uuid.Parse(courseID); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid course ID format"))
+		return
+	}
+
+	s, ok := h.scope(c, courseID)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	funnel := dto.CourseFunnel{
+		AnalyticsRange: dto.AnalyticsRange{From: s.rng.From, To: s.rng.To},
+		CourseID:       courseID,
+		Sections:       []dto.SectionFunnel{},
+		Lectures:       []dto.LectureDropOff{},
+	}
+	if err := h.db.QueryRowContext(ctx, "SELECT title FROM courses WHERE id = $1", courseID).Scan(&funnel.Title); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+
+	// Hoàn thành section = đã hoàn thành mọi bài giảng hiện có của section
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT s.id, s.title, s.sort_order, lc.total,
+			   COUNT(p.user_id),
+			   COUNT(p.user_id) FILTER (WHERE lc.total > 0 AND p.completed_lectures >= lc.total)
+		FROM course_sections s
+		CROSS JOIN LATERAL (SELECT COUNT(*)::int AS total FROM course_lectures l WHERE l.section_id = s.id) lc
+		LEFT JOIN mv_section_learner_progress p
+			   ON p.section_id = s.id AND p.started_on BETWEEN $2::date AND $3::date
+		WHERE s.course_id = $1
+		GROUP BY s.id, s.title, s.sort_order, lc.total
+		ORDER BY s.sort_order ASC
+	`, courseID, s.rng.From, s.rng.To)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch section funnel"))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f dto.SectionFunnel
+		if err := rows.Scan(&f.SectionID, &f.Title, &f.SortOrder, &f.TotalLectures, &f.Learners, &f.Completed); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan section funnel"))
+			return
+		}
+		f.CompletionRate = ratio(f.Completed, f.Learners)
+		funnel.Sections = append(funnel.Sections, f)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch section funnel"))
+		return
+	}
+
+	lectureRows, err := h.db.QueryContext(ctx, `
+		SELECT l.id, l.section_id, l.title,
+			   COALESCE(SUM(v.started), 0), COALESCE(SUM(v.completed), 0), COALESCE(SUM(v.watch_time), 0)
+		FROM course_lectures l
+		JOIN course_sections s ON s.id = l.section_id
+		LEFT JOIN mv_lecture_daily_progress v
+			   ON v.lecture_id = l.id AND v.day BETWEEN $2::date AND $3::date
+		WHERE s.course_id = $1
+		GROUP BY l.id, s.sort_order
+		ORDER BY s.sort_order ASC, l.sort_order ASC
+	`, courseID, s.rng.From, s.rng.To)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch lecture drop-off"))
+		return
+	}
+	defer lectureRows.Close()
+
+	var prevStarted int64
+	for i := 0; lectureRows.Next(); i++ {
+		var d dto.LectureDropOff
+		var watchTime int64
+		if err := lectureRows.Scan(&d.LectureID, &d.SectionID, &d.Title, &d.Started, &d.Completed, &watchTime); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan lecture drop-off"))
+			return
+		}
+		if d.Started > 0 {
+			d.AverageWatchTime = float64(watchTime) / float64(d.Started)
+		}
+		// Bài đầu tiên không có bài trước để so sánh
+		if i > 0 && prevStarted > d.Started {
+			d.DropOff = prevStarted - d.Started
+		}
+		if i > 0 {
+			d.DropOffRate = ratio(d.DropOff, prevStarted)
+		}
+		prevStarted = d.Started
+		funnel.Lectures = append(funnel.Lectures, d)
+	}
+	if err := lectureRows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch lecture drop-off"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Course funnel retrieved successfully",
+		Data:    funnel,
+	})
+}
+
+// ratio trả về part/whole làm tròn 4 chữ số, nil khi whole = 0
+func ratio(part, whole int64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	r := float64(int64(float64(part)/float64(whole)*10000+0.5)) / 10000
+	return &r
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+func TestGetSummaryRevenuePerCurrency(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "instructor")
+	mock.ExpectQuery(`GROUP BY currency`).
+		WillReturnRows(sqlmock.NewRows([]string{"enrollments", "sales", "revenue", "reviews", "rating", "watch", "questions"}).
+			AddRow(4, 3, `[{"currency":"USD","amount":1999},{"currency":"VND","amount":998000}]`, 1, 4.5, 300.0, 2))
+
+	status, res := serve(t, http.MethodGet, "/instructors/me/analytics/summary", "/instructors/me/analytics/summary",
+		testLearnerID, "", NewInstructorAnalyticsHandler(db).GetSummary)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var summary dto.AnalyticsSummary
+	if err := json.Unmarshal(res.Data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Sales != 3 || len(summary.Revenue) != 2 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	if usd := summary.Revenue[0]; usd.Currency != "USD" || usd.Amount != 1999 {
+		t.Errorf("USD revenue = %+v", usd)
+	}
+}
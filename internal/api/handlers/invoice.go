@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/invoice"
+)
+
+// InvoiceHandler xuất và cho tải hóa đơn của đơn hàng đã thanh toán. Người mua
+// xuất hóa đơn một lần (có hoặc không có thông tin công ty) rồi mới tải được;
+// file HTML / PDF render một lần rồi lưu vào storage.
+type InvoiceHandler struct {
+	db        *sql.DB
+	publisher *invoice.Publisher
+	opts      invoice.Options
+}
+
+func NewInvoiceHandler(db *sql.DB, publisher *invoice.Publisher, opts invoice.Options) *InvoiceHandler {
+	return &InvoiceHandler{db: db, publisher: publisher, opts: opts}
+}
+
+// GET /api/orders/:id/invoice?format=pdf|html
+// Tải file hóa đơn đã xuất; 404 nếu chưa xuất. Không tự xuất ở đây, để người
+// mua còn gửi được thông tin công ty qua POST
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	orderID, ok := h.authorizeOrder(c)
+	if !ok {
+		return
+	}
+
+	var query dto.InvoiceQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	format := query.Format
+	if format == "" {
+		format = invoice.PDF
+	}
+	ctx := c.Request.Context()
+
+	inv, err := invoice.Load(ctx, h.db, orderID)
+	if err != nil {
+		apierror.Abort(c, invoiceError(err))
+		return
+	}
+
+	if err := h.publisher.Publish(ctx, inv); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to render invoice"))
+		return
+	}
+	file, err := h.publisher.Open(ctx, inv, format)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to open invoice file"))
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", invoice.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+inv.No+"."+format+`"`)
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		// Header đã gửi, chỉ ghi log được (file bị cắt ngang)
+		middleware.Log(c).WithError(err).WithField("invoice_no", inv.No).Error("Failed to send invoice file")
+	}
+}
+
+// POST /api/orders/:id/invoice
+// Xuất hóa đơn, kèm thông tin công ty nếu có (body rỗng là hóa đơn cá nhân).
+// Hóa đơn đã xuất thì không sửa được
+func (h *InvoiceHandler) IssueInvoice(c *gin.Context) {
+	orderID, ok := h.authorizeOrder(c)
+	if !ok {
+		return
+	}
+
+	var req dto.IssueInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	var company *invoice.Company
+	if req.CompanyName != "" {
+		company = &invoice.Company{Name: req.CompanyName, TaxCode: req.TaxCode, Address: req.Address}
+	}
+	ctx := c.Request.Context()
+
+	inv, err := invoice.Issue(ctx, h.db, orderID, company, h.opts)
+	if err != nil {
+		apierror.Abort(c, invoiceError(err))
+		return
+	}
+
+	// Hóa đơn đã được lưu; render lỗi thì lần tải sau sẽ render lại
+	if err := h.publisher.Publish(ctx, inv); err != nil {
+		middleware.Log(c).WithError(err).WithField("invoice_no", inv.No).Warn("Failed to render invoice")
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Invoice issued successfully",
+		Data:    invoiceToDTO(inv),
+	})
+}
+
+// authorizeOrder chỉ cho người mua và admin truy cập hóa đơn của đơn hàng
+func (h *InvoiceHandler) authorizeOrder(c *gin.Context) (string, bool) {
+	userID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return "", false
+	}
+
+	orderID := c.Param("id")
+	if _, err := uuid.Parse(orderID); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid order ID format"))
+		return "", false
+	}
+
+	var ownerID string
+	err := h.db.QueryRowContext(c.Request.Context(), "SELECT user_id FROM orders WHERE id = $1", orderID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrderNotFound, "Order not found"))
+		return "", false
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch order"))
+		return "", false
+	}
+	if ownerID != userID && role != "admin" {
+		// Không để lộ đơn hàng của người khác
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrderNotFound, "Order not found"))
+		return "", false
+	}
+	return orderID, true
+}
+
+func invoiceError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, invoice.ErrOrderNotFound):
+		return apierror.NotFound(apierror.CodeOrderNotFound, "Order not found")
+	case errors.Is(err, invoice.ErrNotFound):
+		return apierror.NotFound(apierror.CodeInvoiceNotFound, "Invoice not found")
+	case errors.Is(err, invoice.ErrOrderNotPaid):
+		return apierror.Unprocessable(apierror.CodeOrderNotPaid, "Invoices are only issued for completed orders")
+	case errors.Is(err, invoice.ErrAlreadyIssued):
+		return apierror.Conflict(apierror.CodeInvoiceAlreadyIssued, "An invoice has already been issued for this order")
+	}
+	return apierror.Internal(err, "Failed to issue invoice")
+}
+
+func invoiceToDTO(inv *invoice.Invoice) dto.InvoiceDTO {
+	out := dto.InvoiceDTO{
+		ID:         inv.ID,
+		OrderID:    inv.OrderID,
+		InvoiceNo:  inv.No,
+		IssuedAt:   inv.IssuedAt,
+		BuyerName:  inv.Buyer,
+		BuyerEmail: inv.Email,
+		Currency:   inv.Currency,
+		CouponCode: inv.Coupon,
+		Subtotal:   inv.Subtotal,
+		Discount:   inv.Discount,
+		VATRate:    inv.VATRate,
+		NetAmount:  inv.NetAmount,
+		VATAmount:  inv.VATAmount,
+		Total:      inv.Total,
+		Items:      make([]dto.InvoiceItemDTO, 0, len(inv.Items)),
+	}
+	if co := inv.Company; co != nil {
+		out.BuyerCompany, out.BuyerTaxCode, out.BuyerAddress = nonEmpty(co.Name), nonEmpty(co.TaxCode), nonEmpty(co.Address)
+	}
+	for _, it := range inv.Items {
+		out.Items = append(out.Items, dto.InvoiceItemDTO{
+			LineNo:      it.LineNo,
+			CourseID:    it.CourseID,
+			Description: it.Description,
+			UnitPrice:   it.UnitPrice,
+			Discount:    it.Discount,
+			NetAmount:   it.NetAmount,
+			VATAmount:   it.VATAmount,
+			Amount:      it.Amount,
+		})
+	}
+	return out
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
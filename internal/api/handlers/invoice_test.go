@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/invoice"
+)
+
+func expectOrderOwner(mock sqlmock.Sqlmock, ownerID string) {
+	mock.ExpectQuery(`SELECT user_id FROM orders WHERE id = \$1`).WithArgs(testOrderID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(ownerID))
+}
+
+// Downloading must not issue the invoice, or the buyer could no longer add
+// company details with POST.
+func TestGetInvoiceBeforeIssue(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "student")
+	expectOrderOwner(mock, testLearnerID)
+	mock.ExpectQuery(`FROM invoices i`).WithArgs(testOrderID).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	status, res := serve(t, http.MethodGet, "/orders/:id/invoice", "/orders/"+testOrderID+"/invoice",
+		testLearnerID, "", NewInvoiceHandler(db, nil, invoice.Options{}).GetInvoice)
+	if status != http.StatusNotFound || res.Error.Code != "INVOICE_NOT_FOUND" {
+		t.Fatalf("got %d %s, want 404 INVOICE_NOT_FOUND", status, res.Error.Code)
+	}
+}
+
+func TestIssueInvoiceRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		// Company details are optional; the order is checked next.
+		{"personal invoice", "", http.StatusUnprocessableEntity, "ORDER_NOT_PAID"},
+		{"company invoice", `{"company_name":"Công ty A","tax_code":"0101234567","address":"Hà Nội"}`,
+			http.StatusUnprocessableEntity, "ORDER_NOT_PAID"},
+		{"partial company", `{"company_name":"Công ty A"}`, http.StatusBadRequest, "VALIDATION_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			expectRole(mock, testLearnerID, "student")
+			expectOrderOwner(mock, testLearnerID)
+			if tt.wantCode == "ORDER_NOT_PAID" {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT payment_status FROM orders`).WithArgs(testOrderID).
+					WillReturnRows(sqlmock.NewRows([]string{"payment_status"}).AddRow("pending"))
+				mock.ExpectRollback()
+			}
+
+			status, res := serve(t, http.MethodPost, "/orders/:id/invoice", "/orders/"+testOrderID+"/invoice",
+				testLearnerID, tt.body, NewInvoiceHandler(db, nil, invoice.Options{}).IssueInvoice)
+			if status != tt.wantStatus || res.Error.Code != tt.wantCode {
+				t.Fatalf("got %d %s, want %d %s", status, res.Error.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+
+	"internal/api/dto"
+)
+
+// Batch loaders for ?include=. Each runs one query for all IDs of a page
+// (WHERE ... = ANY($1)) instead of one query per row.
+
+func loadUserSummaries(ctx context.Context, db *sql.DB, ids []string) (map[string]*dto.UserDTO, error) {
+	users := map[string]*dto.UserDTO{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, username, first_name, last_name, avatar_url
+		FROM users WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u dto.UserDTO
+		if err := rows.Scan(&u.ID, &u.Username, &u.FirstName, &u.LastName, &u.AvatarURL); err != nil {
+			return nil, err
+		}
+		users[u.ID] = &u
+	}
+	return users, rows.Err()
+}
+
+func loadCourseSummaries(ctx context.Context, db *sql.DB, ids []string) (map[string]*dto.CourseDTO, error) {
+	courses := map[string]*dto.CourseDTO{}
+	if len(ids) == 0 {
+		return courses, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, title, slug, thumbnail_url, instructor_id,
+		       minor_units(price, currency), minor_units(discount_price, currency), currency, rating
+		FROM courses WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var co dto.CourseDTO
+		err := rows.Scan(&co.ID, &co.Title, &co.Slug, &co.ThumbnailURL, &co.InstructorID,
+			&co.Price, &co.DiscountPrice, &co.Currency, &co.Rating)
+		if err != nil {
+			return nil, err
+		}
+		courses[co.ID] = &co
+	}
+	return courses, rows.Err()
+}
+
+func loadLectureSummaries(ctx context.Context, db *sql.DB, ids []string) (map[string]*dto.CourseLectureDTO, error) {
+	lectures := map[string]*dto.CourseLectureDTO{}
+	if len(ids) == 0 {
+		return lectures, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, section_id, title, content_type, sort_order
+		FROM course_lectures WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l dto.CourseLectureDTO
+		if err := rows.Scan(&l.ID, &l.SectionID, &l.Title, &l.ContentType, &l.SortOrder); err != nil {
+			return nil, err
+		}
+		lectures[l.ID] = &l
+	}
+	return lectures, rows.Err()
+}
+
+func loadCategories(ctx context.Context, db *sql.DB, ids []string) (map[string]*dto.CategoryResponse, error) {
+	categories := map[string]*dto.CategoryResponse{}
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, name, slug, description, icon_url, parent_id, sort_order, is_active, created_at, updated_at
+		FROM categories WHERE id = ANY($1)
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cat dto.CategoryResponse
+		err := rows.Scan(&cat.ID, &cat.Name, &cat.Slug, &cat.Description, &cat.IconURL,
+			&cat.ParentID, &cat.SortOrder, &cat.IsActive, &cat.CreatedAt, &cat.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		categories[cat.ID] = &cat
+	}
+	return categories, rows.Err()
+}
+
+// loadCourseTags returns the tags of each course, keyed by course ID.
+func loadCourseTags(ctx context.Context, db *sql.DB, courseIDs []string) (map[string][]dto.TagDTO, error) {
+	tags := map[string][]dto.TagDTO{}
+	if len(courseIDs) == 0 {
+		return tags, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT ct.course_id, t.id, t.name, t.slug, t.description, t.color, t.created_at
+		FROM course_tags ct
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE ct.course_id = ANY($1)
+		ORDER BY t.name ASC
+	`, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var courseID string
+		var t dto.TagDTO
+		if err := rows.Scan(&courseID, &t.ID, &t.Name, &t.Slug, &t.Description, &t.Color, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags[courseID] = append(tags[courseID], t)
+	}
+	return tags, rows.Err()
+}
+
+// loadCourseSections returns the sections of each course in order, keyed by
+// course ID, with their lectures when withLectures is set.
+func loadCourseSections(ctx context.Context, db *sql.DB, courseIDs []string, withLectures bool) (map[string][]dto.CourseSectionResponse, error) {
+	sections := map[string][]dto.CourseSectionResponse{}
+	if len(courseIDs) == 0 {
+		return sections, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, course_id, title, description, sort_order, created_at, updated_at
+		FROM course_sections
+		WHERE course_id = ANY($1)
+		ORDER BY sort_order ASC
+	`, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sectionIDs []string
+	for rows.Next() {
+		var s dto.CourseSectionResponse
+		if err := rows.Scan(&s.ID, &s.CourseID, &s.Title, &s.Description, &s.SortOrder, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		sections[s.CourseID] = append(sections[s.CourseID], s)
+		sectionIDs = append(sectionIDs, s.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !withLectures || len(sectionIDs) == 0 {
+		return sections, nil
+	}
+
+	lectures, err := loadSectionLectures(ctx, db, sectionIDs, false)
+	if err != nil {
+		return nil, err
+	}
+	for courseID := range sections {
+		for i := range sections[courseID] {
+			sections[courseID][i].Lectures = lectures[sections[courseID][i].ID]
+		}
+	}
+	return sections, nil
+}
+
+// loadSectionLectures returns the lectures of each section in order, keyed
+// by section ID. Unless withContent is set the video URL, article and file
+// URL are left out, so the lectures only outline the course.
+func loadSectionLectures(ctx context.Context, db *sql.DB, sectionIDs []string, withContent bool) (map[string][]dto.CourseLectureResponse, error) {
+	content := "NULL, video_duration, NULL, NULL"
+	if withContent {
+		content = "video_url, video_duration, article_content, file_url"
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, section_id, title, description, content_type, `+content+`,
+			   sort_order, is_preview, is_downloadable, created_at, updated_at
+		FROM course_lectures
+		WHERE section_id = ANY($1)
+		ORDER BY sort_order ASC
+	`, sectionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lectures := map[string][]dto.CourseLectureResponse{}
+	for rows.Next() {
+		var l dto.CourseLectureResponse
+		err := rows.Scan(&l.ID, &l.SectionID, &l.Title, &l.Description, &l.ContentType, &l.VideoURL,
+			&l.VideoDuration, &l.ArticleContent, &l.FileURL, &l.SortOrder, &l.IsPreview,
+			&l.IsDownloadable, &l.CreatedAt, &l.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		lectures[l.SectionID] = append(lectures[l.SectionID], l)
+	}
+	return lectures, rows.Err()
+}
+
+// loadQuestionAnswers returns the answers of each question, oldest first,
+// keyed by question ID.
+func loadQuestionAnswers(ctx context.Context, db *sql.DB, questionIDs []string) (map[string][]dto.CourseAnswerDTO, error) {
+	answers := map[string][]dto.CourseAnswerDTO{}
+	if len(questionIDs) == 0 {
+		return answers, nil
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, question_id, user_id, answer, is_instructor_answer, votes, created_at, updated_at
+		FROM course_answers
+		WHERE question_id = ANY($1)
+		ORDER BY created_at ASC
+	`, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a dto.CourseAnswerDTO
+		err := rows.Scan(&a.ID, &a.QuestionID, &a.UserID, &a.Answer, &a.IsInstructorAnswer,
+			&a.Votes, &a.CreatedAt, &a.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		answers[a.QuestionID] = append(answers[a.QuestionID], a)
+	}
+	return answers, rows.Err()
+}
+
+// expandCourses attaches the relations requested in e to courses.
+func expandCourses(ctx context.Context, db *sql.DB, e expansion, courses []dto.CourseResponse) error {
+	courseIDs := collectIDs(len(courses), func(i int) string { return courses[i].ID })
+
+	if e.has("instructor") {
+		users, err := loadUserSummaries(ctx, db, collectIDs(len(courses), func(i int) string { return courses[i].InstructorID }))
+		if err != nil {
+			return err
+		}
+		for i := range courses {
+			courses[i].Instructor = users[courses[i].InstructorID]
+		}
+	}
+	if e.has("category") {
+		categories, err := loadCategories(ctx, db, collectIDs(len(courses), func(i int) string { return courses[i].CategoryID }))
+		if err != nil {
+			return err
+		}
+		for i := range courses {
+			courses[i].Category = categories[courses[i].CategoryID]
+		}
+	}
+	if e.has("tags") {
+		tags, err := loadCourseTags(ctx, db, courseIDs)
+		if err != nil {
+			return err
+		}
+		for i := range courses {
+			courses[i].Tags = tags[courses[i].ID]
+		}
+	}
+	if e.has("sections") {
+		sections, err := loadCourseSections(ctx, db, courseIDs, e.has("sections.lectures"))
+		if err != nil {
+			return err
+		}
+		for i := range courses {
+			courses[i].Sections = sections[courses[i].ID]
+		}
+	}
+	return nil
+}
+
+// expandQuestions attaches the relations requested in e to questions.
+func expandQuestions(ctx context.Context, db *sql.DB, e expansion, questions []dto.CourseQuestionDTO) error {
+	if e.has("course") {
+		courses, err := loadCourseSummaries(ctx, db, collectIDs(len(questions), func(i int) string { return questions[i].CourseID }))
+		if err != nil {
+			return err
+		}
+		for i := range questions {
+			questions[i].Course = courses[questions[i].CourseID]
+		}
+	}
+	if e.has("lecture") {
+		lectureIDs := collectIDs(len(questions), func(i int) string {
+			if questions[i].LectureID == nil {
+				return ""
+			}
+			return *questions[i].LectureID
+		})
+		lectures, err := loadLectureSummaries(ctx, db, lectureIDs)
+		if err != nil {
+			return err
+		}
+		for i := range questions {
+			if questions[i].LectureID != nil {
+				questions[i].Lecture = lectures[*questions[i].LectureID]
+			}
+		}
+	}
+	if e.has("answers") {
+		answers, err := loadQuestionAnswers(ctx, db, collectIDs(len(questions), func(i int) string { return questions[i].ID }))
+		if err != nil {
+			return err
+		}
+		for i := range questions {
+			questions[i].Answers = answers[questions[i].ID]
+		}
+	}
+
+	// Question and answer authors share one users query.
+	if e.has("user") || e.has("answers.user") {
+		var userIDs []string
+		for _, q := range questions {
+			if e.has("user") {
+				userIDs = append(userIDs, q.UserID)
+			}
+			if e.has("answers.user") {
+				for _, a := range q.Answers {
+					userIDs = append(userIDs, a.UserID)
+				}
+			}
+		}
+		users, err := loadUserSummaries(ctx, db, collectIDs(len(userIDs), func(i int) string { return userIDs[i] }))
+		if err != nil {
+			return err
+		}
+		for i := range questions {
+			if e.has("user") {
+				questions[i].User = users[questions[i].UserID]
+			}
+			if e.has("answers.user") {
+				for j := range questions[i].Answers {
+					questions[i].Answers[j].User = users[questions[i].Answers[j].UserID]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expandEnrollments attaches the relations requested in e to enrollments.
+func expandEnrollments(ctx context.Context, db *sql.DB, e expansion, enrollments []dto.EnrollmentResponse) error {
+	if e.has("user") {
+		users, err := loadUserSummaries(ctx, db, collectIDs(len(enrollments), func(i int) string { return enrollments[i].UserID }))
+		if err != nil {
+			return err
+		}
+		for i := range enrollments {
+			enrollments[i].User = users[enrollments[i].UserID]
+		}
+	}
+	if e.has("course") {
+		courses, err := loadCourseSummaries(ctx, db, collectIDs(len(enrollments), func(i int) string { return enrollments[i].CourseID }))
+		if err != nil {
+			return err
+		}
+		for i := range enrollments {
+			enrollments[i].Course = courses[enrollments[i].CourseID]
+		}
+	}
+	return nil
+}
+
+// expandReviews attaches the relations requested in e to reviews.
+func expandReviews(ctx context.Context, db *sql.DB, e expansion, reviews []dto.CourseReviewDTO) error {
+	if e.has("user") {
+		users, err := loadUserSummaries(ctx, db, collectIDs(len(reviews), func(i int) string { return reviews[i].UserID }))
+		if err != nil {
+			return err
+		}
+		for i := range reviews {
+			reviews[i].User = users[reviews[i].UserID]
+		}
+	}
+	if e.has("course") {
+		courses, err := loadCourseSummaries(ctx, db, collectIDs(len(reviews), func(i int) string { return reviews[i].CourseID }))
+		if err != nil {
+			return err
+		}
+		for i := range reviews {
+			reviews[i].Course = courses[reviews[i].CourseID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/metrics"
+	"internal/money"
+	"internal/orders"
+)
+
+// OrderHandler ghi nhận kết quả thanh toán của đơn hàng (mua khóa học, quà
+// tặng, suất của tổ chức) do cổng thanh toán báo về.
+type OrderHandler struct {
+	db *sql.DB
+}
+
+func NewOrderHandler(db *sql.DB) *OrderHandler {
+	return &OrderHandler{db: db}
+}
+
+// recordSettledOrder cập nhật các counter nghiệp vụ khi đơn hoàn tất thanh
+// toán.
+func recordSettledOrder(order *orders.Order) {
+	if order.PaymentStatus != orders.StatusCompleted {
+		return
+	}
+	method := order.PaymentMethod
+	if method == "" {
+		method = "unknown"
+	}
+	metrics.OrdersCompleted.Inc(method)
+	metrics.RevenueTotal.Add(money.New(order.FinalAmount, order.Currency).Float64(), order.Currency)
+	if order.CouponType != "" {
+		metrics.CouponRedemptions.Inc(order.CouponType)
+	}
+	if order.Enrolled > 0 {
+		metrics.EnrollmentsCreated.Add(float64(order.Enrolled), "order")
+	}
+}
+
+// PUT /api/admin/orders/:id/status
+// Admin ghi nhận kết quả thanh toán đã đối soát của một đơn đang chờ:
+// completed ghi danh người mua (đơn thường) hoặc mở voucher/suất của đơn;
+// failed hủy chúng. Mỗi lần ghi nhận được lưu vào order_settlements
+func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid order ID format"))
+		return
+	}
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	order, err := orders.Settle(c.Request.Context(), h.db, id, orders.Settlement{
+		Status:        req.Status,
+		TransactionID: req.TransactionID,
+		Reason:        req.Reason,
+		SettledBy:     c.GetString(middleware.UserIDKey),
+	}, time.Now())
+	switch {
+	case errors.Is(err, orders.ErrNotFound):
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrderNotFound, "Order not found"))
+		return
+	case errors.Is(err, orders.ErrNoTransaction):
+		apierror.Abort(c, apierror.BadRequest(apierror.CodeValidationFailed, "transaction_id is required to complete an order"))
+		return
+	case errors.Is(err, orders.ErrInvalidTransition):
+		apierror.Abort(c, apierror.Conflict(apierror.CodeInvalidPaymentStatus, "Order is no longer pending"))
+		return
+	case err != nil:
+		apierror.Abort(c, apierror.Internal(err, "Failed to update order"))
+		return
+	}
+
+	middleware.Log(c).WithField("order_id", id).WithField("kind", order.Kind).
+		WithField("status", order.PaymentStatus).WithField("reason", req.Reason).Info("Order settled")
+	recordSettledOrder(order)
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Order updated successfully",
+		Data: dto.OrderStatusDTO{
+			ID:                 order.ID,
+			Kind:               order.Kind,
+			PaymentStatus:      order.PaymentStatus,
+			Currency:           order.Currency,
+			FinalAmount:        order.FinalAmount,
+			TransactionID:      order.TransactionID,
+			EnrollmentsCreated: order.Enrolled,
+		},
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+	"internal/metrics"
+	"internal/orders"
+)
+
+const testOrderID = "7e6d5c4b-3a29-4180-9f7e-6d5c4b3a2918"
+
+func TestUpdateOrderStatusCompletesGiftOrder(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "admin")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM orders`).WithArgs(testOrderID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "payment_status", "is_gift", "for_organization", "currency", "final_amount", "payment_method", "coupon_type"}).
+			AddRow(testLearnerID, "pending", true, false, "VND", 2598000, "momo", ""))
+	mock.ExpectExec(`UPDATE orders SET payment_status`).
+		WithArgs(testOrderID, "completed", "PAY-42", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO order_settlements`).
+		WithArgs(testOrderID, "completed", "PAY-42", "Khớp sao kê ngày 01/03", testLearnerID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	status, res := serve(t, http.MethodPut, "/admin/orders/:id/status", "/admin/orders/"+testOrderID+"/status",
+		testLearnerID, `{"status":"completed","transaction_id":"PAY-42","reason":"Khớp sao kê ngày 01/03"}`,
+		NewOrderHandler(db).UpdateOrderStatus)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var order dto.OrderStatusDTO
+	if err := json.Unmarshal(res.Data, &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.PaymentStatus != "completed" || order.Kind != "gift" || order.FinalAmount != 2598000 {
+		t.Errorf("order = %+v", order)
+	}
+}
+
+func TestUpdateOrderStatusRequiresAdmin(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "student")
+
+	status, res := serve(t, http.MethodPut, "/admin/orders/:id/status", "/admin/orders/"+testOrderID+"/status",
+		testLearnerID, `{"status":"completed"}`, NewOrderHandler(db).UpdateOrderStatus)
+	if status != http.StatusForbidden || res.Error.Code != "FORBIDDEN" {
+		t.Fatalf("got %d %s, want 403 FORBIDDEN", status, res.Error.Code)
+	}
+}
+
+// An admin can only settle an order by saying why, and completing one needs
+// the provider's transaction ID; nothing is written otherwise.
+func TestUpdateOrderStatusNeedsAuditTrail(t *testing.T) {
+	for _, body := range []string{
+		`{"status":"completed","transaction_id":"PAY-42"}`,
+		`{"status":"completed","reason":"Khách gửi ảnh chuyển khoản"}`,
+		`{"status":"failed","reason":""}`,
+	} {
+		db, mock := newMockDB(t)
+		expectRole(mock, testLearnerID, "admin")
+
+		status, res := serve(t, http.MethodPut, "/admin/orders/:id/status", "/admin/orders/"+testOrderID+"/status",
+			testLearnerID, body, NewOrderHandler(db).UpdateOrderStatus)
+		if status != http.StatusBadRequest || res.Error.Code != "VALIDATION_FAILED" {
+			t.Errorf("%s: got %d %s, want 400 VALIDATION_FAILED", body, status, res.Error.Code)
+		}
+	}
+}
+
+func TestRecordSettledOrder(t *testing.T) {
+	recordSettledOrder(&orders.Order{PaymentStatus: orders.StatusFailed, PaymentMethod: "test-wallet", Currency: "EUR"})
+	recordSettledOrder(&orders.Order{
+		PaymentStatus: orders.StatusCompleted, PaymentMethod: "test-wallet", CouponType: "fixed",
+		Currency: "EUR", FinalAmount: 1999, Enrolled: 2,
+	})
+
+	var buf bytes.Buffer
+	metrics.Default.Write(&buf)
+	for _, want := range []string{
+		`orders_completed_total{payment_method="test-wallet"} 1`,
+		`revenue_total{currency="EUR"} 19.99`,
+		`coupon_redemptions_total{discount_type="fixed"} 1`,
+		`enrollments_created_total{source="order"} 2`,
+	} {
+		if !strings.Contains(buf.String(), want+"\n") {
+			t.Errorf("metrics output lacks %s", want)
+		}
+	}
+}
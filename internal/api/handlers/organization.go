@@ -0,0 +1,503 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/organization"
+)
+
+// OrganizationHandler quản lý tổ chức, thành viên, suất học và khóa học giao
+// cho thành viên. Mọi dữ liệu chỉ đọc được trong tổ chức mà người đang đăng
+// nhập là thành viên; quy tắc vai trò nằm trong internal/organization.
+type OrganizationHandler struct {
+	db *sql.DB
+}
+
+func NewOrganizationHandler(db *sql.DB) *OrganizationHandler {
+	return &OrganizationHandler{db: db}
+}
+
+// membership kiểm tra người đang đăng nhập có vai trò ít nhất min trong tổ
+// chức :id và trả về vai trò đó; admin được xem như owner. Người ngoài tổ
+// chức nhận 404 để không lộ sự tồn tại của tổ chức.
+func (h *OrganizationHandler) membership(c *gin.Context, min string) (orgID, userID, role string, ok bool) {
+	userID, siteRole, ok := currentUser(c, h.db)
+	if !ok {
+		return "", "", "", false
+	}
+	orgID = c.Param("id")
+	if _, err := uuid.Parse(orgID); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid organization ID format"))
+		return "", "", "", false
+	}
+
+	var memberRole sql.NullString
+	err := h.db.QueryRowContext(c.Request.Context(), `
+		SELECT m.role
+		FROM organizations o
+		LEFT JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $2
+		WHERE o.id = $1`, orgID, userID).Scan(&memberRole)
+	if err != nil && err != sql.ErrNoRows {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organization"))
+		return "", "", "", false
+	}
+	switch {
+	case err == nil && siteRole == "admin":
+		role = organization.RoleOwner
+	case err == nil && memberRole.Valid:
+		role = memberRole.String
+	default:
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrganizationNotFound, "Organization not found"))
+		return "", "", "", false
+	}
+
+	if !organization.AtLeast(role, min) {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden,
+			fmt.Sprintf("Organization %s access required", min)))
+		return "", "", "", false
+	}
+	return orgID, userID, role, true
+}
+
+// $1 là người đang đăng nhập, dùng để lấy vai trò của họ trong tổ chức
+const organizationSelect = `
+	SELECT o.id, o.name, o.description, m.role,
+	       (SELECT COUNT(*) FROM organization_members mc WHERE mc.organization_id = o.id),
+	       o.created_at, o.updated_at
+	FROM organizations o
+	LEFT JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $1`
+
+func scanOrganization(row interface{ Scan(...interface{}) error }, o *dto.OrganizationDTO) error {
+	return row.Scan(&o.ID, &o.Name, &o.Description, &o.Role, &o.MemberCount, &o.CreatedAt, &o.UpdatedAt)
+}
+
+func fetchOrganization(ctx context.Context, q querier, id, userID string) (*dto.OrganizationDTO, error) {
+	var o dto.OrganizationDTO
+	if err := scanOrganization(q.QueryRowContext(ctx, organizationSelect+" WHERE o.id = $2", userID, id), &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GET /api/organizations
+// Tổ chức mà người đang đăng nhập là thành viên; admin thấy mọi tổ chức
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	userID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+	ctx := c.Request.Context()
+
+	where := " WHERE m.user_id IS NOT NULL"
+	if role == "admin" {
+		where = " WHERE TRUE"
+	}
+
+	var total int64
+	err := h.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organizations o
+		LEFT JOIN organization_members m ON m.organization_id = o.id AND m.user_id = $1`+where, userID).Scan(&total)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count organizations"))
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, organizationSelect+where+`
+		ORDER BY o.name, o.id
+		LIMIT $2 OFFSET $3`, userID, query.Limit, query.GetOffset())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organizations"))
+		return
+	}
+	defer rows.Close()
+
+	organizations := []dto.OrganizationDTO{}
+	for rows.Next() {
+		var o dto.OrganizationDTO
+		if err := scanOrganization(rows, &o); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan organization"))
+			return
+		}
+		organizations = append(organizations, o)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organizations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Organizations retrieved successfully",
+		Data: dto.OrganizationListResponse{
+			Organizations: organizations,
+			Pagination:    dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/organizations/:id
+// Chi tiết tổ chức; mọi thành viên đều xem được
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	orgID, userID, _, ok := h.membership(c, organization.RoleLearner)
+	if !ok {
+		return
+	}
+
+	o, err := fetchOrganization(c.Request.Context(), h.db, orgID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organization"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Organization retrieved successfully",
+		Data:    o,
+	})
+}
+
+// POST /api/organizations
+// Tạo tổ chức; người tạo trở thành owner
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create organization"))
+		return
+	}
+	defer tx.Rollback()
+
+	var orgID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO organizations (name, description, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id`, req.Name, req.Description, userID).Scan(&orgID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create organization"))
+		return
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, added_by)
+		VALUES ($1, $2, 'owner', $2)`, orgID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create organization"))
+		return
+	}
+
+	o, err := fetchOrganization(ctx, tx, orgID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organization"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create organization"))
+		return
+	}
+
+	middleware.Log(c).WithField("organization_id", orgID).Info("Organization created")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Organization created successfully",
+		Data:    o,
+	})
+}
+
+// PUT /api/organizations/:id
+// Cập nhật tên, mô tả tổ chức (owner)
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	orgID, userID, _, ok := h.membership(c, organization.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	if req.Name == nil && req.Description == nil {
+		apierror.Abort(c, apierror.BadRequest(apierror.CodeNoFieldsToUpdate, "No fields to update"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	_, err := h.db.ExecContext(ctx, `
+		UPDATE organizations SET
+			name = COALESCE($2, name),
+			description = COALESCE($3, description),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, orgID, req.Name, req.Description)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update organization"))
+		return
+	}
+
+	o, err := fetchOrganization(ctx, h.db, orgID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch organization"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Organization updated successfully",
+		Data:    o,
+	})
+}
+
+const organizationMemberSelect = `
+	SELECT m.user_id, u.email, u.username, u.first_name, u.last_name, m.role, m.created_at
+	FROM organization_members m
+	JOIN users u ON u.id = m.user_id`
+
+func scanOrganizationMember(row interface{ Scan(...interface{}) error }, m *dto.OrganizationMemberDTO) error {
+	return row.Scan(&m.UserID, &m.Email, &m.Username, &m.FirstName, &m.LastName, &m.Role, &m.CreatedAt)
+}
+
+func fetchOrganizationMember(ctx context.Context, q querier, orgID, userID string) (*dto.OrganizationMemberDTO, error) {
+	var m dto.OrganizationMemberDTO
+	err := scanOrganizationMember(q.QueryRowContext(ctx, organizationMemberSelect+`
+		WHERE m.organization_id = $1 AND m.user_id = $2`, orgID, userID), &m)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GET /api/organizations/:id/members
+// Thành viên của tổ chức (owner, manager)
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	var query dto.OrganizationMemberListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+	ctx := c.Request.Context()
+
+	where := " WHERE m.organization_id = $1"
+	args := []interface{}{orgID}
+	if query.Role != "" {
+		args = append(args, query.Role)
+		where += " AND m.role = $" + strconv.Itoa(len(args))
+	}
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM organization_members m"+where, args...).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count members"))
+		return
+	}
+
+	args = append(args, query.Limit, query.GetOffset())
+	rows, err := h.db.QueryContext(ctx, organizationMemberSelect+where+fmt.Sprintf(`
+		ORDER BY u.first_name, u.last_name, u.id
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch members"))
+		return
+	}
+	defer rows.Close()
+
+	members := []dto.OrganizationMemberDTO{}
+	for rows.Next() {
+		var m dto.OrganizationMemberDTO
+		if err := scanOrganizationMember(rows, &m); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan member"))
+			return
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch members"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Members retrieved successfully",
+		Data: dto.OrganizationMemberListResponse{
+			Members:    members,
+			Pagination: dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// lockMember khóa tổ chức (để kiểm tra owner cuối cùng không bị tranh chấp)
+// và đọc vai trò của thành viên :user_id.
+func lockMember(ctx context.Context, tx *sql.Tx, orgID, memberID string) (role string, owners int, err error) {
+	if _, err = tx.ExecContext(ctx, "SELECT 1 FROM organizations WHERE id = $1 FOR UPDATE", orgID); err != nil {
+		return "", 0, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		SELECT m.role, (SELECT COUNT(*) FROM organization_members o WHERE o.organization_id = $1 AND o.role = 'owner')
+		FROM organization_members m
+		WHERE m.organization_id = $1 AND m.user_id = $2`, orgID, memberID).Scan(&role, &owners)
+	return role, owners, err
+}
+
+func memberID(c *gin.Context) (string, bool) {
+	id := c.Param("user_id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid user ID format"))
+		return "", false
+	}
+	return id, true
+}
+
+// PUT /api/organizations/:id/members/:user_id
+// Đổi vai trò thành viên (owner). Tổ chức luôn phải còn ít nhất một owner
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleOwner)
+	if !ok {
+		return
+	}
+	target, ok := memberID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update member"))
+		return
+	}
+	defer tx.Rollback()
+
+	current, owners, err := lockMember(ctx, tx, orgID, target)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrganizationMemberNotFound, "Member not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch member"))
+		return
+	}
+	if current == organization.RoleOwner && req.Role != organization.RoleOwner && owners <= 1 {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeLastOwner,
+			"An organization must keep at least one owner"))
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE organization_members SET role = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE organization_id = $1 AND user_id = $2`, orgID, target, req.Role)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update member"))
+		return
+	}
+
+	member, err := fetchOrganizationMember(ctx, tx, orgID, target)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch member"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update member"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Member updated successfully",
+		Data:    member,
+	})
+}
+
+// DELETE /api/organizations/:id/members/:user_id
+// Xóa thành viên cùng các khóa học được giao; thành viên vẫn giữ ghi danh và
+// suất đã dùng không được hoàn lại. Manager chỉ xóa được learner; mọi thành
+// viên đều tự rời tổ chức được, trừ owner cuối cùng
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	orgID, userID, role, ok := h.membership(c, organization.RoleLearner)
+	if !ok {
+		return
+	}
+	target, ok := memberID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to remove member"))
+		return
+	}
+	defer tx.Rollback()
+
+	current, owners, err := lockMember(ctx, tx, orgID, target)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeOrganizationMemberNotFound, "Member not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch member"))
+		return
+	}
+	if target != userID && !organization.CanManage(role, current) {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden,
+			"Only owners can remove owners or managers"))
+		return
+	}
+	if current == organization.RoleOwner && owners <= 1 {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeLastOwner,
+			"An organization must keep at least one owner"))
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, orgID, target)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to remove member"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to remove member"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Member removed successfully",
+	})
+}
@@ -0,0 +1,496 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/metrics"
+	"internal/organization"
+)
+
+// Tiến độ đọc từ ghi danh của thành viên, kể cả ghi danh có trước khi được giao
+const assignmentFrom = `
+	FROM organization_course_assignments a
+	LEFT JOIN enrollments e ON e.user_id = a.user_id AND e.course_id = a.course_id`
+
+const assignmentSelect = `
+	SELECT a.id, a.organization_id, a.user_id, u.email, a.course_id, c.title, to_char(a.due_date, 'YYYY-MM-DD'),` +
+	organization.AssignmentStatus + `, ` + organization.Overdue + `,
+	       COALESCE(e.progress_percentage, 0)::float8, e.completed_at, e.id, a.seat_pool_id IS NOT NULL,
+	       a.assigned_by, a.created_at, a.updated_at` + assignmentFrom + `
+	JOIN users u ON u.id = a.user_id
+	JOIN courses c ON c.id = a.course_id`
+
+func scanAssignment(row interface{ Scan(...interface{}) error }, a *dto.CourseAssignmentDTO) error {
+	return row.Scan(&a.ID, &a.OrganizationID, &a.UserID, &a.UserEmail, &a.CourseID, &a.CourseTitle, &a.DueDate,
+		&a.Status, &a.Overdue, &a.ProgressPercentage, &a.CompletedAt, &a.EnrollmentID, &a.UsedSeat,
+		&a.AssignedBy, &a.CreatedAt, &a.UpdatedAt)
+}
+
+func queryAssignments(ctx context.Context, q querier, where string, args ...interface{}) ([]dto.CourseAssignmentDTO, error) {
+	rows, err := q.QueryContext(ctx, assignmentSelect+" WHERE "+where+" ORDER BY u.email, c.title", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assignments := []dto.CourseAssignmentDTO{}
+	for rows.Next() {
+		var a dto.CourseAssignmentDTO
+		if err := scanAssignment(rows, &a); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+func assignmentID(c *gin.Context) (string, bool) {
+	id := c.Param("assignment_id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid assignment ID format"))
+		return "", false
+	}
+	return id, true
+}
+
+// GET /api/organizations/:id/assignments
+// Khóa học được giao kèm tiến độ. Owner, manager thấy mọi thành viên của tổ
+// chức; learner chỉ thấy khóa học được giao cho mình
+func (h *OrganizationHandler) GetAssignments(c *gin.Context) {
+	orgID, userID, role, ok := h.membership(c, organization.RoleLearner)
+	if !ok {
+		return
+	}
+
+	var query dto.CourseAssignmentListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+	if !organization.AtLeast(role, organization.RoleManager) {
+		query.UserID = userID
+	}
+	ctx := c.Request.Context()
+
+	where := "a.organization_id = $1"
+	args := []interface{}{orgID}
+	if query.UserID != "" {
+		args = append(args, query.UserID)
+		where += " AND a.user_id = $" + strconv.Itoa(len(args))
+	}
+	if query.CourseID != "" {
+		args = append(args, query.CourseID)
+		where += " AND a.course_id = $" + strconv.Itoa(len(args))
+	}
+	switch query.Status {
+	case "":
+	case "overdue":
+		where += " AND " + organization.Overdue
+	default:
+		args = append(args, query.Status)
+		where += " AND " + organization.AssignmentStatus + " = $" + strconv.Itoa(len(args))
+	}
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*)"+assignmentFrom+" WHERE "+where, args...).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count assignments"))
+		return
+	}
+
+	args = append(args, query.Limit, query.GetOffset())
+	rows, err := h.db.QueryContext(ctx, assignmentSelect+" WHERE "+where+fmt.Sprintf(`
+		ORDER BY a.due_date NULLS LAST, u.email, c.title
+		LIMIT $%d OFFSET $%d`, len(args)-1, len(args)), args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch assignments"))
+		return
+	}
+	defer rows.Close()
+
+	assignments := []dto.CourseAssignmentDTO{}
+	for rows.Next() {
+		var a dto.CourseAssignmentDTO
+		if err := scanAssignment(rows, &a); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan assignment"))
+			return
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch assignments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Assignments retrieved successfully",
+		Data: dto.CourseAssignmentListResponse{
+			Assignments: assignments,
+			Pagination:  dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// POST /api/organizations/:id/assignments
+// Giao một khóa học cho các thành viên (owner, manager). Thành viên chưa ghi
+// danh dùng một suất đã thanh toán và được ghi danh; thiếu suất thì không
+// giao cho ai
+func (h *OrganizationHandler) CreateAssignments(c *gin.Context) {
+	orgID, userID, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateCourseAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	userIDs := collectIDs(len(req.UserIDs), func(i int) string { return req.UserIDs[i] })
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to assign course"))
+		return
+	}
+	defer tx.Rollback()
+
+	var courseTitle string
+	err = tx.QueryRowContext(ctx, "SELECT title FROM courses WHERE id = $1", req.CourseID).Scan(&courseTitle)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch course"))
+		return
+	}
+
+	// Khóa thành viên để họ không bị xóa khỏi tổ chức giữa chừng
+	rows, err := tx.QueryContext(ctx, `
+		SELECT user_id FROM organization_members
+		WHERE organization_id = $1 AND user_id = ANY($2)
+		FOR SHARE`, orgID, userIDs)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch members"))
+		return
+	}
+	members := make(map[string]bool, len(userIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan member"))
+			return
+		}
+		members[id] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch members"))
+		return
+	}
+	for _, id := range userIDs {
+		if !members[id] {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeOrganizationMemberNotFound,
+				"User "+id+" is not a member of this organization"))
+			return
+		}
+	}
+
+	message := fmt.Sprintf("Bạn được giao khóa học \"%s\"", courseTitle)
+	if req.DueDate != nil {
+		message += fmt.Sprintf(", hạn hoàn thành %s", *req.DueDate)
+	}
+
+	ids := make([]string, 0, len(userIDs))
+	seats := 0
+	for _, member := range userIDs {
+		var enrollmentID string
+		var poolID sql.NullString
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM enrollments WHERE user_id = $1 AND course_id = $2`, member, req.CourseID).Scan(&enrollmentID)
+		if err == sql.ErrNoRows {
+			poolID.String, err = organization.TakeSeat(ctx, tx, orgID, req.CourseID)
+			if err == organization.ErrNoSeats {
+				apierror.Abort(c, apierror.Unprocessable(apierror.CodeNoSeatsAvailable,
+					fmt.Sprintf("Not enough paid seats for course %q", courseTitle)))
+				return
+			}
+			if err != nil {
+				apierror.Abort(c, apierror.Internal(err, "Failed to take seat"))
+				return
+			}
+			poolID.Valid = true
+			seats++
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO enrollments (user_id, course_id) VALUES ($1, $2) RETURNING id`,
+				member, req.CourseID).Scan(&enrollmentID)
+			if err != nil {
+				apierror.Abort(c, apierror.FromDB(err, "Failed to enroll member"))
+				return
+			}
+		} else if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to fetch enrollment"))
+			return
+		}
+
+		var id string
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO organization_course_assignments
+				(organization_id, user_id, course_id, seat_pool_id, enrollment_id, due_date, assigned_by)
+			VALUES ($1, $2, $3, $4, $5, $6::date, $7)
+			RETURNING id`,
+			orgID, member, req.CourseID, poolID, enrollmentID, req.DueDate, userID).Scan(&id)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to assign course"))
+			return
+		}
+		ids = append(ids, id)
+
+		if member != userID {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO notifications (user_id, title, message, type, related_id)
+				VALUES ($1, $2, $3, 'course_assigned', $4)`,
+				member, "Khóa học mới được giao", message, req.CourseID)
+			if err != nil {
+				apierror.Abort(c, apierror.Internal(err, "Failed to notify member"))
+				return
+			}
+		}
+	}
+
+	assignments, err := queryAssignments(ctx, tx, "a.id = ANY($1)", ids)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch assignments"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to assign course"))
+		return
+	}
+	// Mỗi suất dùng là một ghi danh mới
+	metrics.EnrollmentsCreated.Add(float64(seats), "organization")
+
+	middleware.Log(c).WithField("organization_id", orgID).WithField("course_id", req.CourseID).
+		WithField("members", len(ids)).WithField("seats", seats).Info("Course assigned")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Course assigned successfully",
+		Data:    assignments,
+	})
+}
+
+// PUT /api/organizations/:id/assignments/:assignment_id
+// Đổi hoặc gỡ hạn hoàn thành (owner, manager)
+func (h *OrganizationHandler) UpdateAssignment(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+	id, ok := assignmentID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCourseAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.db.ExecContext(ctx, `
+		UPDATE organization_course_assignments SET due_date = $3::date, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND organization_id = $2`, id, orgID, req.DueDate)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update assignment"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseAssignmentNotFound, "Assignment not found"))
+		return
+	}
+
+	var assignment dto.CourseAssignmentDTO
+	if err := scanAssignment(h.db.QueryRowContext(ctx, assignmentSelect+" WHERE a.id = $1", id), &assignment); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch assignment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Assignment updated successfully",
+		Data:    assignment,
+	})
+}
+
+// DELETE /api/organizations/:id/assignments/:assignment_id
+// Gỡ khóa học được giao (owner, manager); thành viên vẫn giữ ghi danh nên
+// suất đã dùng không được hoàn lại
+func (h *OrganizationHandler) DeleteAssignment(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+	id, ok := assignmentID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.db.ExecContext(c.Request.Context(), `
+		DELETE FROM organization_course_assignments WHERE id = $1 AND organization_id = $2`, id, orgID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to delete assignment"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeCourseAssignmentNotFound, "Assignment not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Assignment deleted successfully",
+	})
+}
+
+// GET /api/organizations/:id/reports/progress
+// Báo cáo tiến độ học của thành viên trên các khóa học được giao (owner,
+// manager), tổng hợp từ enrollments.progress_percentage
+func (h *OrganizationHandler) GetProgressReport(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	var query dto.OrganizationReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	ctx := c.Request.Context()
+
+	where := "a.organization_id = $1"
+	args := []interface{}{orgID}
+	if query.UserID != "" {
+		args = append(args, query.UserID)
+		where += " AND a.user_id = $" + strconv.Itoa(len(args))
+	}
+	if query.CourseID != "" {
+		args = append(args, query.CourseID)
+		where += " AND a.course_id = $" + strconv.Itoa(len(args))
+	}
+	progress := `
+		WITH p AS (
+			SELECT a.user_id, a.course_id, ` + organization.AssignmentStatus + ` AS status,
+			       ` + organization.Overdue + ` AS overdue, COALESCE(e.progress_percentage, 0) AS progress` +
+		assignmentFrom + `
+			WHERE ` + where + `
+		)`
+
+	report := dto.OrganizationProgressReport{
+		Members: []dto.MemberProgressDTO{},
+		Courses: []dto.CourseProgressDTO{},
+	}
+	s := &report.Summary
+	err := h.db.QueryRowContext(ctx, progress+`
+		SELECT COUNT(DISTINCT user_id), COUNT(*),
+		       COUNT(*) FILTER (WHERE status = 'not_started'),
+		       COUNT(*) FILTER (WHERE status = 'in_progress'),
+		       COUNT(*) FILTER (WHERE status = 'completed'),
+		       COUNT(*) FILTER (WHERE overdue),
+		       COALESCE(ROUND(AVG(progress), 2), 0)::float8
+		FROM p`, args...,
+	).Scan(&s.Members, &s.Assignments, &s.NotStarted, &s.InProgress, &s.Completed, &s.Overdue, &s.AverageProgress)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to build progress summary"))
+		return
+	}
+
+	// Không lọc theo khóa học thì liệt kê cả thành viên chưa được giao khóa nào
+	join := "LEFT JOIN"
+	if query.CourseID != "" {
+		join = "JOIN"
+	}
+	memberWhere := "m.organization_id = $1"
+	if query.UserID != "" {
+		memberWhere += " AND m.user_id = $2"
+	}
+	rows, err := h.db.QueryContext(ctx, progress+`
+		SELECT m.user_id, u.email, u.first_name, u.last_name, m.role,
+		       COUNT(p.user_id),
+		       COUNT(*) FILTER (WHERE p.status = 'completed'),
+		       COUNT(*) FILTER (WHERE p.overdue),
+		       COALESCE(ROUND(AVG(p.progress), 2), 0)::float8
+		FROM organization_members m
+		JOIN users u ON u.id = m.user_id
+		`+join+` p ON p.user_id = m.user_id
+		WHERE `+memberWhere+`
+		GROUP BY m.user_id, u.email, u.first_name, u.last_name, m.role
+		ORDER BY u.first_name, u.last_name, m.user_id`, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to build member progress"))
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m dto.MemberProgressDTO
+		if err := rows.Scan(&m.UserID, &m.Email, &m.FirstName, &m.LastName, &m.Role,
+			&m.Assignments, &m.Completed, &m.Overdue, &m.AverageProgress); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan member progress"))
+			return
+		}
+		report.Members = append(report.Members, m)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to build member progress"))
+		return
+	}
+
+	courseRows, err := h.db.QueryContext(ctx, progress+`
+		SELECT p.course_id, c.title, COUNT(*),
+		       COUNT(*) FILTER (WHERE p.status = 'completed'),
+		       COUNT(*) FILTER (WHERE p.overdue),
+		       COALESCE(ROUND(AVG(p.progress), 2), 0)::float8
+		FROM p
+		JOIN courses c ON c.id = p.course_id
+		GROUP BY p.course_id, c.title
+		ORDER BY c.title, p.course_id`, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to build course progress"))
+		return
+	}
+	defer courseRows.Close()
+	for courseRows.Next() {
+		var cp dto.CourseProgressDTO
+		if err := courseRows.Scan(&cp.CourseID, &cp.CourseTitle, &cp.Assignments,
+			&cp.Completed, &cp.Overdue, &cp.AverageProgress); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan course progress"))
+			return
+		}
+		report.Courses = append(report.Courses, cp)
+	}
+	if err := courseRows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to build course progress"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Progress report retrieved successfully",
+		Data:    report,
+	})
+}
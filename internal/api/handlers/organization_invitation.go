@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/organization"
+)
+
+const organizationInvitationSelect = `
+	SELECT i.id, i.organization_id, o.name, i.email, i.role, i.created_at
+	FROM organization_invitations i
+	JOIN organizations o ON o.id = i.organization_id`
+
+func queryOrganizationInvitations(ctx context.Context, q querier, where string, args ...interface{}) ([]dto.OrganizationInvitationDTO, error) {
+	rows, err := q.QueryContext(ctx, organizationInvitationSelect+" WHERE "+where+" ORDER BY i.created_at DESC, i.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []dto.OrganizationInvitationDTO{}
+	for rows.Next() {
+		var i dto.OrganizationInvitationDTO
+		if err := scanOrganizationInvitation(rows, &i); err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, i)
+	}
+	return invitations, rows.Err()
+}
+
+func scanOrganizationInvitation(row interface{ Scan(...interface{}) error }, i *dto.OrganizationInvitationDTO) error {
+	return row.Scan(&i.ID, &i.OrganizationID, &i.OrganizationName, &i.Email, &i.Role, &i.CreatedAt)
+}
+
+func invitationID(c *gin.Context, param string) (string, bool) {
+	id := c.Param(param)
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid invitation ID format"))
+		return "", false
+	}
+	return id, true
+}
+
+// POST /api/organizations/:id/invitations
+// Mời người dùng vào tổ chức theo email (owner, manager). Phản hồi như nhau
+// dù email đã đăng ký hay chưa; người có tài khoản nhận thông báo và thành
+// thành viên khi chấp nhận. Mời lại cùng email thay vai trò của lời mời cũ
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	orgID, userID, role, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	var req dto.InviteOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	if req.Role == "" {
+		req.Role = organization.RoleLearner
+	}
+	if !organization.CanManage(role, req.Role) {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden,
+			"Only owners can invite owners or managers"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to invite member"))
+		return
+	}
+	defer tx.Rollback()
+
+	// Thành viên hiện tại thì owner, manager đã xem được qua danh sách thành
+	// viên nên báo lỗi không làm lộ thêm thông tin
+	var isMember bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM organization_members m
+			JOIN users u ON u.id = m.user_id
+			WHERE m.organization_id = $1 AND u.email = $2
+		)`, orgID, req.Email).Scan(&isMember)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch member"))
+		return
+	}
+	if isMember {
+		apierror.Abort(c, apierror.Conflict(apierror.CodeAlreadyMember, "User is already a member of this organization"))
+		return
+	}
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO organization_invitations (organization_id, email, role, invited_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, email) DO UPDATE
+		SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by, created_at = CURRENT_TIMESTAMP
+		RETURNING id`, orgID, req.Email, req.Role, userID).Scan(&id)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to invite member"))
+		return
+	}
+	var invitation dto.OrganizationInvitationDTO
+	err = scanOrganizationInvitation(tx.QueryRowContext(ctx, organizationInvitationSelect+" WHERE i.id = $1", id), &invitation)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch invitation"))
+		return
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO notifications (user_id, title, message, type, related_id)
+		SELECT id, $2, $3, 'organization_invitation', $4 FROM users WHERE email = $1`,
+		req.Email, "Lời mời tham gia tổ chức",
+		fmt.Sprintf("Bạn được mời tham gia tổ chức \"%s\"", invitation.OrganizationName), id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to notify member"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to invite member"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Invitation sent",
+		Data:    invitation,
+	})
+}
+
+// GET /api/organizations/:id/invitations
+// Lời mời đang chờ của tổ chức (owner, manager)
+func (h *OrganizationHandler) GetInvitations(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	invitations, err := queryOrganizationInvitations(c.Request.Context(), h.db, "i.organization_id = $1", orgID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Invitations retrieved successfully",
+		Data:    dto.OrganizationInvitationListResponse{Invitations: invitations},
+	})
+}
+
+// DELETE /api/organizations/:id/invitations/:invitation_id
+// Thu hồi lời mời. Manager chỉ thu hồi được lời mời learner
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	orgID, _, role, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+	id, ok := invitationID(c, "invitation_id")
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	var invitedRole string
+	err := h.db.QueryRowContext(ctx, `
+		SELECT role FROM organization_invitations WHERE id = $1 AND organization_id = $2`, id, orgID).Scan(&invitedRole)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeInvitationNotFound, "Invitation not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch invitation"))
+		return
+	}
+	if !organization.CanManage(role, invitedRole) {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden,
+			"Only owners can revoke owner or manager invitations"))
+		return
+	}
+
+	if _, err := h.db.ExecContext(ctx, "DELETE FROM organization_invitations WHERE id = $1", id); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to revoke invitation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Invitation revoked successfully",
+	})
+}
+
+// GET /api/organization-invitations
+// Lời mời vào tổ chức gửi tới email của người đang đăng nhập
+func (h *OrganizationHandler) GetMyInvitations(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	invitations, err := queryOrganizationInvitations(c.Request.Context(), h.db,
+		"i.email = (SELECT email FROM users WHERE id = $1)", userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Invitations retrieved successfully",
+		Data:    dto.OrganizationInvitationListResponse{Invitations: invitations},
+	})
+}
+
+// POST /api/organization-invitations/:id/accept
+// Chấp nhận lời mời: người đang đăng nhập thành thành viên với vai trò trong
+// lời mời
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	id, ok := invitationID(c, "id")
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to accept invitation"))
+		return
+	}
+	defer tx.Rollback()
+
+	var orgID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT i.organization_id
+		FROM organization_invitations i
+		JOIN users u ON u.email = i.email
+		WHERE i.id = $1 AND u.id = $2
+		FOR UPDATE OF i`, id, userID).Scan(&orgID)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeInvitationNotFound, "Invitation not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch invitation"))
+		return
+	}
+
+	// Đã là thành viên (thêm bằng lời mời khác) thì giữ vai trò hiện tại
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO organization_members (organization_id, user_id, role, added_by)
+		SELECT organization_id, $2, role, invited_by FROM organization_invitations WHERE id = $1
+		ON CONFLICT (organization_id, user_id) DO NOTHING`, id, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to accept invitation"))
+		return
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM organization_invitations WHERE id = $1", id); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to accept invitation"))
+		return
+	}
+
+	member, err := fetchOrganizationMember(ctx, tx, orgID, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch member"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to accept invitation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Invitation accepted",
+		Data:    member,
+	})
+}
+
+// POST /api/organization-invitations/:id/decline
+// Từ chối lời mời
+func (h *OrganizationHandler) DeclineInvitation(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	id, ok := invitationID(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.db.ExecContext(c.Request.Context(), `
+		DELETE FROM organization_invitations i
+		USING users u
+		WHERE i.id = $1 AND u.id = $2 AND u.email = i.email`, id, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to decline invitation"))
+		return
+	}
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeInvitationNotFound, "Invitation not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Invitation declined",
+	})
+}
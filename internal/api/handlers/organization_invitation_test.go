@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+const (
+	testOrganizationID = "4d3c2b1a-0f9e-4d8c-b7a6-958473625140"
+	testManagerID      = "2c9d8e7f-6a5b-4c3d-9e2f-1a0b9c8d7e6f"
+	testInvitationID   = "8b7a6c5d-4e3f-4a1b-9c8d-7e6f5a4b3c2d"
+)
+
+func expectOrganizationRole(mock sqlmock.Sqlmock, userID, role string) {
+	expectRole(mock, userID, "student")
+	mock.ExpectQuery(`LEFT JOIN organization_members m`).WithArgs(testOrganizationID, userID).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(role))
+}
+
+// Inviting an unregistered email must answer exactly like a registered one so
+// the endpoint cannot be used to find out which emails have accounts.
+func TestInviteMemberDoesNotRevealAccounts(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		notified int64
+	}{
+		{"registered email", 1},
+		{"unregistered email", 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			expectOrganizationRole(mock, testManagerID, "manager")
+			mock.ExpectBegin()
+			mock.ExpectQuery(`SELECT EXISTS`).WithArgs(testOrganizationID, "new@example.com").
+				WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			mock.ExpectQuery(`INSERT INTO organization_invitations`).
+				WithArgs(testOrganizationID, "new@example.com", "learner", testManagerID).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(testInvitationID))
+			mock.ExpectQuery(`FROM organization_invitations i`).WithArgs(testInvitationID).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "organization_id", "name", "email", "role", "created_at"}).
+					AddRow(testInvitationID, testOrganizationID, "Acme", "new@example.com", "learner", time.Now()))
+			mock.ExpectExec(`INSERT INTO notifications`).
+				WithArgs("new@example.com", sqlmock.AnyArg(), sqlmock.AnyArg(), testInvitationID).
+				WillReturnResult(sqlmock.NewResult(0, tt.notified))
+			mock.ExpectCommit()
+
+			status, res := serve(t, http.MethodPost, "/organizations/:id/invitations",
+				"/organizations/"+testOrganizationID+"/invitations", testManagerID,
+				`{"email":"new@example.com"}`, NewOrganizationHandler(db).InviteMember)
+			if status != http.StatusCreated {
+				t.Fatalf("status = %d (%s)", status, res.Error.Code)
+			}
+			var invitation dto.OrganizationInvitationDTO
+			if err := json.Unmarshal(res.Data, &invitation); err != nil {
+				t.Fatal(err)
+			}
+			if invitation.ID != testInvitationID || invitation.Email != "new@example.com" || invitation.Role != "learner" {
+				t.Errorf("invitation = %+v", invitation)
+			}
+		})
+	}
+}
+
+func TestInviteMemberRoleRules(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectOrganizationRole(mock, testManagerID, "manager")
+
+	status, res := serve(t, http.MethodPost, "/organizations/:id/invitations",
+		"/organizations/"+testOrganizationID+"/invitations", testManagerID,
+		`{"email":"boss@example.com","role":"manager"}`, NewOrganizationHandler(db).InviteMember)
+	if status != http.StatusForbidden || res.Error.Code != "FORBIDDEN" {
+		t.Fatalf("manager inviting a manager: status = %d (%s)", status, res.Error.Code)
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	db, mock := newMockDB(t)
+	expectRole(mock, testLearnerID, "student")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM organization_invitations i\s+JOIN users u ON u.email = i.email`).
+		WithArgs(testInvitationID, testLearnerID).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id"}).AddRow(testOrganizationID))
+	mock.ExpectExec(`INSERT INTO organization_members`).WithArgs(testInvitationID, testLearnerID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM organization_invitations`).WithArgs(testInvitationID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM organization_members m`).WithArgs(testOrganizationID, testLearnerID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email", "username", "first_name", "last_name", "role", "created_at"}).
+			AddRow(testLearnerID, "new@example.com", "new", "Lan", "Nguyen", "learner", time.Now()))
+	mock.ExpectCommit()
+
+	status, res := serve(t, http.MethodPost, "/organization-invitations/:id/accept",
+		"/organization-invitations/"+testInvitationID+"/accept", testLearnerID, "",
+		NewOrganizationHandler(db).AcceptInvitation)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%s)", status, res.Error.Code)
+	}
+	var member dto.OrganizationMemberDTO
+	if err := json.Unmarshal(res.Data, &member); err != nil {
+		t.Fatal(err)
+	}
+	if member.UserID != testLearnerID || member.Role != "learner" {
+		t.Errorf("member = %+v", member)
+	}
+}
+
+// An invitation can only be answered by the user it was sent to.
+func TestInvitationOfAnotherUser(t *testing.T) {
+	db, mock := newMockDB(t)
+	h := NewOrganizationHandler(db)
+
+	expectRole(mock, testLearnerID, "student")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM organization_invitations i`).WithArgs(testInvitationID, testLearnerID).
+		WillReturnRows(sqlmock.NewRows([]string{"organization_id"}))
+	mock.ExpectRollback()
+	status, res := serve(t, http.MethodPost, "/organization-invitations/:id/accept",
+		"/organization-invitations/"+testInvitationID+"/accept", testLearnerID, "", h.AcceptInvitation)
+	if status != http.StatusNotFound || res.Error.Code != "INVITATION_NOT_FOUND" {
+		t.Errorf("accept: status = %d (%s)", status, res.Error.Code)
+	}
+
+	expectRole(mock, testLearnerID, "student")
+	mock.ExpectExec(`DELETE FROM organization_invitations i\s+USING users u`).WithArgs(testInvitationID, testLearnerID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	status, res = serve(t, http.MethodPost, "/organization-invitations/:id/decline",
+		"/organization-invitations/"+testInvitationID+"/decline", testLearnerID, "", h.DeclineInvitation)
+	if status != http.StatusNotFound || res.Error.Code != "INVITATION_NOT_FOUND" {
+		t.Errorf("decline: status = %d (%s)", status, res.Error.Code)
+	}
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/money"
+	"internal/organization"
+	"internal/pricing"
+)
+
+// maxSeatOrderSeats giới hạn tổng số suất của một đơn mua suất.
+const maxSeatOrderSeats = 1000
+
+const seatPoolSelect = `
+	SELECT p.id, p.order_id, p.course_id, c.title,` + organization.SeatPoolStatus + `,
+	       p.seats, p.seats_used,
+	       CASE WHEN o.payment_status = 'completed' THEN p.seats - p.seats_used ELSE 0 END,
+	       p.created_at
+	FROM organization_seat_pools p
+	JOIN orders o ON o.id = p.order_id
+	JOIN courses c ON c.id = p.course_id`
+
+func querySeatPools(ctx context.Context, q querier, where string, args ...interface{}) ([]dto.SeatPoolDTO, error) {
+	rows, err := q.QueryContext(ctx, seatPoolSelect+" WHERE "+where+" ORDER BY c.title, p.created_at, p.id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pools := []dto.SeatPoolDTO{}
+	for rows.Next() {
+		var p dto.SeatPoolDTO
+		if err := rows.Scan(&p.ID, &p.OrderID, &p.CourseID, &p.CourseTitle, &p.Status,
+			&p.Seats, &p.SeatsUsed, &p.SeatsAvailable, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		pools = append(pools, p)
+	}
+	return pools, rows.Err()
+}
+
+// GET /api/organizations/:id/seats
+// Suất học của tổ chức theo từng đơn và khóa học (owner, manager)
+func (h *OrganizationHandler) GetSeatPools(c *gin.Context) {
+	orgID, _, _, ok := h.membership(c, organization.RoleManager)
+	if !ok {
+		return
+	}
+
+	var query dto.SeatPoolListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	where := "p.organization_id = $1"
+	args := []interface{}{orgID}
+	if query.CourseID != "" {
+		where += " AND p.course_id = $2"
+		args = append(args, query.CourseID)
+	}
+	pools, err := querySeatPools(c.Request.Context(), h.db, where, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch seats"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Seats retrieved successfully",
+		Data:    pools,
+	})
+}
+
+// POST /api/organizations/:id/seats
+// Mua suất học cho tổ chức (owner): tạo đơn pending với một order_item mỗi
+// suất; suất dùng được khi đơn đã thanh toán
+func (h *OrganizationHandler) PurchaseSeats(c *gin.Context) {
+	orgID, userID, _, ok := h.membership(c, organization.RoleOwner)
+	if !ok {
+		return
+	}
+
+	var req dto.PurchaseSeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	currency := money.Default
+	if req.Currency != "" {
+		currency = req.Currency
+	}
+
+	// Cùng khóa học xuất hiện nhiều lần thì gộp thành một kho suất
+	seats := 0
+	quantities := make(map[string]int, len(req.Items))
+	for _, item := range req.Items {
+		seats += item.Quantity
+		quantities[item.CourseID] += item.Quantity
+	}
+	if seats > maxSeatOrderSeats {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeValidationFailed,
+			fmt.Sprintf("A seat order can hold at most %d seats", maxSeatOrderSeats)).WithDetails(apierror.FieldError{
+			Field: "items", Rule: "max", Message: fmt.Sprintf("must add up to at most %d seats", maxSeatOrderSeats),
+		}))
+		return
+	}
+	ctx := c.Request.Context()
+
+	ids := collectIDs(len(req.Items), func(i int) string { return req.Items[i].CourseID })
+	var unpublished string
+	err := h.db.QueryRowContext(ctx, `
+		SELECT title FROM courses WHERE id = ANY($1) AND status <> 'published' LIMIT 1`, ids).Scan(&unpublished)
+	if err == nil {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeCourseNotPublished,
+			fmt.Sprintf("Course %q is not published", unpublished)))
+		return
+	}
+	if err != sql.ErrNoRows {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch courses"))
+		return
+	}
+
+	lines, err := pricing.Load(ctx, h.db, ids, currency, time.Now())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to price courses"))
+		return
+	}
+	var total, final int64
+	for _, id := range ids {
+		line, ok := lines[id]
+		if !ok {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeCourseNotFound, "Course "+id+" not found"))
+			return
+		}
+		if line.Currency != currency {
+			apierror.Abort(c, apierror.Unprocessable(apierror.CodeCoursePriceNotFound,
+				fmt.Sprintf("Course %q has no price in %s", line.Title, currency)))
+			return
+		}
+		total += line.List.Amount * int64(quantities[id])
+		final += line.Price.Amount * int64(quantities[id])
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create seat order"))
+		return
+	}
+	defer tx.Rollback()
+
+	var order dto.SeatOrderDTO
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO orders (user_id, total_amount, discount_amount, final_amount, currency,
+		                    payment_status, organization_id)
+		VALUES ($1, from_minor_units($2, $5), from_minor_units($3, $5), from_minor_units($4, $5), $5,
+		        'pending', $6)
+		RETURNING id, payment_status, created_at`,
+		userID, total, total-final, final, currency, orgID,
+	).Scan(&order.ID, &order.PaymentStatus, &order.CreatedAt)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create seat order"))
+		return
+	}
+	order.Currency, order.TotalAmount, order.DiscountAmount, order.FinalAmount = currency, total, total-final, final
+
+	for _, id := range ids {
+		line := lines[id]
+		var discount *int64
+		if line.Price.Amount < line.List.Amount {
+			discount = &line.Price.Amount
+		}
+		_, err = tx.ExecContext(ctx, `
+			WITH items AS (
+				INSERT INTO order_items (order_id, course_id, price, discount_price, final_price)
+				SELECT $1, $2, from_minor_units($3, $6), from_minor_units($4, $6), from_minor_units($5, $6)
+				FROM generate_series(1, $7::int)
+			)
+			INSERT INTO organization_seat_pools (organization_id, order_id, course_id, seats)
+			VALUES ($8, $1, $2, $7)`,
+			order.ID, id, line.List.Amount, discount, line.Price.Amount, currency, quantities[id], orgID)
+		if err != nil {
+			apierror.Abort(c, apierror.FromDB(err, "Failed to create seats"))
+			return
+		}
+	}
+
+	order.SeatPools, err = querySeatPools(ctx, tx, "p.order_id = $1", order.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch seats"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create seat order"))
+		return
+	}
+
+	middleware.Log(c).WithField("organization_id", orgID).WithField("order_id", order.ID).
+		WithField("seats", seats).Info("Seat order created")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Seat order created successfully",
+		Data:    order,
+	})
+}
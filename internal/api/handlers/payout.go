@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/config"
+	"internal/ledger"
+)
+
+// PayoutHandler phục vụ các đợt chi trả cho giảng viên (admin) và sao kê thu
+// nhập của giảng viên đang đăng nhập. Bút toán bán hàng / hoàn tiền do trigger
+// trên orders ghi, phần chi trả do package ledger ghi.
+type PayoutHandler struct {
+	db  *sql.DB
+	cfg config.PayoutsConfig
+}
+
+func NewPayoutHandler(db *sql.DB, cfg config.PayoutsConfig) *PayoutHandler {
+	return &PayoutHandler{db: db, cfg: cfg}
+}
+
+// Số tiền (đơn vị nhỏ nhất) và số khoản chi của đợt, tổng theo tiền tệ (bỏ
+// qua khoản bị hủy / lỗi)
+const payoutBatchSelect = `
+	SELECT b.id, b.status, b.eligible_before, b.created_by, b.created_at, b.updated_at,
+	       (SELECT COUNT(*) FROM payouts p WHERE p.batch_id = b.id),
+	       COALESCE((
+	           SELECT json_agg(json_build_object('currency', t.currency, 'amount', t.amount) ORDER BY t.currency)
+	           FROM (
+	               SELECT p.currency, minor_units(SUM(p.amount), p.currency) AS amount
+	               FROM payouts p
+	               WHERE p.batch_id = b.id AND p.status NOT IN ('failed', 'cancelled')
+	               GROUP BY p.currency
+	           ) t
+	       ), '[]')::text
+	FROM payout_batches b`
+
+func scanPayoutBatch(row interface{ Scan(...interface{}) error }, batch *dto.PayoutBatchDTO) error {
+	var totals string
+	if err := row.Scan(&batch.ID, &batch.Status, &batch.EligibleBefore, &batch.CreatedBy,
+		&batch.CreatedAt, &batch.UpdatedAt, &batch.PayoutCount, &totals); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(totals), &batch.Totals)
+}
+
+// POST /api/admin/payout-batches
+// Gom số dư đã qua thời gian giữ của mọi giảng viên vào một đợt chi trả mới
+func (h *PayoutHandler) CreatePayoutBatch(c *gin.Context) {
+	adminID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	if role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Admin access required"))
+		return
+	}
+
+	run, err := ledger.RunPayouts(c.Request.Context(), h.db, h.cfg.HoldingPeriod, h.cfg.MinimumAmounts, &adminID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create payout batch"))
+		return
+	}
+	if run == nil {
+		c.JSON(http.StatusOK, dto.APIResponse{
+			Success: true,
+			Message: "No instructor balance is due for payout",
+		})
+		return
+	}
+
+	batch, apiErr := h.fetchBatch(c, run.ID)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Payout batch created successfully",
+		Data:    batch,
+	})
+}
+
+// GET /api/admin/payout-batches
+func (h *PayoutHandler) GetPayoutBatches(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+
+	var args []interface{}
+	where := ""
+	if status := c.Query("status"); status != "" {
+		where = " WHERE b.status = $1"
+		args = append(args, status)
+	}
+
+	var total int64
+	if err := h.db.QueryRowContext(c.Request.Context(), "SELECT COUNT(*) FROM payout_batches b"+where, args...).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count payout batches"))
+		return
+	}
+
+	listQuery := payoutBatchSelect + where +
+		" ORDER BY b.created_at DESC LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	args = append(args, query.Limit, query.GetOffset())
+
+	rows, err := h.db.QueryContext(c.Request.Context(), listQuery, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch payout batches"))
+		return
+	}
+	defer rows.Close()
+
+	batches := []dto.PayoutBatchDTO{}
+	for rows.Next() {
+		var batch dto.PayoutBatchDTO
+		if err := scanPayoutBatch(rows, &batch); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan payout batch"))
+			return
+		}
+		batches = append(batches, batch)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Payout batches retrieved successfully",
+		Data: dto.PayoutBatchListResponse{
+			Batches:    batches,
+			Pagination: dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/admin/payout-batches/:id
+func (h *PayoutHandler) GetPayoutBatch(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid payout batch ID format"))
+		return
+	}
+
+	batch, apiErr := h.fetchBatch(c, id)
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Payout batch retrieved successfully",
+		Data:    batch,
+	})
+}
+
+// PUT /api/admin/payout-batches/:id/status
+// Đổi trạng thái đợt và mọi khoản chi chưa kết thúc trong đợt
+func (h *PayoutHandler) UpdatePayoutBatchStatus(c *gin.Context) {
+	h.updateStatus(c, "Invalid payout batch ID format", ledger.SetBatchStatus,
+		apierror.CodePayoutBatchNotFound, "Payout batch not found")
+}
+
+// PUT /api/admin/payouts/:id/status
+// Đổi trạng thái một khoản chi, ví dụ khi chuyển khoản cho một giảng viên bị lỗi
+func (h *PayoutHandler) UpdatePayoutStatus(c *gin.Context) {
+	h.updateStatus(c, "Invalid payout ID format", ledger.SetPayoutStatus,
+		apierror.CodePayoutNotFound, "Payout not found")
+}
+
+func (h *PayoutHandler) updateStatus(c *gin.Context, invalidID string,
+	set func(ctx context.Context, db *sql.DB, id, status string) error,
+	notFoundCode apierror.Code, notFound string) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID(invalidID))
+		return
+	}
+
+	var req dto.UpdatePayoutStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	switch err := set(c.Request.Context(), h.db, id, req.Status); {
+	case errors.Is(err, ledger.ErrNotFound):
+		apierror.Abort(c, apierror.NotFound(notFoundCode, notFound))
+		return
+	case errors.Is(err, ledger.ErrInvalidTransition):
+		apierror.Abort(c, apierror.Conflict(apierror.CodeInvalidPayoutStatus, "Status cannot change from its current value to "+req.Status))
+		return
+	case err != nil:
+		apierror.Abort(c, apierror.Internal(err, "Failed to update payout status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Payout status updated successfully",
+	})
+}
+
+// fetchBatch đọc một đợt chi trả kèm các khoản chi của nó.
+func (h *PayoutHandler) fetchBatch(c *gin.Context, id string) (*dto.PayoutBatchDTO, *apierror.Error) {
+	ctx := c.Request.Context()
+
+	var batch dto.PayoutBatchDTO
+	err := scanPayoutBatch(h.db.QueryRowContext(ctx, payoutBatchSelect+" WHERE b.id = $1", id), &batch)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apierror.NotFound(apierror.CodePayoutBatchNotFound, "Payout batch not found")
+		}
+		return nil, apierror.Internal(err, "Failed to fetch payout batch")
+	}
+
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT p.id, p.batch_id, p.instructor_id, u.first_name || ' ' || u.last_name,
+		       minor_units(p.amount, p.currency), p.currency, p.status, p.paid_at, p.created_at, p.updated_at
+		FROM payouts p
+		JOIN users u ON u.id = p.instructor_id
+		WHERE p.batch_id = $1
+		ORDER BY p.amount DESC, p.id`, id)
+	if err != nil {
+		return nil, apierror.Internal(err, "Failed to fetch payouts")
+	}
+	defer rows.Close()
+
+	batch.Payouts = []dto.PayoutDTO{}
+	for rows.Next() {
+		var p dto.PayoutDTO
+		if err := rows.Scan(&p.ID, &p.BatchID, &p.InstructorID, &p.InstructorName,
+			&p.Amount, &p.Currency, &p.Status, &p.PaidAt, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, apierror.Internal(err, "Failed to scan payout")
+		}
+		batch.Payouts = append(batch.Payouts, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, apierror.Internal(err, "Failed to fetch payouts")
+	}
+	return &batch, nil
+}
+
+// GET /api/instructors/me/statement?from=&to=
+// Sao kê thu nhập theo ngày giờ Việt Nam: số dư đầu kỳ, phát sinh trong kỳ,
+// số dư cuối kỳ và số dư hiện tại (đã / chưa qua thời gian giữ). Số tiền tính
+// theo đơn vị nhỏ nhất của tiền tệ.
+func (h *PayoutHandler) GetStatement(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var query dto.StatementQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	rng, apiErr := parseAnalyticsRange(query.From, query.To, "")
+	if apiErr != nil {
+		apierror.Abort(c, apiErr)
+		return
+	}
+	ctx := c.Request.Context()
+
+	// $1 from, $2 to, $3 instructor; số dương là nền tảng nợ giảng viên
+	const start = `($1::date::timestamp AT TIME ZONE 'Asia/Ho_Chi_Minh')`
+	const end = `(($2::date + 1)::timestamp AT TIME ZONE 'Asia/Ho_Chi_Minh')`
+	const inRange = `e.created_at >= ` + start + ` AND e.created_at < ` + end
+
+	statement := dto.InstructorStatement{
+		From:     rng.From,
+		To:       rng.To,
+		Balances: []dto.StatementBalance{},
+		Entries:  []dto.StatementEntry{},
+	}
+
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT e.currency,
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE e.created_at < `+start+`), 0), e.currency),
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE `+inRange+` AND t.kind = 'sale'), 0), e.currency),
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE `+inRange+` AND t.kind = 'refund'), 0), e.currency),
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE `+inRange+` AND t.payout_id IS NOT NULL), 0), e.currency),
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE e.created_at < `+end+`), 0), e.currency)
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE e.account = 'instructor_payable' AND e.instructor_id = $3
+		GROUP BY e.currency`, rng.From, rng.To, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch statement balances"))
+		return
+	}
+	byCurrency := map[string]*dto.StatementBalance{}
+	for rows.Next() {
+		var b dto.StatementBalance
+		if err := rows.Scan(&b.Currency, &b.OpeningBalance, &b.Earnings, &b.Refunds, &b.Payouts, &b.ClosingBalance); err != nil {
+			rows.Close()
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan statement balance"))
+			return
+		}
+		statement.Balances = append(statement.Balances, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch statement balances"))
+		return
+	}
+	for i := range statement.Balances {
+		byCurrency[statement.Balances[i].Currency] = &statement.Balances[i]
+	}
+
+	current, err := ledger.Balances(ctx, h.db, userID, h.cfg.HoldingPeriod)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch current balance"))
+		return
+	}
+	for _, cur := range current {
+		if b := byCurrency[cur.Currency]; b != nil {
+			b.Available = cur.Available
+			b.OnHold = cur.OnHold()
+		}
+	}
+
+	rows, err = h.db.QueryContext(ctx, `
+		SELECT t.id, t.kind, minor_units(-e.amount, e.currency), e.currency, t.instructor_share::float8,
+		       oi.order_id, oi.course_id, co.title, t.payout_id, e.created_at
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		LEFT JOIN order_items oi ON oi.id = t.order_item_id
+		LEFT JOIN courses co ON co.id = oi.course_id
+		WHERE e.account = 'instructor_payable' AND e.instructor_id = $3 AND `+inRange+`
+		ORDER BY e.created_at, t.id`, rng.From, rng.To, userID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch statement entries"))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e dto.StatementEntry
+		if err := rows.Scan(&e.TransactionID, &e.Kind, &e.Amount, &e.Currency, &e.InstructorShare,
+			&e.OrderID, &e.CourseID, &e.CourseTitle, &e.PayoutID, &e.CreatedAt); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan statement entry"))
+			return
+		}
+		statement.Entries = append(statement.Entries, e)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Statement retrieved successfully",
+		Data:    statement,
+	})
+}
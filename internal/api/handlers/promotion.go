@@ -0,0 +1,419 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+)
+
+// PromotionHandler quản lý khuyến mãi theo thời gian. Giá bán được tính bởi
+// course_effective_price nên thay đổi có hiệu lực ngay với danh sách khóa học
+// và giỏ hàng.
+type PromotionHandler struct {
+	db           *sql.DB
+	couponPolicy string
+}
+
+// NewPromotionHandler - couponPolicy dùng cho khuyến mãi tạo mới không tự đặt
+// coupon_policy
+func NewPromotionHandler(db *sql.DB, couponPolicy string) *PromotionHandler {
+	return &PromotionHandler{db: db, couponPolicy: couponPolicy}
+}
+
+// promotionStatus tính trạng thái của khuyến mãi p tại thời điểm hiện tại.
+const promotionStatus = `
+	CASE WHEN NOT p.is_active THEN 'inactive'
+	     WHEN p.starts_at > now() THEN 'scheduled'
+	     WHEN p.ends_at <= now() THEN 'ended'
+	     ELSE 'running' END`
+
+// discount_value lưu phần trăm hoặc giá theo đơn vị chính như coupons; API
+// dùng basis point và đơn vị nhỏ nhất của currency.
+const promotionColumns = `
+	p.id, p.name, p.description, p.discount_type,
+	CASE WHEN p.discount_type = 'percentage' THEN (p.discount_value * 100)::bigint
+	     ELSE minor_units(p.discount_value, p.currency) END,
+	p.currency, p.priority, p.coupon_policy, p.starts_at, p.ends_at, p.is_active,` + promotionStatus + `,
+	p.created_at, p.updated_at`
+
+func scanPromotion(row interface{ Scan(...interface{}) error }, p *dto.PromotionDTO) error {
+	return row.Scan(&p.ID, &p.Name, &p.Description, &p.DiscountType, &p.DiscountValue,
+		&p.Currency, &p.Priority, &p.CouponPolicy, &p.StartsAt, &p.EndsAt, &p.IsActive, &p.Status,
+		&p.CreatedAt, &p.UpdatedAt)
+}
+
+// loadPromotionTargets gắn phạm vi cho các khuyến mãi.
+func loadPromotionTargets(ctx context.Context, q querier, promotions []dto.PromotionDTO) error {
+	if len(promotions) == 0 {
+		return nil
+	}
+	ids := collectIDs(len(promotions), func(i int) string { return promotions[i].ID })
+	rows, err := q.QueryContext(ctx, `
+		SELECT promotion_id, target_type, target_id
+		FROM promotion_targets
+		WHERE promotion_id = ANY($1)
+		ORDER BY target_type, target_id`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	targets := map[string][]dto.PromotionTargetDTO{}
+	for rows.Next() {
+		var id string
+		var t dto.PromotionTargetDTO
+		if err := rows.Scan(&id, &t.Type, &t.ID); err != nil {
+			return err
+		}
+		targets[id] = append(targets[id], t)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range promotions {
+		promotions[i].Targets = targets[promotions[i].ID]
+		if promotions[i].Targets == nil {
+			promotions[i].Targets = []dto.PromotionTargetDTO{}
+		}
+	}
+	return nil
+}
+
+// replacePromotionTargets thay phạm vi của khuyến mãi. Trả về lỗi 422 nếu
+// một đối tượng không tồn tại (giảng viên phải có vai trò instructor).
+func replacePromotionTargets(ctx context.Context, tx *sql.Tx, id string, targets []dto.PromotionTargetDTO) error {
+	types := make([]string, len(targets))
+	ids := make([]string, len(targets))
+	for i, t := range targets {
+		types[i], ids[i] = t.Type, t.ID
+	}
+
+	var missingType, missingID string
+	err := tx.QueryRowContext(ctx, `
+		SELECT t.type, t.id
+		FROM unnest($1::text[], $2::uuid[]) AS t(type, id)
+		WHERE NOT CASE t.type
+			WHEN 'course' THEN EXISTS (SELECT 1 FROM courses WHERE id = t.id)
+			WHEN 'category' THEN EXISTS (SELECT 1 FROM categories WHERE id = t.id)
+			ELSE EXISTS (SELECT 1 FROM users WHERE id = t.id AND role = 'instructor')
+		END
+		LIMIT 1`, types, ids).Scan(&missingType, &missingID)
+	if err == nil {
+		return apierror.Unprocessable(apierror.CodePromotionTargetNotFound, "Promotion target "+missingType+" "+missingID+" not found")
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM promotion_targets WHERE promotion_id = $1", id); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO promotion_targets (promotion_id, target_type, target_id)
+		SELECT $1, t.type, t.id FROM unnest($2::text[], $3::uuid[]) AS t(type, id)
+		ON CONFLICT DO NOTHING`, id, types, ids)
+	return err
+}
+
+// GET /api/admin/promotions
+func (h *PromotionHandler) GetPromotions(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	var query dto.PromotionListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+
+	var args []interface{}
+	where := ""
+	if query.Status != "" {
+		where = " WHERE" + promotionStatus + " = $1"
+		args = append(args, query.Status)
+	}
+	ctx := c.Request.Context()
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM promotions p"+where, args...).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count promotions"))
+		return
+	}
+
+	listQuery := "SELECT " + promotionColumns + " FROM promotions p" + where +
+		" ORDER BY p.starts_at DESC, p.priority DESC LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	args = append(args, query.Limit, query.GetOffset())
+
+	rows, err := h.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch promotions"))
+		return
+	}
+	defer rows.Close()
+
+	promotions := []dto.PromotionDTO{}
+	for rows.Next() {
+		var p dto.PromotionDTO
+		if err := scanPromotion(rows, &p); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan promotion"))
+			return
+		}
+		promotions = append(promotions, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch promotions"))
+		return
+	}
+	if err := loadPromotionTargets(ctx, h.db, promotions); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to load promotion targets"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Promotions retrieved successfully",
+		Data: dto.PromotionListResponse{
+			Promotions: promotions,
+			Pagination: dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/admin/promotions/:id
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	promotion, err := fetchPromotion(c.Request.Context(), h.db, id)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodePromotionNotFound, "Promotion not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch promotion"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Promotion retrieved successfully",
+		Data:    promotion,
+	})
+}
+
+// POST /api/admin/promotions
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	adminID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	if role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Admin access required"))
+		return
+	}
+
+	var req dto.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	policy := h.couponPolicy
+	if req.CouponPolicy != "" {
+		policy = req.CouponPolicy
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	if req.DiscountType == "percentage" {
+		req.Currency = nil
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create promotion"))
+		return
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO promotions (name, description, discount_type, discount_value, currency, priority,
+		                        coupon_policy, starts_at, ends_at, is_active, created_by)
+		VALUES ($1, $2, $3, CASE WHEN $3 = 'percentage' THEN $4::bigint / 100.0 ELSE from_minor_units($4, $5) END,
+		        $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id`,
+		req.Name, req.Description, req.DiscountType, *req.DiscountValue, req.Currency, req.Priority,
+		policy, req.StartsAt, req.EndsAt, isActive, adminID,
+	).Scan(&id)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create promotion"))
+		return
+	}
+	if err := replacePromotionTargets(ctx, tx, id, req.Targets); err != nil {
+		apierror.Abort(c, apierror.From(err))
+		return
+	}
+	promotion, err := fetchPromotion(ctx, tx, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch promotion"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create promotion"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Promotion created successfully",
+		Data:    promotion,
+	})
+}
+
+// PUT /api/admin/promotions/:id
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update promotion"))
+		return
+	}
+	defer tx.Rollback()
+
+	// Trường không gửi giữ nguyên; đổi sang percentage thì bỏ currency
+	result, err := tx.ExecContext(ctx, `
+		UPDATE promotions SET
+			name = COALESCE($2, name),
+			description = COALESCE($3, description),
+			discount_type = COALESCE($4, discount_type),
+			discount_value = CASE
+				WHEN $5::bigint IS NULL THEN discount_value
+				WHEN COALESCE($4, discount_type) = 'percentage' THEN $5 / 100.0
+				ELSE from_minor_units($5, COALESCE($6, currency)) END,
+			currency = CASE WHEN COALESCE($4, discount_type) = 'percentage' THEN NULL ELSE COALESCE($6, currency) END,
+			priority = COALESCE($7, priority),
+			coupon_policy = COALESCE($8, coupon_policy),
+			starts_at = COALESCE($9, starts_at),
+			ends_at = COALESCE($10, ends_at),
+			is_active = COALESCE($11, is_active),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		id, req.Name, req.Description, req.DiscountType, req.DiscountValue, req.Currency,
+		req.Priority, req.CouponPolicy, req.StartsAt, req.EndsAt, req.IsActive,
+	)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update promotion"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodePromotionNotFound, "Promotion not found"))
+		return
+	}
+	if req.Targets != nil {
+		if err := replacePromotionTargets(ctx, tx, id, req.Targets); err != nil {
+			apierror.Abort(c, apierror.From(err))
+			return
+		}
+	}
+
+	promotion, err := fetchPromotion(ctx, tx, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch promotion"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update promotion"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Promotion updated successfully",
+		Data:    promotion,
+	})
+}
+
+// DELETE /api/admin/promotions/:id
+func (h *PromotionHandler) DeletePromotion(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	result, err := h.db.ExecContext(c.Request.Context(), "DELETE FROM promotions WHERE id = $1", id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to delete promotion"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodePromotionNotFound, "Promotion not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Promotion deleted successfully",
+	})
+}
+
+// querier là *sql.DB hoặc *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// fetchPromotion đọc một khuyến mãi kèm phạm vi.
+func fetchPromotion(ctx context.Context, q querier, id string) (*dto.PromotionDTO, error) {
+	var p dto.PromotionDTO
+	row := q.QueryRowContext(ctx, "SELECT "+promotionColumns+" FROM promotions p WHERE p.id = $1", id)
+	if err := scanPromotion(row, &p); err != nil {
+		return nil, err
+	}
+	promotions := []dto.PromotionDTO{p}
+	if err := loadPromotionTargets(ctx, q, promotions); err != nil {
+		return nil, err
+	}
+	return &promotions[0], nil
+}
+
+func promotionID(c *gin.Context) (string, bool) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid promotion ID format"))
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+)
+
+// RevenueShareRuleHandler quản lý các quy tắc chia doanh thu giữa nền tảng và
+// giảng viên. Quy tắc được trigger trên orders đọc khi ghi sổ cái, nên thay
+// đổi chỉ áp dụng cho các đơn hoàn tất sau đó.
+type RevenueShareRuleHandler struct {
+	db *sql.DB
+}
+
+func NewRevenueShareRuleHandler(db *sql.DB) *RevenueShareRuleHandler {
+	return &RevenueShareRuleHandler{db: db}
+}
+
+const revenueShareRuleColumns = `
+	id, instructor_id, coupon_source, instructor_share::float8, created_at, updated_at`
+
+func scanRevenueShareRule(row interface{ Scan(...interface{}) error }, rule *dto.RevenueShareRuleDTO) error {
+	return row.Scan(&rule.ID, &rule.InstructorID, &rule.CouponSource, &rule.InstructorShare,
+		&rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// GET /api/admin/revenue-share-rules
+func (h *RevenueShareRuleHandler) GetRevenueShareRules(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	// Thứ tự ưu tiên giống revenue_share_for: cụ thể nhất trước
+	rows, err := h.db.QueryContext(c.Request.Context(), `
+		SELECT `+revenueShareRuleColumns+`
+		FROM revenue_share_rules
+		ORDER BY instructor_id IS NULL, coupon_source IS NULL, created_at`)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue share rules"))
+		return
+	}
+	defer rows.Close()
+
+	rules := []dto.RevenueShareRuleDTO{}
+	for rows.Next() {
+		var rule dto.RevenueShareRuleDTO
+		if err := scanRevenueShareRule(rows, &rule); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan revenue share rule"))
+			return
+		}
+		rules = append(rules, rule)
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Revenue share rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+// POST /api/admin/revenue-share-rules
+func (h *RevenueShareRuleHandler) CreateRevenueShareRule(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	var req dto.CreateRevenueShareRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	var rule dto.RevenueShareRuleDTO
+	err := scanRevenueShareRule(h.db.QueryRowContext(c.Request.Context(), `
+		INSERT INTO revenue_share_rules (instructor_id, coupon_source, instructor_share)
+		VALUES ($1, $2, $3)
+		RETURNING `+revenueShareRuleColumns,
+		req.InstructorID, req.CouponSource, *req.InstructorShare,
+	), &rule)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create revenue share rule"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Revenue share rule created successfully",
+		Data:    rule,
+	})
+}
+
+// PUT /api/admin/revenue-share-rules/:id
+func (h *RevenueShareRuleHandler) UpdateRevenueShareRule(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid revenue share rule ID format"))
+		return
+	}
+
+	var req dto.UpdateRevenueShareRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	var rule dto.RevenueShareRuleDTO
+	err := scanRevenueShareRule(h.db.QueryRowContext(c.Request.Context(), `
+		UPDATE revenue_share_rules
+		SET instructor_share = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+revenueShareRuleColumns,
+		id, *req.InstructorShare,
+	), &rule)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeRevenueShareRuleNotFound, "Revenue share rule not found"))
+			return
+		}
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update revenue share rule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Revenue share rule updated successfully",
+		Data:    rule,
+	})
+}
+
+// DELETE /api/admin/revenue-share-rules/:id
+func (h *RevenueShareRuleHandler) DeleteRevenueShareRule(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid revenue share rule ID format"))
+		return
+	}
+
+	// Quy tắc mặc định (không giới hạn giảng viên và nguồn coupon) phải luôn tồn tại
+	var isDefault bool
+	err := h.db.QueryRowContext(c.Request.Context(), `
+		SELECT instructor_id IS NULL AND coupon_source IS NULL FROM revenue_share_rules WHERE id = $1`, id,
+	).Scan(&isDefault)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			apierror.Abort(c, apierror.NotFound(apierror.CodeRevenueShareRuleNotFound, "Revenue share rule not found"))
+			return
+		}
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch revenue share rule"))
+		return
+	}
+	if isDefault {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodeDefaultRevenueShareRule, "The default revenue share rule cannot be deleted"))
+		return
+	}
+
+	if _, err := h.db.ExecContext(c.Request.Context(), "DELETE FROM revenue_share_rules WHERE id = $1", id); err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to delete revenue share rule"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Revenue share rule deleted successfully",
+	})
+}
@@ -0,0 +1,864 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"internal/api/apierror"
+	"internal/api/dto"
+	"internal/api/middleware"
+	"internal/money"
+	"internal/subscription"
+)
+
+// SubscriptionHandler quản lý gói thuê bao và thuê bao của user. Gia hạn, hết
+// hạn và thu hồi quyền học do internal/subscription chạy định kỳ; quyền học
+// được kiểm tra qua internal/entitlement.
+type SubscriptionHandler struct {
+	db *sql.DB
+}
+
+func NewSubscriptionHandler(db *sql.DB) *SubscriptionHandler {
+	return &SubscriptionHandler{db: db}
+}
+
+const planColumns = `
+	sp.id, sp.name, sp.description, sp.billing_interval, sp.interval_count,
+	minor_units(sp.price, sp.currency), sp.currency, sp.scope, sp.is_active, sp.created_at, sp.updated_at`
+
+func scanPlan(row interface{ Scan(...interface{}) error }, p *dto.SubscriptionPlanDTO) error {
+	return row.Scan(&p.ID, &p.Name, &p.Description, &p.BillingInterval, &p.IntervalCount,
+		&p.Price, &p.Currency, &p.Scope, &p.IsActive, &p.CreatedAt, &p.UpdatedAt)
+}
+
+// loadPlanItems gắn danh mục / khóa học cho các gói.
+func loadPlanItems(ctx context.Context, q querier, plans []dto.SubscriptionPlanDTO) error {
+	if len(plans) == 0 {
+		return nil
+	}
+	ids := collectIDs(len(plans), func(i int) string { return plans[i].ID })
+	rows, err := q.QueryContext(ctx, `
+		SELECT plan_id, item_type, item_id
+		FROM subscription_plan_items
+		WHERE plan_id = ANY($1)
+		ORDER BY item_type, item_id`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	items := map[string][]dto.SubscriptionPlanItemDTO{}
+	for rows.Next() {
+		var id string
+		var item dto.SubscriptionPlanItemDTO
+		if err := rows.Scan(&id, &item.Type, &item.ID); err != nil {
+			return err
+		}
+		items[id] = append(items[id], item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range plans {
+		plans[i].Items = items[plans[i].ID]
+		if plans[i].Items == nil {
+			plans[i].Items = []dto.SubscriptionPlanItemDTO{}
+		}
+	}
+	return nil
+}
+
+// checkPlanItems kiểm tra items khớp với scope: gói all không có items, gói
+// categories / courses cần ít nhất một item và chỉ đúng loại đó.
+func checkPlanItems(scope string, items []dto.SubscriptionPlanItemDTO) error {
+	if scope == subscription.ScopeAll {
+		if len(items) > 0 {
+			return apierror.Unprocessable(apierror.CodeValidationFailed, "Plan items do not match its scope").WithDetails(apierror.FieldError{
+				Field: "items", Rule: "excluded_if", Message: "must be empty when scope is all",
+			})
+		}
+		return nil
+	}
+	want := "category"
+	if scope == subscription.ScopeCourses {
+		want = "course"
+	}
+	if len(items) == 0 {
+		return apierror.Unprocessable(apierror.CodeValidationFailed, "Plan items do not match its scope").WithDetails(apierror.FieldError{
+			Field: "items", Rule: "required_unless", Message: "is required when scope is " + scope,
+		})
+	}
+	for i, item := range items {
+		if item.Type != want {
+			return apierror.Unprocessable(apierror.CodeValidationFailed, "Plan items do not match its scope").WithDetails(apierror.FieldError{
+				Field: fmt.Sprintf("items[%d].type", i), Rule: "eq", Message: "must be " + want + " when scope is " + scope,
+			})
+		}
+	}
+	return nil
+}
+
+// replacePlanItems thay danh sách item của gói. Trả về lỗi 422 nếu một danh
+// mục hoặc khóa học không tồn tại.
+func replacePlanItems(ctx context.Context, tx *sql.Tx, id string, items []dto.SubscriptionPlanItemDTO) error {
+	types := make([]string, len(items))
+	ids := make([]string, len(items))
+	for i, item := range items {
+		types[i], ids[i] = item.Type, item.ID
+	}
+
+	var missingType, missingID string
+	err := tx.QueryRowContext(ctx, `
+		SELECT t.type, t.id
+		FROM unnest($1::text[], $2::uuid[]) AS t(type, id)
+		WHERE NOT CASE t.type
+			WHEN 'course' THEN EXISTS (SELECT 1 FROM courses WHERE id = t.id)
+			ELSE EXISTS (SELECT 1 FROM categories WHERE id = t.id)
+		END
+		LIMIT 1`, types, ids).Scan(&missingType, &missingID)
+	if err == nil {
+		return apierror.Unprocessable(apierror.CodePlanItemNotFound, "Plan item "+missingType+" "+missingID+" not found")
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM subscription_plan_items WHERE plan_id = $1", id); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO subscription_plan_items (plan_id, item_type, item_id)
+		SELECT $1, t.type, t.id FROM unnest($2::text[], $3::uuid[]) AS t(type, id)
+		ON CONFLICT DO NOTHING`, id, types, ids)
+	return err
+}
+
+// fetchPlan đọc một gói kèm items.
+func fetchPlan(ctx context.Context, q querier, id string) (*dto.SubscriptionPlanDTO, error) {
+	var p dto.SubscriptionPlanDTO
+	row := q.QueryRowContext(ctx, "SELECT "+planColumns+" FROM subscription_plans sp WHERE sp.id = $1", id)
+	if err := scanPlan(row, &p); err != nil {
+		return nil, err
+	}
+	plans := []dto.SubscriptionPlanDTO{p}
+	if err := loadPlanItems(ctx, q, plans); err != nil {
+		return nil, err
+	}
+	return &plans[0], nil
+}
+
+// isAdmin cho biết request có đến từ admin không, không dừng request khi
+// chưa đăng nhập.
+func isAdmin(c *gin.Context, db *sql.DB) (bool, error) {
+	id := c.GetString(middleware.UserIDKey)
+	if _, err := uuid.Parse(id); err != nil {
+		return false, nil
+	}
+	var role string
+	err := db.QueryRowContext(c.Request.Context(), "SELECT role FROM users WHERE id = $1", id).Scan(&role)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return role == "admin", err
+}
+
+// GET /api/subscription-plans
+// Danh sách gói thuê bao; chỉ admin thấy gói đã ngừng bán
+func (h *SubscriptionHandler) GetPlans(c *gin.Context) {
+	var query dto.SubscriptionPlanListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+
+	ctx := c.Request.Context()
+	admin, err := isAdmin(c, h.db)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch current user"))
+		return
+	}
+	if !admin {
+		active := true
+		query.IsActive = &active
+	}
+
+	var args []interface{}
+	where := ""
+	if query.IsActive != nil {
+		where = " WHERE sp.is_active = $1"
+		args = append(args, *query.IsActive)
+	}
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM subscription_plans sp"+where, args...).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count subscription plans"))
+		return
+	}
+
+	listQuery := "SELECT " + planColumns + " FROM subscription_plans sp" + where +
+		" ORDER BY sp.price, sp.created_at LIMIT $" + strconv.Itoa(len(args)+1) + " OFFSET $" + strconv.Itoa(len(args)+2)
+	args = append(args, query.Limit, query.GetOffset())
+
+	rows, err := h.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plans"))
+		return
+	}
+	defer rows.Close()
+
+	plans := []dto.SubscriptionPlanDTO{}
+	for rows.Next() {
+		var p dto.SubscriptionPlanDTO
+		if err := scanPlan(rows, &p); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan subscription plan"))
+			return
+		}
+		plans = append(plans, p)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plans"))
+		return
+	}
+	if err := loadPlanItems(ctx, h.db, plans); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to load subscription plan items"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription plans retrieved successfully",
+		Data: dto.SubscriptionPlanListResponse{
+			Plans:      plans,
+			Pagination: dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/subscription-plans/:id
+func (h *SubscriptionHandler) GetPlan(c *gin.Context) {
+	id, ok := planID(c)
+	if !ok {
+		return
+	}
+	plan, err := fetchPlan(c.Request.Context(), h.db, id)
+	if err != nil && err != sql.ErrNoRows {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plan"))
+		return
+	}
+	if err == nil && !plan.IsActive {
+		admin, err := isAdmin(c, h.db)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to fetch current user"))
+			return
+		}
+		if !admin {
+			plan = nil
+		}
+	}
+	if plan == nil {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionPlanNotFound, "Subscription plan not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription plan retrieved successfully",
+		Data:    plan,
+	})
+}
+
+// POST /api/admin/subscription-plans
+func (h *SubscriptionHandler) CreatePlan(c *gin.Context) {
+	adminID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+	if role != "admin" {
+		apierror.Abort(c, apierror.New(http.StatusForbidden, apierror.CodeForbidden, "Admin access required"))
+		return
+	}
+
+	var req dto.CreateSubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	if err := checkPlanItems(req.Scope, req.Items); err != nil {
+		apierror.Abort(c, apierror.From(err))
+		return
+	}
+	intervalCount := 1
+	if req.IntervalCount != nil {
+		intervalCount = *req.IntervalCount
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = money.Default
+	}
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create subscription plan"))
+		return
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO subscription_plans (name, description, billing_interval, interval_count, price, currency,
+		                                scope, is_active, created_by)
+		VALUES ($1, $2, $3, $4, from_minor_units($5, $6), $6, $7, $8, $9)
+		RETURNING id`,
+		req.Name, req.Description, req.BillingInterval, intervalCount, req.Price, currency,
+		req.Scope, isActive, adminID,
+	).Scan(&id)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create subscription plan"))
+		return
+	}
+	if err := replacePlanItems(ctx, tx, id, req.Items); err != nil {
+		apierror.Abort(c, apierror.From(err))
+		return
+	}
+	plan, err := fetchPlan(ctx, tx, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plan"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create subscription plan"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Subscription plan created successfully",
+		Data:    plan,
+	})
+}
+
+// PUT /api/admin/subscription-plans/:id
+// Giá mới áp dụng từ kỳ gia hạn tiếp theo của các thuê bao đang chạy; đổi
+// phạm vi có hiệu lực ngay với quyền học
+func (h *SubscriptionHandler) UpdatePlan(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id, ok := planID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateSubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription plan"))
+		return
+	}
+	defer tx.Rollback()
+
+	var scope string
+	err = tx.QueryRowContext(ctx, "SELECT scope FROM subscription_plans WHERE id = $1 FOR UPDATE", id).Scan(&scope)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionPlanNotFound, "Subscription plan not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plan"))
+		return
+	}
+
+	// Đổi scope thì items cũ không còn khớp, nên phải gửi items mới (trừ scope all)
+	scopeChanged := req.Scope != nil && *req.Scope != scope
+	if req.Scope != nil {
+		scope = *req.Scope
+	}
+	if req.Items != nil || scopeChanged {
+		if err := checkPlanItems(scope, req.Items); err != nil {
+			apierror.Abort(c, apierror.From(err))
+			return
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE subscription_plans SET
+			name = COALESCE($2, name),
+			description = COALESCE($3, description),
+			price = COALESCE(from_minor_units($4, currency), price),
+			scope = $5,
+			is_active = COALESCE($6, is_active),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		id, req.Name, req.Description, req.Price, scope, req.IsActive,
+	)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to update subscription plan"))
+		return
+	}
+	if req.Items != nil || scopeChanged {
+		if err := replacePlanItems(ctx, tx, id, req.Items); err != nil {
+			apierror.Abort(c, apierror.From(err))
+			return
+		}
+	}
+
+	plan, err := fetchPlan(ctx, tx, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plan"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription plan"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription plan updated successfully",
+		Data:    plan,
+	})
+}
+
+// DELETE /api/admin/subscription-plans/:id
+// Chỉ xóa được gói chưa có thuê bao; gói đã bán thì ngừng bán (is_active = false)
+func (h *SubscriptionHandler) DeletePlan(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id, ok := planID(c)
+	if !ok {
+		return
+	}
+	result, err := h.db.ExecContext(c.Request.Context(), "DELETE FROM subscription_plans WHERE id = $1", id)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to delete subscription plan"))
+		return
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionPlanNotFound, "Subscription plan not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription plan deleted successfully",
+	})
+}
+
+// access_until: thuê bao active / past_due còn quyền học đến hết thời gian
+// gia hạn nếu có, ngược lại đến cuối kỳ
+const subscriptionColumns = `
+	s.id, s.user_id, sp.id, sp.name, sp.billing_interval, sp.interval_count, s.status,
+	s.current_period_start, s.current_period_end, s.cancel_at_period_end,
+	CASE WHEN s.status IN ('active', 'past_due') THEN COALESCE(s.grace_until, s.current_period_end) END,
+	s.cancelled_at, s.ended_at,
+	pp.id, pp.period_start, pp.period_end, minor_units(pp.amount, pp.currency), pp.currency, pp.status, pp.created_at,
+	s.created_at, s.updated_at`
+
+const subscriptionFrom = `
+	FROM subscriptions s
+	JOIN subscription_plans sp ON sp.id = s.plan_id
+	LEFT JOIN LATERAL (
+		SELECT p.* FROM subscription_payments p
+		WHERE p.subscription_id = s.id AND p.status = 'pending'
+		ORDER BY p.period_start DESC
+		LIMIT 1
+	) pp ON TRUE`
+
+func scanSubscription(row interface{ Scan(...interface{}) error }, s *dto.SubscriptionDTO) error {
+	var (
+		periodStart, periodEnd, accessUntil, cancelledAt, endedAt sql.NullTime
+		paymentID, paymentCurrency, paymentStatus                 sql.NullString
+		paymentStart, paymentEnd, paymentCreated                  sql.NullTime
+		paymentAmount                                             sql.NullInt64
+	)
+	err := row.Scan(&s.ID, &s.UserID, &s.Plan.ID, &s.Plan.Name, &s.Plan.BillingInterval, &s.Plan.IntervalCount, &s.Status,
+		&periodStart, &periodEnd, &s.CancelAtPeriodEnd, &accessUntil, &cancelledAt, &endedAt,
+		&paymentID, &paymentStart, &paymentEnd, &paymentAmount, &paymentCurrency, &paymentStatus, &paymentCreated,
+		&s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	s.CurrentPeriodStart = nullTime(periodStart)
+	s.CurrentPeriodEnd = nullTime(periodEnd)
+	s.AccessUntil = nullTime(accessUntil)
+	s.CancelledAt = nullTime(cancelledAt)
+	s.EndedAt = nullTime(endedAt)
+	if paymentID.Valid {
+		s.PendingPayment = &dto.SubscriptionPaymentDTO{
+			ID:             paymentID.String,
+			SubscriptionID: s.ID,
+			PeriodStart:    paymentStart.Time,
+			PeriodEnd:      paymentEnd.Time,
+			Amount:         paymentAmount.Int64,
+			Currency:       paymentCurrency.String,
+			Status:         paymentStatus.String,
+			CreatedAt:      paymentCreated.Time,
+		}
+	}
+	return nil
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func fetchSubscription(ctx context.Context, q querier, id string) (*dto.SubscriptionDTO, error) {
+	var s dto.SubscriptionDTO
+	row := q.QueryRowContext(ctx, "SELECT "+subscriptionColumns+subscriptionFrom+" WHERE s.id = $1", id)
+	if err := scanSubscription(row, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GET /api/subscriptions
+// Thuê bao của user đang đăng nhập, mới nhất trước
+func (h *SubscriptionHandler) GetSubscriptions(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var query dto.PaginationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	query.SetDefaults()
+	ctx := c.Request.Context()
+
+	var total int64
+	if err := h.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM subscriptions WHERE user_id = $1", userID).Scan(&total); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to count subscriptions"))
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, "SELECT "+subscriptionColumns+subscriptionFrom+`
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC
+		LIMIT $2 OFFSET $3`, userID, query.Limit, query.GetOffset())
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscriptions"))
+		return
+	}
+	defer rows.Close()
+
+	subscriptions := []dto.SubscriptionDTO{}
+	for rows.Next() {
+		var s dto.SubscriptionDTO
+		if err := scanSubscription(rows, &s); err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to scan subscription"))
+			return
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	if err := rows.Err(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscriptions"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscriptions retrieved successfully",
+		Data: dto.SubscriptionListResponse{
+			Subscriptions: subscriptions,
+			Pagination:    dto.NewPaginationResponse(total, query.Page, query.Limit),
+		},
+	})
+}
+
+// GET /api/subscriptions/:id
+// Chủ thuê bao hoặc admin; người khác nhận 404
+func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	s, ok := h.ownSubscription(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription retrieved successfully",
+		Data:    s,
+	})
+}
+
+// POST /api/subscriptions
+// Đăng ký gói và lập khoản thanh toán kỳ đầu; thuê bao có hiệu lực khi cổng
+// thanh toán xác nhận khoản này. Khoản không được thanh toán sẽ bị hủy sau
+// thời gian gia hạn
+func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
+	userID, _, ok := currentUser(c, h.db)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create subscription"))
+		return
+	}
+	defer tx.Rollback()
+
+	var active bool
+	err = tx.QueryRowContext(ctx, "SELECT is_active FROM subscription_plans WHERE id = $1 FOR SHARE", req.PlanID).Scan(&active)
+	if err == sql.ErrNoRows {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionPlanNotFound, "Subscription plan not found"))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription plan"))
+		return
+	}
+	if !active {
+		apierror.Abort(c, apierror.Unprocessable(apierror.CodePlanInactive, "Subscription plan is no longer offered"))
+		return
+	}
+
+	var id string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO subscriptions (user_id, plan_id) VALUES ($1, $2) RETURNING id
+	`, userID, req.PlanID).Scan(&id)
+	if err != nil {
+		apierror.Abort(c, apierror.FromDB(err, "Failed to create subscription"))
+		return
+	}
+	// Kỳ của khoản đầu được tính lại từ lúc thanh toán
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO subscription_payments (subscription_id, period_start, period_end, amount, currency)
+		SELECT $1, now(), subscription_period_end(now(), sp.billing_interval, sp.interval_count), sp.price, sp.currency
+		FROM subscription_plans sp WHERE sp.id = $2
+	`, id, req.PlanID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create subscription payment"))
+		return
+	}
+
+	s, err := fetchSubscription(ctx, tx, id)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to create subscription"))
+		return
+	}
+
+	middleware.Log(c).WithField("subscription_id", id).Info("Subscription created")
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Message: "Subscription created successfully",
+		Data:    s,
+	})
+}
+
+// POST /api/subscriptions/:id/cancel
+// Hủy ở cuối kỳ: vẫn học được đến hết kỳ đã trả tiền và không gia hạn nữa.
+// Thuê bao chưa thanh toán kỳ đầu bị hủy ngay
+func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	h.changeCancellation(c, true)
+}
+
+// POST /api/subscriptions/:id/resume
+// Bỏ yêu cầu hủy khi kỳ hiện tại chưa kết thúc; kỳ sau được gia hạn như thường
+func (h *SubscriptionHandler) ResumeSubscription(c *gin.Context) {
+	h.changeCancellation(c, false)
+}
+
+func (h *SubscriptionHandler) changeCancellation(c *gin.Context, cancel bool) {
+	current, ok := h.ownSubscription(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription"))
+		return
+	}
+	defer tx.Rollback()
+
+	var status string
+	var periodEnd sql.NullTime
+	err = tx.QueryRowContext(ctx, `
+		SELECT status, current_period_end FROM subscriptions WHERE id = $1 FOR UPDATE
+	`, current.ID).Scan(&status, &periodEnd)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription"))
+		return
+	}
+
+	open := status == subscription.StatusActive || status == subscription.StatusPastDue
+	switch {
+	case cancel && status == subscription.StatusIncomplete:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscriptions SET status = 'cancelled', cancelled_at = now(), ended_at = now(), updated_at = now()
+			WHERE id = $1`, current.ID)
+	case cancel && open:
+		// Bỏ thời gian gia hạn: quyền học kết thúc ở cuối kỳ đã trả tiền
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscriptions
+			SET cancel_at_period_end = TRUE, cancelled_at = COALESCE(cancelled_at, now()), grace_until = NULL, updated_at = now()
+			WHERE id = $1`, current.ID)
+	case !cancel && open && periodEnd.Valid && periodEnd.Time.After(time.Now()):
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscriptions SET cancel_at_period_end = FALSE, cancelled_at = NULL, updated_at = now()
+			WHERE id = $1`, current.ID)
+	default:
+		apierror.Abort(c, apierror.Conflict(apierror.CodeInvalidSubscriptionStatus, "Subscription cannot be changed in status "+status))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription"))
+		return
+	}
+	if cancel {
+		// Khoản gia hạn đang chờ không còn cần thanh toán
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscription_payments SET status = 'void', updated_at = now()
+			WHERE subscription_id = $1 AND status = 'pending'`, current.ID)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal(err, "Failed to void subscription payments"))
+			return
+		}
+	}
+
+	s, err := fetchSubscription(ctx, tx, current.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription"))
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription"))
+		return
+	}
+
+	message := "Subscription resumed successfully"
+	if cancel {
+		message = "Subscription cancelled successfully"
+	}
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    s,
+	})
+}
+
+// PUT /api/admin/subscription-payments/:id/status
+// Cổng thanh toán (qua admin / webhook nội bộ) báo kết quả khoản đang chờ:
+// paid kích hoạt hoặc gia hạn thuê bao, failed với kỳ đầu thì kết thúc thuê bao
+func (h *SubscriptionHandler) UpdatePaymentStatus(c *gin.Context) {
+	if !requireAdmin(c, h.db) {
+		return
+	}
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid subscription payment ID format"))
+		return
+	}
+	var req dto.UpdateSubscriptionPaymentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Abort(c, apierror.Validation(err))
+		return
+	}
+	transactionID := ""
+	if req.TransactionID != nil {
+		transactionID = *req.TransactionID
+	}
+
+	ctx := c.Request.Context()
+	subscriptionID, err := subscription.Settle(ctx, h.db, id, req.Status, transactionID, time.Now())
+	switch {
+	case errors.Is(err, subscription.ErrNotFound):
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionPaymentNotFound, "Subscription payment not found"))
+		return
+	case errors.Is(err, subscription.ErrInvalidTransition):
+		apierror.Abort(c, apierror.Conflict(apierror.CodeInvalidPaymentStatus, "Subscription payment is no longer pending"))
+		return
+	case err != nil:
+		apierror.Abort(c, apierror.Internal(err, "Failed to update subscription payment"))
+		return
+	}
+
+	middleware.Log(c).WithField("payment_id", id).WithField("status", req.Status).Info("Subscription payment settled")
+
+	s, err := fetchSubscription(ctx, h.db, subscriptionID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Message: "Subscription payment updated successfully",
+		Data:    s,
+	})
+}
+
+// ownSubscription đọc thuê bao :id của user đang đăng nhập (admin đọc được
+// mọi thuê bao). Thuê bao của người khác trả về 404.
+func (h *SubscriptionHandler) ownSubscription(c *gin.Context) (*dto.SubscriptionDTO, bool) {
+	userID, role, ok := currentUser(c, h.db)
+	if !ok {
+		return nil, false
+	}
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid subscription ID format"))
+		return nil, false
+	}
+
+	s, err := fetchSubscription(c.Request.Context(), h.db, id)
+	if err != nil && err != sql.ErrNoRows {
+		apierror.Abort(c, apierror.Internal(err, "Failed to fetch subscription"))
+		return nil, false
+	}
+	if err == sql.ErrNoRows || (s.UserID != userID && role != "admin") {
+		apierror.Abort(c, apierror.NotFound(apierror.CodeSubscriptionNotFound, "Subscription not found"))
+		return nil, false
+	}
+	return s, true
+}
+
+func planID(c *gin.Context) (string, bool) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		apierror.Abort(c, apierror.InvalidID("Invalid subscription plan ID format"))
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"internal/api/dto"
+)
+
+const testLearnerID = "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11"
+
+const newUserBody = `{"email":"hoc.vien@example.com","username":"hocvien","password":"mat-khau","first_name":"Hoc","last_name":"Vien"`
+
+func TestCreateUserIsStudent(t *testing.T) {
+	for _, body := range []string{newUserBody + `}`, newUserBody + `,"role":"student"}`} {
+		db, mock := newMockDB(t)
+		mock.ExpectExec(`INSERT INTO users`).
+			WithArgs(sqlmock.AnyArg(), "hoc.vien@example.com", "hocvien", sqlmock.AnyArg(), "Hoc", "Vien", nil, nil, "student").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		now := time.Now()
+		mock.ExpectQuery(`FROM users WHERE id = \$1`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email", "username", "first_name", "last_name", "avatar_url",
+				"bio", "role", "is_verified", "created_at", "updated_at"}).
+				AddRow(testLearnerID, "hoc.vien@example.com", "hocvien", "Hoc", "Vien", nil, nil, "student", false, now, now))
+
+		status, res := serve(t, http.MethodPost, "/users", "/users", "", body, NewUserHandler(db).CreateUser)
+		if status != http.StatusCreated {
+			t.Fatalf("%s: status = %d (%s)", body, status, res.Error.Code)
+		}
+		var user dto.UserResponse
+		if err := json.Unmarshal(res.Data, &user); err != nil {
+			t.Fatal(err)
+		}
+		if user.Role != "student" {
+			t.Errorf("%s: role = %q", body, user.Role)
+		}
+	}
+}
+
+// Registration cannot hand out roles: only an admin may create an admin or
+// instructor account, or change a user's role.
+func TestUserRoleRequiresAdmin(t *testing.T) {
+	tests := []struct {
+		name       string
+		callerRole string // "" là khách
+		method     string
+		pattern    string
+		path       string
+		body       string
+		status     int
+		code       string
+	}{
+		{"anonymous registers as admin", "", http.MethodPost, "/users", "/users",
+			newUserBody + `,"role":"admin"}`, http.StatusUnauthorized, "UNAUTHENTICATED"},
+		{"student creates an instructor", "student", http.MethodPost, "/users", "/users",
+			newUserBody + `,"role":"instructor"}`, http.StatusForbidden, "FORBIDDEN"},
+		{"student promotes themselves", "student", http.MethodPut, "/users/:id", "/users/" + testLearnerID,
+			`{"role":"admin"}`, http.StatusForbidden, "FORBIDDEN"},
+		{"instructor changes a role", "instructor", http.MethodPut, "/users/:id", "/users/" + testLearnerID,
+			`{"role":"instructor"}`, http.StatusForbidden, "FORBIDDEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			h := NewUserHandler(db)
+			handler := h.CreateUser
+			if tt.method == http.MethodPut {
+				handler = h.UpdateUser
+			}
+			userID := ""
+			if tt.callerRole != "" {
+				userID = testLearnerID
+				expectRole(mock, userID, tt.callerRole)
+			}
+
+			status, res := serve(t, tt.method, tt.pattern, tt.path, userID, tt.body, handler)
+			if status != tt.status || res.Error.Code != tt.code {
+				t.Errorf("got %d %s, want %d %s", status, res.Error.Code, tt.status, tt.code)
+			}
+		})
+	}
+}
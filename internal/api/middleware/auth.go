@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"internal/api/apierror"
+	"internal/auth"
+)
+
+// Authenticate sets UserIDKey from a "Bearer" access token in the
+// Authorization header. Requests without the header go through anonymously
+// and are turned away by the handlers that need a user; a header with a
+// missing, invalid or expired token is rejected with 401 so clients notice
+// they have to log in again.
+func Authenticate(tokens *auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_request"`)
+			apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeInvalidToken, "Authorization header must be a Bearer token"))
+			return
+		}
+		claims, err := tokens.Verify(token, time.Now())
+		if err != nil {
+			message := "Invalid access token"
+			if err == auth.ErrExpiredToken {
+				message = "Access token has expired"
+			}
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			apierror.Abort(c, apierror.New(http.StatusUnauthorized, apierror.CodeInvalidToken, message))
+			return
+		}
+
+		c.Set(UserIDKey, claims.Subject)
+		c.Set(loggerKey, Log(c).WithField(UserIDKey, claims.Subject))
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("enduser.id", claims.Subject))
+		c.Next()
+	}
+}
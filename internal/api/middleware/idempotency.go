@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/idempotency"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST safely.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response served from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency answers a retried request that carries an Idempotency-Key
+// with the response stored for the first attempt, scoped to the calling
+// user (see idempotencyScope). Reusing a key with a different payload is
+// rejected with 422, and a retry that arrives while the first attempt is
+// still running gets 409. Requests without the header are not affected.
+// Server errors and panics are not stored so the request can be retried.
+func Idempotency(store *idempotency.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || store == nil {
+			c.Next()
+			return
+		}
+		if !validIdempotencyKey(key) {
+			apierror.Abort(c, apierror.BadRequest(apierror.CodeInvalidIdempotencyKey,
+				"Idempotency-Key must be 1-255 printable ASCII characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(apierror.CodeInvalidRequest, "Failed to read request body").WithCause(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := idempotencyScope(c)
+		hash := idempotency.Hash(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		stored, err := store.Begin(c.Request.Context(), scope, key, hash)
+		switch {
+		case errors.Is(err, idempotency.ErrMismatch):
+			apierror.Abort(c, apierror.Unprocessable(apierror.CodeIdempotencyKeyReused,
+				"Idempotency-Key was already used with a different request"))
+			return
+		case errors.Is(err, idempotency.ErrInProgress):
+			apierror.Abort(c, apierror.Conflict(apierror.CodeIdempotencyKeyInUse,
+				"A request with this Idempotency-Key is still being processed"))
+			return
+		case err != nil:
+			apierror.Abort(c, apierror.Internal(err, "Failed to check idempotency key"))
+			return
+		case stored != nil:
+			c.Header(IdempotentReplayedHeader, "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.Body)
+			c.Abort()
+			return
+		}
+
+		w := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		// Deferred so a panicking handler releases the key instead of
+		// leaving it in progress until it expires; the panic is re-raised
+		// for the recovery middleware.
+		defer func() {
+			p := recover()
+
+			// The outcome must be recorded even if the client has gone away.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			var err error
+			status := w.Status()
+			if p != nil || status >= http.StatusInternalServerError {
+				err = store.Release(ctx, scope, key)
+			} else {
+				err = store.Complete(ctx, scope, key, idempotency.Response{
+					StatusCode:  status,
+					ContentType: w.Header().Get("Content-Type"),
+					Body:        w.body.Bytes(),
+				})
+			}
+			if err != nil {
+				Log(c).WithError(err).WithField("idempotency_key", key).Error("failed to record idempotent response")
+			}
+			if p != nil {
+				panic(p)
+			}
+		}()
+		c.Next()
+	}
+}
+
+// idempotencyScope keeps the keys of different callers apart so a key
+// guessed or reused by someone else never replays another user's response.
+// Authenticated requests are scoped to the user; anonymous ones to the
+// client IP and route, hashed to fit the scope column.
+func idempotencyScope(c *gin.Context) string {
+	if userID := c.GetString(UserIDKey); userID != "" {
+		return "user:" + userID
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "\x00" + c.FullPath()))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// captureWriter keeps a copy of the response body while writing it through.
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"database/sql/driver"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"internal/idempotency"
+)
+
+const (
+	userA = "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11"
+	userB = "0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c"
+	body  = `{"course_id":"5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d"}`
+)
+
+// idempotentRouter serves POST /enrollments behind Idempotency as the user
+// named in the X-Test-User header; the handler answers with that user.
+func idempotentRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			c.Set(UserIDKey, user)
+		}
+	})
+	r.POST("/enrollments", Idempotency(idempotency.NewStore(db, time.Hour)), func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"user_id": c.GetString(UserIDKey)})
+	})
+	return r, mock
+}
+
+func post(r http.Handler, user, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/enrollments", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func expectClaim(mock sqlmock.Sqlmock, scope, key string) {
+	hash := idempotency.Hash(http.MethodPost, "/enrollments", []byte(body))
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs(scope, key, hash, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow(key))
+	mock.ExpectExec(`UPDATE idempotency_keys SET status_code`).
+		WithArgs(scope, key, http.StatusCreated, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+}
+
+func TestIdempotencyKeyIsScopedToUser(t *testing.T) {
+	r, mock := idempotentRouter(t)
+	const key = "3c9d6a1e-enroll"
+
+	// User B sends the key user A already used: B's request must run on its
+	// own instead of replaying A's response.
+	expectClaim(mock, "user:"+userA, key)
+	expectClaim(mock, "user:"+userB, key)
+
+	first := post(r, userA, key)
+	second := post(r, userB, key)
+
+	if second.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Fatal("user B got user A's replayed response")
+	}
+	if !strings.Contains(first.Body.String(), userA) || !strings.Contains(second.Body.String(), userB) {
+		t.Fatalf("responses: A = %s, B = %s", first.Body, second.Body)
+	}
+
+	// A's own retry is still replayed.
+	hash := idempotency.Hash(http.MethodPost, "/enrollments", []byte(body))
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).WithArgs("user:"+userA, key, hash, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}))
+	mock.ExpectQuery(`SELECT request_hash`).WithArgs("user:"+userA, key).
+		WillReturnRows(sqlmock.NewRows([]string{"request_hash", "status_code", "content_type", "response_body"}).
+			AddRow(hash, http.StatusCreated, "application/json; charset=utf-8", first.Body.Bytes()))
+
+	retry := post(r, userA, key)
+	if retry.Header().Get(IdempotentReplayedHeader) != "true" || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry: replayed = %q, body = %s", retry.Header().Get(IdempotentReplayedHeader), retry.Body)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scopeArg records the scope each request was stored under.
+type scopeArg struct{ scopes *[]string }
+
+func (a scopeArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*a.scopes = append(*a.scopes, s)
+	return ok
+}
+
+func TestIdempotencyScopeForAnonymousCallers(t *testing.T) {
+	r, mock := idempotentRouter(t)
+	const key = "3c9d6a1e-enroll"
+
+	var scopes []string
+	for i := 0; i < 3; i++ {
+		mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+			WithArgs(scopeArg{&scopes}, key, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow(key))
+		mock.ExpectExec(`UPDATE idempotency_keys SET status_code`).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	for _, ip := range []string{"203.0.113.7", "198.51.100.2", "203.0.113.7"} {
+		req := httptest.NewRequest(http.MethodPost, "/enrollments", strings.NewReader(body))
+		req.RemoteAddr = ip + ":40000"
+		req.Header.Set(IdempotencyKeyHeader, key)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if len(scopes) != 3 {
+		t.Fatalf("scopes = %q", scopes)
+	}
+	for _, s := range scopes {
+		if s == "" || len(s) > 64 {
+			t.Errorf("scope %q must be non-empty and fit idempotency_keys.scope", s)
+		}
+	}
+	if scopes[0] == scopes[1] {
+		t.Error("anonymous callers from different IPs share a scope")
+	}
+	if scopes[0] != scopes[2] {
+		t.Error("anonymous scope is not stable for the same IP and route")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// A handler that panics must not leave its key in progress: the retry would
+// get 409 until the key expires.
+func TestIdempotencyReleasesKeyOnPanic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard), func(c *gin.Context) { c.Set(UserIDKey, userA) })
+	r.POST("/enrollments", Idempotency(idempotency.NewStore(db, time.Hour)), func(c *gin.Context) {
+		panic("enrollment failed")
+	})
+
+	const key = "3c9d6a1e-enroll"
+	hash := idempotency.Hash(http.MethodPost, "/enrollments", []byte(body))
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs("user:"+userA, key, hash, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow(key))
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE scope = \$1 AND key = \$2`).
+		WithArgs("user:"+userA, key).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	req := httptest.NewRequest(http.MethodPost, "/enrollments", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 from the recovery middleware", w.Code)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
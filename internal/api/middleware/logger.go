@@ -1,55 +1,88 @@
-package middleware
-
-import (
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
-)
-
-// Logger middleware sử dụng logrus
-func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logrus.WithFields(logrus.Fields{
-			"status":      param.StatusCode,
-			"method":      param.Method,
-			"path":        param.Path,
-			"latency":     param.Latency,
-			"client_ip":   param.ClientIP,
-			"user_agent":  param.Request.UserAgent(),
-			"error":       param.ErrorMessage,
-		}).Info("HTTP Request")
-		
-		return ""
-	})
-}
-
-// Structured logger middleware
-func StructuredLogger() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
-
-		// Process request
-		c.Next()
-
-		// Log details
-		latency := time.Since(start)
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		statusCode := c.Writer.Status()
-
-		if raw != "" {
-			path = path + "?" + raw
-		}
-
-		logrus.WithFields(logrus.Fields{
-			"status_code": statusCode,
-			"latency":     latency,
-			"client_ip":   clientIP,
-			"method":      method,
-			"path":        path,
-		}).Info("API Request")
-	}
-}
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// sensitiveParams are query parameters whose values never reach the logs.
+var sensitiveParams = map[string]bool{
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"id_token":      true,
+	"password":      true,
+	"secret":        true,
+	"api_key":       true,
+	"apikey":        true,
+	"key":           true,
+	"signature":     true,
+	"code":          true,
+}
+
+// StructuredLogger logs one line per request through the request-scoped
+// entry set by RequestID, so it must be registered after it.
+func StructuredLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		raw := c.Request.URL.RawQuery
+
+		// Process request
+		c.Next()
+
+		// Log details
+		statusCode := c.Writer.Status()
+		if raw != "" {
+			path = path + "?" + redactQuery(raw)
+		}
+
+		fields := logrus.Fields{
+			"status_code":   statusCode,
+			"latency":       time.Since(start),
+			"client_ip":     c.ClientIP(),
+			"method":        c.Request.Method,
+			"path":          path,
+			"route":         c.FullPath(),
+			"response_size": c.Writer.Size(),
+		}
+		if userID := c.GetString(UserIDKey); userID != "" {
+			fields["user_id"] = userID
+		}
+		if len(c.Errors) > 0 {
+			fields["error"] = c.Errors.String()
+		}
+
+		entry := Log(c).WithFields(fields)
+		switch {
+		case statusCode >= http.StatusInternalServerError:
+			entry.Error("API Request")
+		case statusCode >= http.StatusBadRequest:
+			entry.Warn("API Request")
+		default:
+			entry.Info("API Request")
+		}
+	}
+}
+
+// redactQuery masks the values of sensitive parameters while keeping the
+// original order and encoding of everything else.
+func redactQuery(raw string) string {
+	parts := strings.Split(raw, "&")
+	for i, part := range parts {
+		key, _, hasValue := strings.Cut(part, "=")
+		name := key
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if hasValue && sensitiveParams[strings.ToLower(name)] {
+			parts[i] = key + "=REDACTED"
+		}
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/metrics"
+)
+
+// Metrics records request counts, latency and response size per route
+// template. Requests that match no route are grouped under "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Add(1)
+		defer metrics.HTTPRequestsInFlight.Add(-1)
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.Inc(method, route, status)
+		metrics.HTTPRequestDuration.Observe(time.Since(start).Seconds(), method, route)
+		if size := c.Writer.Size(); size > 0 {
+			metrics.HTTPResponseSize.Observe(float64(size), method, route)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/metrics"
+	"internal/ratelimit"
+)
+
+// RateLimit throttles requests with the token bucket described by p and
+// reports the bucket in the RateLimit-* headers of the IETF draft. Requests
+// over the limit get 429 with Retry-After. A nil limiter disables the check.
+//
+// If the store fails the request is let through: an unavailable limiter
+// must not take the API down with it.
+func RateLimit(l *ratelimit.Limiter, p ratelimit.Policy) gin.HandlerFunc {
+	if l == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	policyHeader := fmt.Sprintf("%d;w=%d;burst=%d;policy=%q", p.Requests, int(p.Period.Seconds()), p.Burst, p.Name)
+
+	return func(c *gin.Context) {
+		res, err := l.Allow(c.Request.Context(), p, rateLimitKey(c, p.KeyBy))
+		if err != nil {
+			Log(c).WithError(err).WithField("policy", p.Name).Warn("rate limit check failed, allowing request")
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("RateLimit-Policy", policyHeader)
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+
+		if !res.Allowed {
+			metrics.RateLimitRejected.Inc(p.Name)
+			h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+			apierror.Abort(c, apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited, "Too many requests, please retry later"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the caller. Anonymous requests under a per-user
+// policy are keyed by IP so they still share a bucket.
+func rateLimitKey(c *gin.Context, keyBy string) string {
+	if keyBy == ratelimit.KeyByUser {
+		if userID := c.GetString(UserIDKey); userID != "" {
+			return "user:" + userID
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
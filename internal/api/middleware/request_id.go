@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RequestIDHeader is accepted from clients and echoed on every response.
+	RequestIDHeader = "X-Request-ID"
+
+	// RequestIDKey and UserIDKey are the gin context keys for the request ID
+	// and the authenticated user's ID.
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+
+	loggerKey = "logger"
+
+	maxRequestIDLength = 128
+)
+
+type loggerContextKey struct{}
+
+// RequestID accepts a well-formed X-Request-ID from the client or generates
+// one, echoes it in the response and attaches a request-scoped logrus entry
+// carrying it (and the trace IDs) to the gin and request contexts.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.New().String()
+		}
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request_id", id))
+
+		entry := logrus.WithField(RequestIDKey, id).WithFields(TraceFields(c))
+		c.Set(loggerKey, entry)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), loggerContextKey{}, entry))
+
+		c.Next()
+	}
+}
+
+// validRequestID rejects IDs that are empty, oversized or could break log
+// lines or response headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if r <= ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Log returns the request-scoped logger, falling back to the standard logger
+// for code running outside RequestID.
+func Log(c *gin.Context) *logrus.Entry {
+	if v, ok := c.Get(loggerKey); ok {
+		if entry, ok := v.(*logrus.Entry); ok {
+			return entry
+		}
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// LogFromContext returns the request-scoped logger stored in ctx, for code
+// that only has the request context (e.g. database helpers).
+func LogFromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerContextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"client ID is echoed", "req-7f3a9c", true},
+		{"missing ID is generated", "", false},
+		{"ID with spaces is replaced", "abc def", false},
+		{"ID with a newline is replaced", "abc\ndef", false},
+		{"oversized ID is replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logged interface{}
+			r := gin.New()
+			r.Use(RequestID())
+			r.GET("/", func(c *gin.Context) {
+				logged = Log(c).Data[RequestIDKey]
+				c.Status(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			if tt.keep && id != tt.header {
+				t.Errorf("response ID = %q, want %q", id, tt.header)
+			}
+			if !tt.keep {
+				if _, err := uuid.Parse(id); err != nil {
+					t.Errorf("response ID = %q, want a generated UUID", id)
+				}
+			}
+			if logged != id {
+				t.Errorf("logger request_id = %v, want %q", logged, id)
+			}
+		})
+	}
+}
+
+// The request log line carries the request ID and user, and never the
+// values of credential query parameters.
+func TestStructuredLogger(t *testing.T) {
+	hook := test.NewGlobal()
+	out := logrus.StandardLogger().Out
+	logrus.SetOutput(io.Discard)
+	t.Cleanup(func() {
+		logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+		logrus.SetOutput(out)
+	})
+
+	r := gin.New()
+	r.Use(RequestID(), StructuredLogger(), func(c *gin.Context) { c.Set(UserIDKey, "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11") })
+	r.GET("/courses/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	req := httptest.NewRequest(http.MethodGet, "/courses/42?page=2&access_token=s3cr3t&Password=hunter2", nil)
+	req.Header.Set(RequestIDHeader, "req-7f3a9c")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("no request logged")
+	}
+	if entry.Level != logrus.WarnLevel {
+		t.Errorf("level = %v, want warning for a 404", entry.Level)
+	}
+	want := logrus.Fields{
+		RequestIDKey: "req-7f3a9c",
+		"route":      "/courses/:id",
+		"path":       "/courses/42?page=2&access_token=REDACTED&Password=REDACTED",
+		"user_id":    "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11",
+	}
+	for k, v := range want {
+		if entry.Data[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry.Data[k], v)
+		}
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"page=2&limit=10", "page=2&limit=10"},
+		{"token=abc&page=2", "token=REDACTED&page=2"},
+		{"API_KEY=abc", "API_KEY=REDACTED"},
+		{"%73ecret=abc", "%73ecret=REDACTED"},
+		{"code", "code"},
+		{"search=a%26b&code=xyz", "search=a%26b&code=REDACTED"},
+	}
+	for _, tt := range tests {
+		if got := redactQuery(tt.in); got != tt.want {
+			t.Errorf("redactQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanID  = "00f067aa0ba902b7"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestTracingContinuesIncomingTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	var fields logrus.Fields
+	r := gin.New()
+	r.Use(Tracing())
+	r.GET("/courses/:id", func(c *gin.Context) {
+		fields = TraceFields(c)
+		c.Status(http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/courses/42", nil)
+	req.Header.Set("traceparent", "00-"+parentTraceID+"-"+parentSpanID+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /courses/:id" {
+		t.Errorf("span name = %q, want the route template", span.Name())
+	}
+	if span.SpanContext().TraceID().String() != parentTraceID || span.Parent().SpanID().String() != parentSpanID {
+		t.Errorf("span is not a child of the incoming traceparent: %v", span.Parent())
+	}
+	attrs := attribute.NewSet(span.Attributes()...)
+	if v, _ := attrs.Value("http.route"); v.AsString() != "/courses/:id" {
+		t.Errorf("http.route = %q", v.AsString())
+	}
+	if v, _ := attrs.Value("http.status_code"); v.AsInt64() != http.StatusInternalServerError {
+		t.Errorf("http.status_code = %d", v.AsInt64())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error for a 500", span.Status())
+	}
+	if fields["trace_id"] != parentTraceID || fields["span_id"] != span.SpanContext().SpanID().String() {
+		t.Errorf("log fields = %v", fields)
+	}
+}
+
+func TestTraceFieldsWithoutTrace(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if fields := TraceFields(c); fields != nil {
+		t.Errorf("TraceFields = %v, want nil outside a trace", fields)
+	}
+}
@@ -1,229 +1,468 @@
-package routes
-
-import (
-	"database/sql"
-
-	"github.com/gin-gonic/gin"
-	"internal/api/handlers"
-	"internal/api/middleware"
-)
-
-func SetupRoutes(db *sql.DB) *gin.Engine {
-	// Create Gin router
-	r := gin.New()
-
-	// Add middleware
-	r.Use(middleware.StructuredLogger())
-	r.Use(gin.Recovery())
-	r.Use(middleware.CORS())
-	r.Use(middleware.JSONMiddleware())
-
-	// Initialize handlers
-	categoryHandler := handlers.NewCategoryHandler(db)
-	userHandler := handlers.NewUserHandler(db)
-	courseHandler := handlers.NewCourseHandler(db)
-	tagHandler := handlers.NewTagHandler(db)
-	instructorProfileHandler := handlers.NewInstructorProfileHandler(db)
-	courseSectionHandler := handlers.NewCourseSectionHandler(db)
-	courseLectureHandler := handlers.NewCourseLectureHandler(db)
-	enrollmentHandler := handlers.NewEnrollmentHandler(db)
-	lectureProgressHandler := handlers.NewLectureProgressHandler(db)
-	courseReviewHandler := handlers.NewCourseReviewHandler(db)
-	wishlistHandler := handlers.NewWishlistHandler(db)
-	couponHandler := handlers.NewCouponHandler(db)
-	courseAnnouncementHandler := handlers.NewCourseAnnouncementHandler(db)
-	courseQAHandler := handlers.NewCourseQAHandler(db)
-	notificationHandler := handlers.NewNotificationHandler(db)
-
-	// API routes
-	api := r.Group("/api/v1")
-	{
-		// Health check
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{
-				"status":  "ok",
-				"message": "API is running",
-			})
-		})
-
-		// Categories routes
-		categories := api.Group("/categories")
-		{
-			categories.GET("", categoryHandler.GetCategories)
-			categories.GET("/:id", categoryHandler.GetCategory)
-			categories.POST("", categoryHandler.CreateCategory)
-			categories.PUT("/:id", categoryHandler.UpdateCategory)
-			categories.DELETE("/:id", categoryHandler.DeleteCategory)
-		}
-
-		// Users routes
-		users := api.Group("/users")
-		{
-			users.GET("", userHandler.GetUsers)
-			users.GET("/:id", userHandler.GetUser)
-			users.POST("", userHandler.CreateUser)
-			users.PUT("/:id", userHandler.UpdateUser)
-			users.DELETE("/:id", userHandler.DeleteUser)
-			
-			// User notification stats
-			users.GET("/:user_id/notification-stats", notificationHandler.GetNotificationStats)
-		}
-
-		// Courses routes
-		courses := api.Group("/courses")
-		{
-			courses.GET("", courseHandler.GetCourses)
-			courses.GET("/:id", courseHandler.GetCourse)
-			courses.POST("", courseHandler.CreateCourse)
-			courses.PUT("/:id", courseHandler.UpdateCourse)
-			courses.DELETE("/:id", courseHandler.DeleteCourse)
-			
-			// Course tags
-			courses.GET("/:course_id/tags", tagHandler.GetCourseTags)
-			
-			// Course review stats
-			courses.GET("/:course_id/review-stats", courseReviewHandler.GetCourseReviewStats)
-		}
-
-		// Tags routes
-		tags := api.Group("/tags")
-		{
-			tags.GET("", tagHandler.GetTags)
-			tags.GET("/:id", tagHandler.GetTag)
-			tags.POST("", tagHandler.CreateTag)
-			tags.PUT("/:id", tagHandler.UpdateTag)
-			tags.DELETE("/:id", tagHandler.DeleteTag)
-		}
-
-		// Instructor Profiles routes
-		instructorProfiles := api.Group("/instructor-profiles")
-		{
-			instructorProfiles.GET("", instructorProfileHandler.GetInstructorProfiles)
-			instructorProfiles.GET("/:id", instructorProfileHandler.GetInstructorProfile)
-			instructorProfiles.POST("", instructorProfileHandler.CreateInstructorProfile)
-			instructorProfiles.PUT("/:id", instructorProfileHandler.UpdateInstructorProfile)
-			instructorProfiles.DELETE("/:id", instructorProfileHandler.DeleteInstructorProfile)
-		}
-
-		// Course Sections routes
-		courseSections := api.Group("/course-sections")
-		{
-			courseSections.GET("", courseSectionHandler.GetCourseSections)
-			courseSections.GET("/:id", courseSectionHandler.GetCourseSection)
-			courseSections.POST("", courseSectionHandler.CreateCourseSection)
-			courseSections.PUT("/:id", courseSectionHandler.UpdateCourseSection)
-			courseSections.DELETE("/:id", courseSectionHandler.DeleteCourseSection)
-		}
-
-		// Course Lectures routes
-		courseLectures := api.Group("/course-lectures")
-		{
-			courseLectures.GET("", courseLectureHandler.GetCourseLectures)
-			courseLectures.GET("/:id", courseLectureHandler.GetCourseLecture)
-			courseLectures.POST("", courseLectureHandler.CreateCourseLecture)
-			courseLectures.PUT("/:id", courseLectureHandler.UpdateCourseLecture)
-			courseLectures.DELETE("/:id", courseLectureHandler.DeleteCourseLecture)
-		}
-
-		// Enrollments routes
-		enrollments := api.Group("/enrollments")
-		{
-			enrollments.GET("", enrollmentHandler.GetEnrollments)
-			enrollments.GET("/:id", enrollmentHandler.GetEnrollment)
-			enrollments.POST("", enrollmentHandler.CreateEnrollment)
-			enrollments.PUT("/:id", enrollmentHandler.UpdateEnrollment)
-			enrollments.DELETE("/:id", enrollmentHandler.DeleteEnrollment)
-		}
-
-		// Lecture Progress routes
-		lectureProgress := api.Group("/lecture-progress")
-		{
-			lectureProgress.GET("", lectureProgressHandler.GetLectureProgresses)
-			lectureProgress.GET("/:id", lectureProgressHandler.GetLectureProgress)
-			lectureProgress.POST("", lectureProgressHandler.CreateLectureProgress)
-			lectureProgress.PUT("/:id", lectureProgressHandler.UpdateLectureProgress)
-			lectureProgress.DELETE("/:id", lectureProgressHandler.DeleteLectureProgress)
-		}
-
-		// Course Reviews routes
-		courseReviews := api.Group("/course-reviews")
-		{
-			courseReviews.GET("", courseReviewHandler.GetCourseReviews)
-			courseReviews.GET("/:id", courseReviewHandler.GetCourseReview)
-			courseReviews.POST("", courseReviewHandler.CreateCourseReview)
-			courseReviews.PUT("/:id", courseReviewHandler.UpdateCourseReview)
-			courseReviews.DELETE("/:id", courseReviewHandler.DeleteCourseReview)
-		}
-
-		// Wishlists routes
-		wishlists := api.Group("/wishlists")
-		{
-			wishlists.GET("", wishlistHandler.GetWishlists)
-			wishlists.GET("/:id", wishlistHandler.GetWishlist)
-			wishlists.POST("", wishlistHandler.CreateWishlist)
-			wishlists.DELETE("/:id", wishlistHandler.DeleteWishlist)
-			wishlists.DELETE("/remove", wishlistHandler.RemoveFromWishlistByUserAndCourse)
-			wishlists.GET("/check", wishlistHandler.CheckWishlist)
-		}
-
-		// Coupons routes
-		coupons := api.Group("/coupons")
-		{
-			coupons.GET("", couponHandler.GetCoupons)
-			coupons.GET("/:id", couponHandler.GetCoupon)
-			coupons.POST("", couponHandler.CreateCoupon)
-			coupons.PUT("/:id", couponHandler.UpdateCoupon)
-			coupons.DELETE("/:id", couponHandler.DeleteCoupon)
-			coupons.POST("/validate", couponHandler.ValidateCoupon)
-		}
-
-		// Course Announcements routes
-		courseAnnouncements := api.Group("/course-announcements")
-		{
-			courseAnnouncements.GET("", courseAnnouncementHandler.GetCourseAnnouncements)
-			courseAnnouncements.GET("/:id", courseAnnouncementHandler.GetCourseAnnouncement)
-			courseAnnouncements.POST("", courseAnnouncementHandler.CreateCourseAnnouncement)
-			courseAnnouncements.PUT("/:id", courseAnnouncementHandler.UpdateCourseAnnouncement)
-			courseAnnouncements.DELETE("/:id", courseAnnouncementHandler.DeleteCourseAnnouncement)
-		}
-
-		// Course Q&A routes
-		courseQuestions := api.Group("/course-questions")
-		{
-			courseQuestions.GET("", courseQAHandler.GetCourseQuestions)
-			courseQuestions.GET("/:id", courseQAHandler.GetCourseQuestion)
-			courseQuestions.POST("", courseQAHandler.CreateCourseQuestion)
-			courseQuestions.PUT("/:id", courseQAHandler.UpdateCourseQuestion)
-			courseQuestions.DELETE("/:id", courseQAHandler.DeleteCourseQuestion)
-			courseQuestions.GET("/:question_id/answers", courseQAHandler.GetCourseAnswers)
-		}
-
-		// Course Answers routes
-		courseAnswers := api.Group("/course-answers")
-		{
-			courseAnswers.POST("", courseQAHandler.CreateCourseAnswer)
-			courseAnswers.PUT("/:id", courseQAHandler.UpdateCourseAnswer)
-			courseAnswers.DELETE("/:id", courseQAHandler.DeleteCourseAnswer)
-		}
-
-		// Notifications routes
-		notifications := api.Group("/notifications")
-		{
-			notifications.GET("", notificationHandler.GetNotifications)
-			notifications.GET("/:id", notificationHandler.GetNotification)
-			notifications.POST("", notificationHandler.CreateNotification)
-			notifications.PUT("/:id", notificationHandler.UpdateNotification)
-			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
-			notifications.PUT("/mark-all-read", notificationHandler.MarkAllAsRead)
-		}
-
-		// Course Tags routes
-		courseTags := api.Group("/course-tags")
-		{
-			courseTags.POST("", tagHandler.AddCourseTag)
-			courseTags.DELETE("/remove", tagHandler.RemoveCourseTag)
-		}
-	}
-
-	return r
-}
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"internal/api/apierror"
+	"internal/api/handlers"
+	"internal/api/middleware"
+	"internal/auth"
+	"internal/config"
+	"internal/database"
+	"internal/idempotency"
+	"internal/invoice"
+	"internal/metrics"
+	"internal/ratelimit"
+	"internal/storage"
+)
+
+func SetupRoutes(conn *database.DB, cfg *config.Config) *gin.Engine {
+	if cfg.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Create Gin router
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		panic(err) // validated with the config
+	}
+	r.NoRoute(apierror.NoRoute)
+	r.NoMethod(apierror.NoMethod)
+	apierror.UseJSONFieldNames()
+	apierror.RegisterRules()
+
+	// Add middleware
+	r.Use(middleware.Tracing())
+	r.Use(middleware.RequestID())
+	if cfg.Metrics.Enabled {
+		metrics.RegisterPoolStats(conn.Stats)
+		r.Use(middleware.Metrics())
+		r.GET(cfg.Metrics.Path, gin.WrapH(metrics.Handler()))
+	}
+	r.Use(middleware.StructuredLogger())
+	r.Use(apierror.Recovery())
+	r.Use(middleware.CORS(cfg.CORS))
+	r.Use(middleware.JSONMiddleware())
+
+	// Bearer access tokens identify the user; it runs before rate limiting
+	// so per-user policies see the user ID.
+	tokens := auth.NewTokens(cfg.JWT.Secret, cfg.JWT.Issuer, time.Duration(cfg.JWT.ExpireHours)*time.Hour)
+	r.Use(middleware.Authenticate(tokens))
+
+	// Handlers work against database/sql regardless of the pool driver
+	db := conn.DB
+
+	// Rate limiting: Default covers the whole API, the other policies are
+	// stacked on endpoints that are cheap to abuse.
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter = ratelimit.NewFromConfig(cfg.RateLimit, db)
+	}
+	limit := func(name string, p config.RateLimitPolicy) gin.HandlerFunc {
+		return middleware.RateLimit(limiter, ratelimit.PolicyFromConfig(name, p))
+	}
+
+	// Idempotency-Key replay for POSTs that create enrollments or money movements
+	idempotent := middleware.Idempotency(idempotency.NewStore(db, cfg.Idempotency.TTL))
+
+	// Rendered invoices are kept in the configured file storage
+	store, err := storage.New(cfg.Storage)
+	if err != nil {
+		panic(err)
+	}
+	invoicePublisher, err := invoice.NewPublisher(db, store, invoice.Seller{
+		Name:    cfg.Invoice.SellerName,
+		TaxCode: cfg.Invoice.SellerTaxCode,
+		Address: cfg.Invoice.SellerAddress,
+		Email:   cfg.Invoice.SellerEmail,
+	}, cfg.Invoice.FontPath)
+	if err != nil {
+		panic(err)
+	}
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(db, tokens)
+	categoryHandler := handlers.NewCategoryHandler(db)
+	userHandler := handlers.NewUserHandler(db)
+	courseHandler := handlers.NewCourseHandler(db)
+	tagHandler := handlers.NewTagHandler(db)
+	instructorProfileHandler := handlers.NewInstructorProfileHandler(db)
+	courseSectionHandler := handlers.NewCourseSectionHandler(db)
+	courseLectureHandler := handlers.NewCourseLectureHandler(db)
+	enrollmentHandler := handlers.NewEnrollmentHandler(db)
+	lectureProgressHandler := handlers.NewLectureProgressHandler(db)
+	courseReviewHandler := handlers.NewCourseReviewHandler(db)
+	wishlistHandler := handlers.NewWishlistHandler(db)
+	couponHandler := handlers.NewCouponHandler(db)
+	courseAnnouncementHandler := handlers.NewCourseAnnouncementHandler(db)
+	courseQAHandler := handlers.NewCourseQAHandler(db)
+	notificationHandler := handlers.NewNotificationHandler(db)
+	instructorAnalyticsHandler := handlers.NewInstructorAnalyticsHandler(db)
+	adminAnalyticsHandler := handlers.NewAdminAnalyticsHandler(db)
+	revenueShareRuleHandler := handlers.NewRevenueShareRuleHandler(db)
+	payoutHandler := handlers.NewPayoutHandler(db, cfg.Payouts)
+	promotionHandler := handlers.NewPromotionHandler(db, cfg.Promotions.CouponPolicy)
+	checkoutHandler := handlers.NewCheckoutHandler(db)
+	giftHandler := handlers.NewGiftHandler(db, cfg.Gifts.VoucherValidity)
+	subscriptionHandler := handlers.NewSubscriptionHandler(db)
+	organizationHandler := handlers.NewOrganizationHandler(db)
+	orderHandler := handlers.NewOrderHandler(db)
+	invoiceHandler := handlers.NewInvoiceHandler(db, invoicePublisher, invoice.Options{
+		Prefix:  cfg.Invoice.SeriesPrefix,
+		VATRate: cfg.Invoice.VATRate,
+	})
+
+	// API routes
+	api := r.Group("/api/v1", limit("default", cfg.RateLimit.Default))
+	{
+		// Health check
+		api.GET("/health", func(c *gin.Context) {
+			c.JSON(200, gin.H{
+				"status":  "ok",
+				"message": "API is running",
+			})
+		})
+
+		// Database health and pool statistics
+		api.GET("/health/db", func(c *gin.Context) {
+			if err := conn.PingContext(c.Request.Context()); err != nil {
+				c.JSON(503, gin.H{
+					"status":  "unavailable",
+					"message": "Database is unreachable",
+				})
+				return
+			}
+			c.JSON(200, gin.H{
+				"status": "ok",
+				"pool":   conn.Stats(),
+			})
+		})
+
+		// Categories routes
+		categories := api.Group("/categories")
+		{
+			categories.GET("", categoryHandler.GetCategories)
+			categories.GET("/:id", categoryHandler.GetCategory)
+			categories.POST("", categoryHandler.CreateCategory)
+			categories.PUT("/:id", categoryHandler.UpdateCategory)
+			categories.DELETE("/:id", categoryHandler.DeleteCategory)
+		}
+
+		// Auth routes
+		authRoutes := api.Group("/auth")
+		{
+			authRoutes.POST("/login", limit("auth", cfg.RateLimit.Auth), authHandler.Login)
+			authRoutes.GET("/me", authHandler.GetCurrentUser)
+		}
+
+		// Users routes
+		users := api.Group("/users")
+		{
+			users.GET("", userHandler.GetUsers)
+			users.GET("/:id", userHandler.GetUser)
+			users.POST("", limit("auth", cfg.RateLimit.Auth), userHandler.CreateUser)
+			users.PUT("/:id", userHandler.UpdateUser)
+			users.DELETE("/:id", userHandler.DeleteUser)
+			
+			// User notification stats
+			users.GET("/:id/notification-stats", notificationHandler.GetNotificationStats)
+		}
+
+		// Courses routes
+		courses := api.Group("/courses")
+		{
+			courses.GET("", courseHandler.GetCourses)
+			courses.GET("/by-slug/:slug", courseHandler.GetCourseBySlug)
+			courses.GET("/:id", courseHandler.GetCourse)
+			courses.GET("/:id/curriculum", courseHandler.GetCourseCurriculum)
+			courses.GET("/:id/access", courseHandler.GetCourseAccess)
+			courses.PUT("/:id/curriculum/order", courseHandler.ReorderCurriculum)
+			courses.POST("", courseHandler.CreateCourse)
+			courses.POST("/:id/clone", idempotent, courseHandler.CloneCourse)
+			courses.PUT("/:id", courseHandler.UpdateCourse)
+			courses.DELETE("/:id", courseHandler.DeleteCourse)
+			courses.GET("/:id/prices", courseHandler.GetCoursePrices)
+			courses.PUT("/:id/prices/:currency", courseHandler.SetCoursePrice)
+			courses.DELETE("/:id/prices/:currency", courseHandler.DeleteCoursePrice)
+			
+			// Course tags
+			courses.GET("/:id/tags", tagHandler.GetCourseTags)
+			
+			// Course review stats
+			courses.GET("/:id/review-stats", courseReviewHandler.GetCourseReviewStats)
+		}
+
+		// Tags routes
+		tags := api.Group("/tags")
+		{
+			tags.GET("", tagHandler.GetTags)
+			tags.GET("/:id", tagHandler.GetTag)
+			tags.POST("", tagHandler.CreateTag)
+			tags.PUT("/:id", tagHandler.UpdateTag)
+			tags.DELETE("/:id", tagHandler.DeleteTag)
+		}
+
+		// Instructor Profiles routes
+		instructorProfiles := api.Group("/instructor-profiles")
+		{
+			instructorProfiles.GET("", instructorProfileHandler.GetInstructorProfiles)
+			instructorProfiles.GET("/:id", instructorProfileHandler.GetInstructorProfile)
+			instructorProfiles.POST("", instructorProfileHandler.CreateInstructorProfile)
+			instructorProfiles.PUT("/:id", instructorProfileHandler.UpdateInstructorProfile)
+			instructorProfiles.DELETE("/:id", instructorProfileHandler.DeleteInstructorProfile)
+		}
+
+		// Instructor analytics routes (current instructor)
+		instructorAnalytics := api.Group("/instructors/me/analytics")
+		{
+			instructorAnalytics.GET("/summary", instructorAnalyticsHandler.GetSummary)
+			instructorAnalytics.GET("/enrollments", instructorAnalyticsHandler.GetEnrollments)
+			instructorAnalytics.GET("/revenue", instructorAnalyticsHandler.GetRevenue)
+			instructorAnalytics.GET("/ratings", instructorAnalyticsHandler.GetRatings)
+			instructorAnalytics.GET("/courses/:course_id/funnel", instructorAnalyticsHandler.GetCourseFunnel)
+		}
+
+		// Earnings statement (current instructor)
+		api.GET("/instructors/me/statement", payoutHandler.GetStatement)
+
+		// Admin analytics routes
+		adminAnalytics := api.Group("/admin/analytics")
+		{
+			adminAnalytics.GET("/dashboard", adminAnalyticsHandler.GetDashboard)
+			adminAnalytics.GET("/reports/:report", adminAnalyticsHandler.GetReport)
+			adminAnalytics.GET("/reports/:report/export", adminAnalyticsHandler.ExportReport)
+		}
+
+		// Revenue share rules and instructor payouts (admin)
+		revenueShareRules := api.Group("/admin/revenue-share-rules")
+		{
+			revenueShareRules.GET("", revenueShareRuleHandler.GetRevenueShareRules)
+			revenueShareRules.POST("", revenueShareRuleHandler.CreateRevenueShareRule)
+			revenueShareRules.PUT("/:id", revenueShareRuleHandler.UpdateRevenueShareRule)
+			revenueShareRules.DELETE("/:id", revenueShareRuleHandler.DeleteRevenueShareRule)
+		}
+
+		payoutBatches := api.Group("/admin/payout-batches")
+		{
+			payoutBatches.GET("", payoutHandler.GetPayoutBatches)
+			payoutBatches.GET("/:id", payoutHandler.GetPayoutBatch)
+			payoutBatches.POST("", idempotent, payoutHandler.CreatePayoutBatch)
+			payoutBatches.PUT("/:id/status", payoutHandler.UpdatePayoutBatchStatus)
+		}
+		api.PUT("/admin/payouts/:id/status", payoutHandler.UpdatePayoutStatus)
+
+		// Scheduled promotions (admin)
+		promotions := api.Group("/admin/promotions")
+		{
+			promotions.GET("", promotionHandler.GetPromotions)
+			promotions.GET("/:id", promotionHandler.GetPromotion)
+			promotions.POST("", promotionHandler.CreatePromotion)
+			promotions.PUT("/:id", promotionHandler.UpdatePromotion)
+			promotions.DELETE("/:id", promotionHandler.DeletePromotion)
+		}
+
+		// Cart pricing with promotions and coupon; rate limited like coupon
+		// validation since it also reveals whether a code exists
+		api.POST("/checkout/quote", limit("coupon_validate", cfg.RateLimit.CouponValidate), checkoutHandler.Quote)
+
+		// Gift orders and course vouchers (current user). Redemption is rate
+		// limited against code guessing
+		gifts := api.Group("/gifts")
+		{
+			gifts.GET("", giftHandler.GetGiftOrders)
+			gifts.POST("", idempotent, giftHandler.CreateGiftOrder)
+			gifts.GET("/received", giftHandler.GetReceivedVouchers)
+			gifts.POST("/redeem", limit("voucher_redeem", cfg.RateLimit.CouponValidate), giftHandler.RedeemVoucher)
+			gifts.GET("/:id", giftHandler.GetGiftOrder)
+		}
+
+		// Subscription plans (public catalogue, admin management) and the
+		// current user's subscriptions
+		subscriptionPlans := api.Group("/subscription-plans")
+		{
+			subscriptionPlans.GET("", subscriptionHandler.GetPlans)
+			subscriptionPlans.GET("/:id", subscriptionHandler.GetPlan)
+		}
+
+		adminSubscriptionPlans := api.Group("/admin/subscription-plans")
+		{
+			adminSubscriptionPlans.POST("", subscriptionHandler.CreatePlan)
+			adminSubscriptionPlans.PUT("/:id", subscriptionHandler.UpdatePlan)
+			adminSubscriptionPlans.DELETE("/:id", subscriptionHandler.DeletePlan)
+		}
+		api.PUT("/admin/subscription-payments/:id/status", subscriptionHandler.UpdatePaymentStatus)
+
+		subscriptions := api.Group("/subscriptions")
+		{
+			subscriptions.GET("", subscriptionHandler.GetSubscriptions)
+			subscriptions.POST("", idempotent, subscriptionHandler.CreateSubscription)
+			subscriptions.GET("/:id", subscriptionHandler.GetSubscription)
+			subscriptions.POST("/:id/cancel", subscriptionHandler.CancelSubscription)
+			subscriptions.POST("/:id/resume", subscriptionHandler.ResumeSubscription)
+		}
+
+		// Organization accounts: members, seats bought through orders, course
+		// assignments and progress reports, scoped to the caller's membership
+		organizations := api.Group("/organizations")
+		{
+			organizations.GET("", organizationHandler.GetOrganizations)
+			organizations.POST("", organizationHandler.CreateOrganization)
+			organizations.GET("/:id", organizationHandler.GetOrganization)
+			organizations.PUT("/:id", organizationHandler.UpdateOrganization)
+			organizations.GET("/:id/members", organizationHandler.GetMembers)
+			organizations.PUT("/:id/members/:user_id", organizationHandler.UpdateMember)
+			organizations.DELETE("/:id/members/:user_id", organizationHandler.RemoveMember)
+			organizations.GET("/:id/invitations", organizationHandler.GetInvitations)
+			organizations.POST("/:id/invitations", organizationHandler.InviteMember)
+			organizations.DELETE("/:id/invitations/:invitation_id", organizationHandler.RevokeInvitation)
+			organizations.GET("/:id/seats", organizationHandler.GetSeatPools)
+			organizations.POST("/:id/seats", idempotent, organizationHandler.PurchaseSeats)
+			organizations.GET("/:id/assignments", organizationHandler.GetAssignments)
+			organizations.POST("/:id/assignments", organizationHandler.CreateAssignments)
+			organizations.PUT("/:id/assignments/:assignment_id", organizationHandler.UpdateAssignment)
+			organizations.DELETE("/:id/assignments/:assignment_id", organizationHandler.DeleteAssignment)
+			organizations.GET("/:id/reports/progress", organizationHandler.GetProgressReport)
+		}
+
+		// Organization invitations sent to the current user's email; accepting
+		// one adds the membership
+		organizationInvitations := api.Group("/organization-invitations")
+		{
+			organizationInvitations.GET("", organizationHandler.GetMyInvitations)
+			organizationInvitations.POST("/:id/accept", organizationHandler.AcceptInvitation)
+			organizationInvitations.POST("/:id/decline", organizationHandler.DeclineInvitation)
+		}
+
+		// Invoices (order owner or admin)
+		orders := api.Group("/orders")
+		{
+			orders.GET("/:id/invoice", invoiceHandler.GetInvoice)
+			orders.POST("/:id/invoice", idempotent, invoiceHandler.IssueInvoice)
+		}
+		// Payment outcome of pending orders, reported by the payment provider
+		api.PUT("/admin/orders/:id/status", orderHandler.UpdateOrderStatus)
+
+		// Course Sections routes
+		courseSections := api.Group("/course-sections")
+		{
+			courseSections.GET("", courseSectionHandler.GetCourseSections)
+			courseSections.GET("/:id", courseSectionHandler.GetCourseSection)
+			courseSections.POST("", courseSectionHandler.CreateCourseSection)
+			courseSections.PUT("/:id", courseSectionHandler.UpdateCourseSection)
+			courseSections.DELETE("/:id", courseSectionHandler.DeleteCourseSection)
+		}
+
+		// Course Lectures routes
+		courseLectures := api.Group("/course-lectures")
+		{
+			courseLectures.GET("", courseLectureHandler.GetCourseLectures)
+			courseLectures.GET("/:id", courseLectureHandler.GetCourseLecture)
+			courseLectures.POST("", courseLectureHandler.CreateCourseLecture)
+			courseLectures.PUT("/:id", courseLectureHandler.UpdateCourseLecture)
+			courseLectures.DELETE("/:id", courseLectureHandler.DeleteCourseLecture)
+		}
+
+		// Enrollments routes
+		enrollments := api.Group("/enrollments")
+		{
+			enrollments.GET("", enrollmentHandler.GetEnrollments)
+			enrollments.GET("/:id", enrollmentHandler.GetEnrollment)
+			enrollments.POST("", idempotent, enrollmentHandler.CreateEnrollment)
+			enrollments.PUT("/:id", enrollmentHandler.UpdateEnrollment)
+			enrollments.DELETE("/:id", enrollmentHandler.DeleteEnrollment)
+		}
+
+		// Lecture Progress routes
+		lectureProgress := api.Group("/lecture-progress")
+		{
+			lectureProgress.GET("", lectureProgressHandler.GetLectureProgresses)
+			lectureProgress.GET("/:id", lectureProgressHandler.GetLectureProgress)
+			lectureProgress.POST("", lectureProgressHandler.CreateLectureProgress)
+			lectureProgress.PUT("/:id", lectureProgressHandler.UpdateLectureProgress)
+			lectureProgress.DELETE("/:id", lectureProgressHandler.DeleteLectureProgress)
+		}
+
+		// Course Reviews routes
+		courseReviews := api.Group("/course-reviews")
+		{
+			courseReviews.GET("", courseReviewHandler.GetCourseReviews)
+			courseReviews.GET("/:id", courseReviewHandler.GetCourseReview)
+			courseReviews.POST("", limit("reviews", cfg.RateLimit.Reviews), courseReviewHandler.CreateCourseReview)
+			courseReviews.PUT("/:id", courseReviewHandler.UpdateCourseReview)
+			courseReviews.DELETE("/:id", courseReviewHandler.DeleteCourseReview)
+		}
+
+		// Wishlists routes
+		wishlists := api.Group("/wishlists")
+		{
+			wishlists.GET("", wishlistHandler.GetWishlists)
+			wishlists.GET("/:id", wishlistHandler.GetWishlist)
+			wishlists.POST("", wishlistHandler.CreateWishlist)
+			wishlists.DELETE("/:id", wishlistHandler.DeleteWishlist)
+			wishlists.DELETE("/remove", wishlistHandler.RemoveFromWishlistByUserAndCourse)
+			wishlists.GET("/check", wishlistHandler.CheckWishlist)
+		}
+
+		// Coupons routes
+		coupons := api.Group("/coupons")
+		{
+			coupons.GET("", couponHandler.GetCoupons)
+			coupons.GET("/:id", couponHandler.GetCoupon)
+			coupons.POST("", couponHandler.CreateCoupon)
+			coupons.PUT("/:id", couponHandler.UpdateCoupon)
+			coupons.DELETE("/:id", couponHandler.DeleteCoupon)
+			coupons.POST("/validate", limit("coupon_validate", cfg.RateLimit.CouponValidate), couponHandler.ValidateCoupon)
+		}
+
+		// Course Announcements routes
+		courseAnnouncements := api.Group("/course-announcements")
+		{
+			courseAnnouncements.GET("", courseAnnouncementHandler.GetCourseAnnouncements)
+			courseAnnouncements.GET("/:id", courseAnnouncementHandler.GetCourseAnnouncement)
+			courseAnnouncements.POST("", courseAnnouncementHandler.CreateCourseAnnouncement)
+			courseAnnouncements.PUT("/:id", courseAnnouncementHandler.UpdateCourseAnnouncement)
+			courseAnnouncements.DELETE("/:id", courseAnnouncementHandler.DeleteCourseAnnouncement)
+		}
+
+		// Course Q&A routes
+		courseQuestions := api.Group("/course-questions")
+		{
+			courseQuestions.GET("", courseQAHandler.GetCourseQuestions)
+			courseQuestions.GET("/:id", courseQAHandler.GetCourseQuestion)
+			courseQuestions.POST("", limit("qa", cfg.RateLimit.QA), courseQAHandler.CreateCourseQuestion)
+			courseQuestions.PUT("/:id", courseQAHandler.UpdateCourseQuestion)
+			courseQuestions.DELETE("/:id", courseQAHandler.DeleteCourseQuestion)
+			courseQuestions.GET("/:id/answers", courseQAHandler.GetCourseAnswers)
+		}
+
+		// Course Answers routes
+		courseAnswers := api.Group("/course-answers")
+		{
+			courseAnswers.POST("", limit("qa", cfg.RateLimit.QA), courseQAHandler.CreateCourseAnswer)
+			courseAnswers.PUT("/:id", courseQAHandler.UpdateCourseAnswer)
+			courseAnswers.DELETE("/:id", courseQAHandler.DeleteCourseAnswer)
+		}
+
+		// Notifications routes
+		notifications := api.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.GetNotifications)
+			notifications.GET("/:id", notificationHandler.GetNotification)
+			notifications.POST("", notificationHandler.CreateNotification)
+			notifications.PUT("/:id", notificationHandler.UpdateNotification)
+			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
+			notifications.PUT("/mark-all-read", notificationHandler.MarkAllAsRead)
+		}
+
+		// Course Tags routes
+		courseTags := api.Group("/course-tags")
+		{
+			courseTags.POST("", tagHandler.AddCourseTag)
+			courseTags.DELETE("/remove", tagHandler.RemoveCourseTag)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,182 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"internal/config"
+	"internal/database"
+)
+
+const testUserID = "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11"
+
+var userColumns = []string{"id", "email", "username", "first_name", "last_name", "avatar_url", "bio",
+	"role", "is_verified", "created_at", "updated_at"}
+
+func newTestRouter(t *testing.T) (*gin.Engine, sqlmock.Sqlmock) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg := config.Default()
+	cfg.Metrics.Enabled = false
+	cfg.RateLimit.Enabled = false
+	cfg.Storage.LocalPath = t.TempDir()
+	cfg.JWT.Secret = "0123456789abcdef0123456789abcdef"
+	return SetupRoutes(&database.DB{DB: db}, cfg), mock
+}
+
+type response struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+func do(t *testing.T, r http.Handler, method, path, token, body string) (int, response) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res response
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("%s %s: decode %q: %v", method, path, w.Body.String(), err)
+	}
+	return w.Code, res
+}
+
+func expectUser(mock sqlmock.Sqlmock, role string) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT role FROM users WHERE id = \$1`).WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(role))
+	mock.ExpectQuery(`SELECT id, email, .* FROM users WHERE id = \$1`).WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(testUserID, "hoc.vien@example.com", "hocvien", "Hoc", "Vien", nil, nil, role, true, now, now))
+}
+
+func TestProtectedRouteRequiresToken(t *testing.T) {
+	r, mock := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		header string
+		code   string
+	}{
+		{"no token", "", "UNAUTHENTICATED"},
+		{"malformed token", "Bearer not-a-token", "INVALID_TOKEN"},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", "INVALID_TOKEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var res response
+			json.Unmarshal(w.Body.Bytes(), &res)
+			if w.Code != http.StatusUnauthorized || res.Error.Code != tt.code {
+				t.Fatalf("got %d %s, want 401 %s: %s", w.Code, res.Error.Code, tt.code, w.Body.String())
+			}
+		})
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginThenProtectedRoute(t *testing.T) {
+	r, mock := newTestRouter(t)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("mat-khau-bi-mat"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	mock.ExpectQuery(`SELECT id, email, .*, password_hash FROM users WHERE email = \$1`).
+		WithArgs("hoc.vien@example.com").
+		WillReturnRows(sqlmock.NewRows(append(userColumns, "password_hash")).
+			AddRow(testUserID, "hoc.vien@example.com", "hocvien", "Hoc", "Vien", nil, nil, "student", true, now, now, string(hash)))
+
+	status, res := do(t, r, http.MethodPost, "/api/v1/auth/login", "",
+		`{"email":"hoc.vien@example.com","password":"mat-khau-bi-mat"}`)
+	if status != http.StatusOK {
+		t.Fatalf("login: got %d %s", status, res.Error.Code)
+	}
+	var login struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.Unmarshal(res.Data, &login); err != nil || login.AccessToken == "" || login.TokenType != "Bearer" {
+		t.Fatalf("login response %s: %v", res.Data, err)
+	}
+
+	expectUser(mock, "student")
+	status, res = do(t, r, http.MethodGet, "/api/v1/auth/me", login.AccessToken, "")
+	if status != http.StatusOK {
+		t.Fatalf("me: got %d %s", status, res.Error.Code)
+	}
+	var me struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(res.Data, &me)
+	if me.ID != testUserID {
+		t.Fatalf("me: id = %q, want %q", me.ID, testUserID)
+	}
+
+	// Người dùng thường không tự đổi role của mình thành admin được
+	mock.ExpectQuery(`SELECT role FROM users WHERE id = \$1`).WithArgs(testUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("student"))
+	status, res = do(t, r, http.MethodPut, "/api/v1/users/"+testUserID, login.AccessToken, `{"role":"admin"}`)
+	if status != http.StatusForbidden || res.Error.Code != "FORBIDDEN" {
+		t.Fatalf("promote to admin: got %d %s, want 403 FORBIDDEN", status, res.Error.Code)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	r, mock := newTestRouter(t)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("mat-khau-bi-mat"), bcrypt.MinCost)
+	now := time.Now()
+	mock.ExpectQuery(`FROM users WHERE email = \$1`).
+		WillReturnRows(sqlmock.NewRows(append(userColumns, "password_hash")).
+			AddRow(testUserID, "hoc.vien@example.com", "hocvien", "Hoc", "Vien", nil, nil, "student", true, now, now, string(hash)))
+	mock.ExpectQuery(`FROM users WHERE email = \$1`).WillReturnRows(sqlmock.NewRows(append(userColumns, "password_hash")))
+
+	for _, body := range []string{
+		`{"email":"hoc.vien@example.com","password":"sai-mat-khau"}`,
+		`{"email":"khong.ton.tai@example.com","password":"mat-khau-bi-mat"}`,
+	} {
+		status, res := do(t, r, http.MethodPost, "/api/v1/auth/login", "", body)
+		if status != http.StatusUnauthorized || res.Error.Code != "INVALID_CREDENTIALS" {
+			t.Fatalf("%s: got %d %s, want 401 INVALID_CREDENTIALS", body, status, res.Error.Code)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIssueVerify(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+	tokens := NewTokens("0123456789abcdef0123456789abcdef", "toanthaycong", time.Hour)
+	token, expires, err := tokens.Issue("6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expires = %v, want %v", expires, now.Add(time.Hour))
+	}
+
+	claims, err := tokens.Verify(token, now.Add(59*time.Minute))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if claims.Subject != "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11" {
+		t.Fatalf("subject = %q", claims.Subject)
+	}
+
+	if _, err := tokens.Verify(token, now.Add(time.Hour)); err != ErrExpiredToken {
+		t.Fatalf("expired token: err = %v, want ErrExpiredToken", err)
+	}
+
+	tests := []struct {
+		name   string
+		tokens *Tokens
+		token  string
+	}{
+		{"other secret", NewTokens("another-secret-another-secret-00", "toanthaycong", time.Hour), token},
+		{"other issuer", NewTokens("0123456789abcdef0123456789abcdef", "someone-else", time.Hour), token},
+		{"tampered payload", tokens, tamper(token)},
+		{"not a jwt", tokens, "abc.def"},
+		{"empty", tokens, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.tokens.Verify(tt.token, now); err != ErrInvalidToken {
+				t.Fatalf("err = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+}
+
+// tamper swaps the payload for one with another subject, keeping the
+// original signature.
+func tamper(token string) string {
+	other, _, _ := NewTokens("x", "toanthaycong", time.Hour).Issue("someone-else", time.Now())
+	parts, otherParts := strings.Split(token, "."), strings.Split(other, ".")
+	return parts[0] + "." + otherParts[1] + "." + parts[2]
+}
@@ -1,40 +1,343 @@
-package config
-
-import (
-	"fmt"
-	"os"
-)
-
-type Config struct {
-	DBHost     string
-	DBPort     string
-	DBUser     string
-	DBPassword string
-	DBName     string
-	DBSSLMode  string
-	ServerPort string
-}
-
-func Load() *Config {
-	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "password123"),
-		DBName:     getEnv("DB_NAME", "toanthaycong"),
-		DBSSLMode:  getEnv("DB_SSL_MODE", "disable"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
-	}
-}
-
-func (c *Config) DatabaseURL() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		c.DBUser, c.DBPassword, c.DBHost, c.DBPort, c.DBName, c.DBSSLMode)
-}
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Config is the application configuration shared by every command in cmd/.
+// Values are layered: built-in defaults, then configs/config.{yaml,toml},
+// then configs/config.<env>.{yaml,toml}, then environment variables and
+// finally secrets read from *_FILE paths.
+type Config struct {
+	Env           string              `yaml:"env" env:"APP_ENV,ENV"`
+	LogLevel      string              `yaml:"log_level" env:"LOG_LEVEL"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	Storage       StorageConfig       `yaml:"storage"`
+	Mail          MailConfig          `yaml:"mail"`
+	CORS          CORSConfig          `yaml:"cors"`
+	Metrics       MetricsConfig       `yaml:"metrics"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Idempotency   IdempotencyConfig   `yaml:"idempotency"`
+	Analytics     AnalyticsConfig     `yaml:"analytics"`
+	Payouts       PayoutsConfig       `yaml:"payouts"`
+	Invoice       InvoiceConfig       `yaml:"invoice"`
+	Promotions    PromotionsConfig    `yaml:"promotions"`
+	Gifts         GiftsConfig         `yaml:"gifts"`
+	Subscriptions SubscriptionsConfig `yaml:"subscriptions"`
+}
+
+type ServerConfig struct {
+	Port            string        `yaml:"port" env:"SERVER_PORT,APP_PORT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+
+	// TrustedProxies lists the proxy CIDRs whose X-Forwarded-For is believed
+	// when resolving the client IP. Empty means the socket peer is the client.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES"`
+}
+
+type DatabaseConfig struct {
+	Host           string        `yaml:"host" env:"DB_HOST"`
+	Port           string        `yaml:"port" env:"DB_PORT"`
+	User           string        `yaml:"user" env:"DB_USER"`
+	Password       string        `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name           string        `yaml:"name" env:"DB_NAME"`
+	SSLMode        string        `yaml:"ssl_mode" env:"DB_SSL_MODE"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT"`
+
+	// Driver selects how connections are managed: "stdlib" uses database/sql's
+	// own pool over the pgx driver, "pgxpool" uses pgx's native pool and
+	// exposes it to database/sql through a thin adapter.
+	Driver          string        `yaml:"driver" env:"DB_DRIVER"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	MinConns        int           `yaml:"min_conns" env:"DB_MIN_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+
+	// StatementCacheCapacity is the per-connection prepared statement cache
+	// size; 0 disables the cache (needed behind PgBouncer transaction pooling).
+	StatementCacheCapacity int `yaml:"statement_cache_capacity" env:"DB_STATEMENT_CACHE_CAPACITY"`
+}
+
+type JWTConfig struct {
+	Secret      string `yaml:"secret" env:"JWT_SECRET" secret:"true"`
+	Issuer      string `yaml:"issuer" env:"JWT_ISSUER"`
+	ExpireHours int    `yaml:"expire_hours" env:"JWT_EXPIRE_HOURS"`
+}
+
+type StorageConfig struct {
+	Driver        string   `yaml:"driver" env:"STORAGE_DRIVER"`
+	LocalPath     string   `yaml:"local_path" env:"UPLOAD_PATH"`
+	PublicURL     string   `yaml:"public_url" env:"STORAGE_PUBLIC_URL"`
+	MaxUploadSize ByteSize `yaml:"max_upload_size" env:"UPLOAD_MAX_SIZE"`
+	S3Endpoint    string   `yaml:"s3_endpoint" env:"STORAGE_S3_ENDPOINT"`
+	S3Region      string   `yaml:"s3_region" env:"STORAGE_S3_REGION"`
+	S3Bucket      string   `yaml:"s3_bucket" env:"STORAGE_S3_BUCKET"`
+	S3AccessKey   string   `yaml:"s3_access_key" env:"STORAGE_S3_ACCESS_KEY" secret:"true"`
+	S3SecretKey   string   `yaml:"s3_secret_key" env:"STORAGE_S3_SECRET_KEY" secret:"true"`
+}
+
+type MailConfig struct {
+	Host     string `yaml:"host" env:"MAIL_HOST"`
+	Port     int    `yaml:"port" env:"MAIL_PORT"`
+	Username string `yaml:"username" env:"MAIL_USERNAME"`
+	Password string `yaml:"password" env:"MAIL_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"MAIL_FROM"`
+	UseTLS   bool   `yaml:"use_tls" env:"MAIL_USE_TLS"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" env:"METRICS_ENABLED"`
+	Path    string `yaml:"path" env:"METRICS_PATH"`
+}
+
+// TracingConfig selects where OpenTelemetry spans are exported. The OTEL_*
+// names follow the OpenTelemetry SDK environment conventions.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled" env:"TRACING_ENABLED"`
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER,OTEL_TRACES_EXPORTER"`
+	Endpoint    string  `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Insecure    bool    `yaml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE"`
+	ServiceName string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// RateLimitConfig configures request throttling. Backend "memory" keeps
+// buckets per process; "postgres" shares them between instances.
+type RateLimitConfig struct {
+	Enabled bool   `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Backend string `yaml:"backend" env:"RATE_LIMIT_BACKEND"`
+
+	// Default applies to every /api/v1 route; the others are stacked on
+	// top of it for the endpoints that are attractive to abuse.
+	Default        RateLimitPolicy `yaml:"default"`
+	Auth           RateLimitPolicy `yaml:"auth"`
+	CouponValidate RateLimitPolicy `yaml:"coupon_validate"`
+	Reviews        RateLimitPolicy `yaml:"reviews"`
+	QA             RateLimitPolicy `yaml:"qa"`
+}
+
+// RateLimitPolicy is a token bucket: Requests tokens are refilled evenly
+// over Period and at most Burst can be saved up. KeyBy is "ip" or "user"
+// (the authenticated user, falling back to the IP for anonymous calls).
+type RateLimitPolicy struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
+	Burst    int           `yaml:"burst"`
+	KeyBy    string        `yaml:"key_by"`
+}
+
+// IdempotencyConfig sets how long responses to requests carrying an
+// Idempotency-Key are kept for replay.
+type IdempotencyConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+// AnalyticsConfig sets how often the API refreshes the instructor analytics
+// materialized views. Zero disables the refresh, e.g. when another instance
+// or an external scheduler owns it.
+type AnalyticsConfig struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"ANALYTICS_REFRESH_INTERVAL"`
+}
+
+// PayoutsConfig controls instructor payout runs. Earnings become payable
+// once they are older than HoldingPeriod, which leaves room for refunds;
+// balances below the MinimumAmounts entry for their currency, in minor
+// units, are carried over to the next run. Currencies without an entry are
+// paid whenever the balance is positive.
+type PayoutsConfig struct {
+	HoldingPeriod  time.Duration    `yaml:"holding_period" env:"PAYOUTS_HOLDING_PERIOD"`
+	MinimumAmounts map[string]int64 `yaml:"minimum_amounts" env:"PAYOUTS_MINIMUM_AMOUNTS"`
+}
+
+// PromotionsConfig holds defaults for scheduled promotions. CouponPolicy is
+// how coupons combine with a promotion that does not set its own policy:
+// "stack", "exclusive" or "best_of".
+type PromotionsConfig struct {
+	CouponPolicy string `yaml:"coupon_policy" env:"PROMOTIONS_COUPON_POLICY"`
+}
+
+// GiftsConfig controls gift vouchers. A voucher can be redeemed until
+// VoucherValidity after its order was placed.
+type GiftsConfig struct {
+	VoucherValidity time.Duration `yaml:"voucher_validity" env:"GIFTS_VOUCHER_VALIDITY"`
+}
+
+// SubscriptionsConfig controls the subscription billing cycle. Renewal
+// payments are created RenewalLead before a period ends; an unpaid
+// subscription keeps its access for GracePeriod after the period ends and is
+// then expired. The API runs the billing pass every SchedulerInterval; zero
+// disables it, e.g. when cmd/subscriptions runs from cron instead.
+type SubscriptionsConfig struct {
+	SchedulerInterval time.Duration `yaml:"scheduler_interval" env:"SUBSCRIPTIONS_SCHEDULER_INTERVAL"`
+	RenewalLead       time.Duration `yaml:"renewal_lead" env:"SUBSCRIPTIONS_RENEWAL_LEAD"`
+	GracePeriod       time.Duration `yaml:"grace_period" env:"SUBSCRIPTIONS_GRACE_PERIOD"`
+}
+
+// InvoiceConfig describes the seller printed on invoices and how they are
+// numbered. Prices include VAT at VATRate basis points (1000 is 10%).
+// FontPath points to a TrueType font used for PDFs; without one PDFs fall
+// back to Courier, which drops Vietnamese diacritics.
+type InvoiceConfig struct {
+	SeriesPrefix  string `yaml:"series_prefix" env:"INVOICE_SERIES_PREFIX"`
+	VATRate       int64  `yaml:"vat_rate" env:"INVOICE_VAT_RATE"`
+	SellerName    string `yaml:"seller_name" env:"INVOICE_SELLER_NAME"`
+	SellerTaxCode string `yaml:"seller_tax_code" env:"INVOICE_SELLER_TAX_CODE"`
+	SellerAddress string `yaml:"seller_address" env:"INVOICE_SELLER_ADDRESS"`
+	SellerEmail   string `yaml:"seller_email" env:"INVOICE_SELLER_EMAIL"`
+	FontPath      string `yaml:"font_path" env:"INVOICE_FONT_PATH"`
+}
+
+// Default returns the configuration used for local development. Every
+// credential here is rejected by Validate when Env is "production".
+func Default() *Config {
+	return &Config{
+		Env:      "development",
+		LogLevel: "info",
+		Server: ServerConfig{
+			Port:            "8080",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Host:           "localhost",
+			Port:           "5432",
+			User:           "postgres",
+			Password:       "password123",
+			Name:           "toanthaycong",
+			SSLMode:        "disable",
+			ConnectTimeout: 5 * time.Second,
+
+			Driver:                 "stdlib",
+			MaxOpenConns:           25,
+			MaxIdleConns:           5,
+			MinConns:               0,
+			ConnMaxLifetime:        time.Hour,
+			ConnMaxIdleTime:        30 * time.Minute,
+			StatementCacheCapacity: 512,
+		},
+		JWT: JWTConfig{
+			Secret:      "your_jwt_secret_key_here",
+			Issuer:      "toanthaycong",
+			ExpireHours: 24,
+		},
+		Storage: StorageConfig{
+			Driver:        "local",
+			LocalPath:     "./uploads",
+			PublicURL:     "/uploads",
+			MaxUploadSize: 10 * MB,
+		},
+		Mail: MailConfig{
+			Host: "localhost",
+			Port: 1025,
+			From: "no-reply@toanthaycong.com",
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   []string{"*"},
+			AllowCredentials: false,
+			MaxAge:           12 * time.Hour,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Exporter:    "stdout",
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "toanthaycong-api",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			Backend:        "memory",
+			Default:        RateLimitPolicy{Requests: 300, Period: time.Minute, Burst: 100, KeyBy: "ip"},
+			Auth:           RateLimitPolicy{Requests: 10, Period: time.Minute, Burst: 5, KeyBy: "ip"},
+			CouponValidate: RateLimitPolicy{Requests: 10, Period: time.Minute, Burst: 5, KeyBy: "user"},
+			Reviews:        RateLimitPolicy{Requests: 5, Period: time.Hour, Burst: 3, KeyBy: "user"},
+			QA:             RateLimitPolicy{Requests: 20, Period: time.Hour, Burst: 5, KeyBy: "user"},
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		Analytics: AnalyticsConfig{
+			RefreshInterval: 15 * time.Minute,
+		},
+		Payouts: PayoutsConfig{
+			HoldingPeriod:  30 * 24 * time.Hour,
+			MinimumAmounts: map[string]int64{"VND": 100000, "USD": 2000, "EUR": 2000},
+		},
+		Invoice: InvoiceConfig{
+			SeriesPrefix: "TTC",
+			VATRate:      1000,
+			SellerName:   "Toán Thầy Công",
+			SellerEmail:  "billing@toanthaycong.com",
+		},
+		Promotions: PromotionsConfig{
+			CouponPolicy: "stack",
+		},
+		Gifts: GiftsConfig{
+			VoucherValidity: 365 * 24 * time.Hour,
+		},
+		Subscriptions: SubscriptionsConfig{
+			SchedulerInterval: 15 * time.Minute,
+			RenewalLead:       3 * 24 * time.Hour,
+			GracePeriod:       7 * 24 * time.Hour,
+		},
+	}
+}
+
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// DSN returns the PostgreSQL connection URL for the configured database.
+func (d DatabaseConfig) DSN() string {
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(d.User, d.Password),
+		Host:   net.JoinHostPort(d.Host, d.Port),
+		Path:   "/" + d.Name,
+	}
+	q := url.Values{}
+	q.Set("sslmode", d.SSLMode)
+	if d.ConnectTimeout > 0 {
+		q.Set("connect_timeout", strconv.Itoa(int(d.ConnectTimeout.Seconds())))
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// DatabaseURL is kept for callers that predate the Database section.
+func (c *Config) DatabaseURL() string {
+	return c.Database.DSN()
+}
+
+// Addr returns the listen address for the HTTP server.
+func (s ServerConfig) Addr() string {
+	return ":" + s.Port
+}
+
+func (c *Config) String() string {
+	return fmt.Sprintf("env=%s server=%s db=%s@%s/%s storage=%s", c.Env, c.Server.Addr(), c.Database.User,
+		net.JoinHostPort(c.Database.Host, c.Database.Port), c.Database.Name, c.Storage.Driver)
+}
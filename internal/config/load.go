@@ -0,0 +1,270 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDir is where Load looks for config files when CONFIG_FILE is unset.
+const DefaultDir = "configs"
+
+// Load builds the configuration from defaults, config files, environment
+// variables and *_FILE secrets, then validates the result.
+func Load() (*Config, error) {
+	// .env is optional; real deployments set variables directly.
+	_ = godotenv.Load()
+
+	cfg := Default()
+
+	files, err := configFiles()
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range files {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The environment may switch APP_ENV, which selects the overlay file,
+	// so env is applied once before and once after the overlay.
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+	if overlay := findFile(filepath.Join(DefaultDir, "config."+cfg.Env)); overlay != "" && os.Getenv("CONFIG_FILE") == "" {
+		if err := loadFile(cfg, overlay); err != nil {
+			return nil, err
+		}
+		if err := applyEnv(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func configFiles() ([]string, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+		return []string{path}, nil
+	}
+	if base := findFile(filepath.Join(DefaultDir, "config")); base != "" {
+		return []string{base}, nil
+	}
+	return nil, nil
+}
+
+func findFile(stem string) string {
+	for _, ext := range []string{".yaml", ".yml", ".toml"} {
+		if _, err := os.Stat(stem + ext); err == nil {
+			return stem + ext
+		}
+	}
+	return ""
+}
+
+func loadFile(cfg *Config, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, cfg); err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		// TOML is decoded generically and re-encoded as YAML so both formats
+		// share the yaml tags and duration/size parsing.
+		var raw map[string]interface{}
+		if err := toml.Unmarshal(content, &raw); err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		converted, err := yaml.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("convert config file %s: %w", path, err)
+		}
+		if err := yaml.Unmarshal(converted, cfg); err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported extension", path)
+	}
+	return nil
+}
+
+// applyEnv walks the config struct and overrides every field that has an
+// `env` tag. Each tag may list several names; the first one set wins. For
+// any name, NAME_FILE is also honoured and its trimmed contents used, which
+// is how Docker and Kubernetes secrets are mounted.
+func applyEnv(cfg *Config) error {
+	return applyEnvStruct(reflect.ValueOf(cfg).Elem())
+}
+
+func applyEnvStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		value := v.Field(i)
+
+		tag := field.Tag.Get("env")
+		if tag == "" {
+			if value.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Time{}) {
+				if err := applyEnvStruct(value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		raw, name, ok, err := lookupEnv(strings.Split(tag, ","))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err := setField(value, raw); err != nil {
+			return fmt.Errorf("env %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func lookupEnv(names []string) (value, name string, ok bool, err error) {
+	for _, name := range names {
+		if path := os.Getenv(name + "_FILE"); path != "" {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", name, false, fmt.Errorf("read %s_FILE: %w", name, err)
+			}
+			return strings.TrimSpace(string(content)), name + "_FILE", true, nil
+		}
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			return value, name, true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setField(v reflect.Value, raw string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return errors.New("unsupported slice type")
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		// KEY=VALUE pairs separated by commas, e.g. "VND=100000,USD=2000"
+		if v.Type().Key().Kind() != reflect.String || v.Type().Elem().Kind() != reflect.Int64 {
+			return errors.New("unsupported map type")
+		}
+		m := reflect.MakeMap(v.Type())
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("%q is not KEY=VALUE", item)
+			}
+			n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), reflect.ValueOf(n))
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// ByteSize is a size in bytes that accepts human units such as "10MB".
+type ByteSize int64
+
+const (
+	KB ByteSize = 1 << (10 * (iota + 1))
+	MB
+	GB
+)
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	s := strings.ToUpper(strings.TrimSpace(string(text)))
+	multiplier := ByteSize(1)
+	for _, unit := range []struct {
+		suffix string
+		size   ByteSize
+	}{{"GB", GB}, {"MB", MB}, {"KB", KB}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.size
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size %q", string(text))
+	}
+	*b = ByteSize(n) * multiplier
+	return nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyEnvMinimumAmounts(t *testing.T) {
+	t.Setenv("PAYOUTS_MINIMUM_AMOUNTS", "VND=200000, USD=5000")
+
+	cfg := Default()
+	if err := applyEnv(cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"VND": 200000, "USD": 5000}
+	if !reflect.DeepEqual(cfg.Payouts.MinimumAmounts, want) {
+		t.Errorf("MinimumAmounts = %v, want %v", cfg.Payouts.MinimumAmounts, want)
+	}
+
+	t.Setenv("PAYOUTS_MINIMUM_AMOUNTS", "VND:200000")
+	if err := applyEnv(Default()); err == nil {
+		t.Error("malformed pair accepted")
+	}
+}
+
+func TestValidateMinimumAmounts(t *testing.T) {
+	cfg := Default()
+	cfg.Payouts.MinimumAmounts = map[string]int64{"VND": -1, "JPY": 100}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("invalid minimums accepted")
+	}
+	for _, want := range []string{"payouts.minimum_amounts.VND", `unsupported currency "JPY"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
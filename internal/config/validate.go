@@ -0,0 +1,278 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"internal/money"
+)
+
+// weakSecrets are values that must never reach production even when they are
+// not the built-in default (they appear in .env.example and docker-compose).
+var weakSecrets = map[string]bool{
+	"":                         true,
+	"password":                 true,
+	"password123":              true,
+	"postgres":                 true,
+	"admin123":                 true,
+	"your_jwt_secret_key_here": true,
+}
+
+// Validate checks the configuration for values the application cannot run
+// with. In production it also refuses default or weak credentials.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	switch c.Env {
+	case "development", "test", "staging", "production":
+	default:
+		add("env must be one of development, test, staging, production (got %q)", c.Env)
+	}
+
+	if c.Server.Port == "" {
+		add("server.port is required")
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		add("server.shutdown_timeout must not be negative")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("server.trusted_proxies: %q is not an IP address or CIDR", proxy)
+		}
+	}
+
+	if c.Database.Host == "" || c.Database.Name == "" || c.Database.User == "" {
+		add("database.host, database.name and database.user are required")
+	}
+	switch c.Database.SSLMode {
+	case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+	default:
+		add("database.ssl_mode %q is not a valid libpq sslmode", c.Database.SSLMode)
+	}
+	switch c.Database.Driver {
+	case "stdlib", "pgxpool":
+	default:
+		add("database.driver must be stdlib or pgxpool (got %q)", c.Database.Driver)
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 || c.Database.MinConns < 0 {
+		add("database pool sizes must not be negative")
+	}
+	if c.Database.Driver == "pgxpool" && c.Database.MaxOpenConns == 0 {
+		add("database.max_open_conns must be positive for the pgxpool driver")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MinConns > c.Database.MaxOpenConns {
+		add("database.min_conns (%d) exceeds database.max_open_conns (%d)", c.Database.MinConns, c.Database.MaxOpenConns)
+	}
+	if c.Database.ConnMaxLifetime < 0 || c.Database.ConnMaxIdleTime < 0 {
+		add("database connection lifetimes must not be negative")
+	}
+	if c.Database.StatementCacheCapacity < 0 {
+		add("database.statement_cache_capacity must not be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns (%d) exceeds database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	if c.JWT.ExpireHours <= 0 {
+		add("jwt.expire_hours must be positive")
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.LocalPath == "" {
+			add("storage.local_path is required for the local driver")
+		}
+	case "s3":
+		if c.Storage.S3Bucket == "" {
+			add("storage.s3_bucket is required for the s3 driver")
+		}
+	default:
+		add("storage.driver must be local or s3 (got %q)", c.Storage.Driver)
+	}
+	if c.Storage.MaxUploadSize <= 0 {
+		add("storage.max_upload_size must be positive")
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		add("cors.allowed_origins must list at least one origin")
+	}
+	if c.CORS.AllowCredentials && c.allowsAnyOrigin() {
+		add("cors.allow_credentials cannot be combined with the * origin")
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		add("metrics.path must start with /")
+	}
+
+	if c.Tracing.Enabled {
+		switch c.Tracing.Exporter {
+		case "otlp", "stdout":
+		default:
+			add("tracing.exporter must be otlp or stdout (got %q)", c.Tracing.Exporter)
+		}
+		if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint == "" {
+			add("tracing.endpoint is required for the otlp exporter")
+		}
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio must be between 0 and 1")
+	}
+
+	if c.RateLimit.Enabled {
+		switch c.RateLimit.Backend {
+		case "memory", "postgres":
+		default:
+			add("rate_limit.backend must be memory or postgres (got %q)", c.RateLimit.Backend)
+		}
+		policies := map[string]RateLimitPolicy{
+			"default":         c.RateLimit.Default,
+			"auth":            c.RateLimit.Auth,
+			"coupon_validate": c.RateLimit.CouponValidate,
+			"reviews":         c.RateLimit.Reviews,
+			"qa":              c.RateLimit.QA,
+		}
+		for _, name := range []string{"default", "auth", "coupon_validate", "reviews", "qa"} {
+			p := policies[name]
+			if p.Requests <= 0 || p.Period <= 0 || p.Burst <= 0 {
+				add("rate_limit.%s requires positive requests, period and burst", name)
+			}
+			if p.KeyBy != "ip" && p.KeyBy != "user" {
+				add("rate_limit.%s.key_by must be ip or user (got %q)", name, p.KeyBy)
+			}
+		}
+	}
+
+	if c.Idempotency.TTL <= 0 {
+		add("idempotency.ttl must be positive")
+	}
+
+	if c.Analytics.RefreshInterval < 0 {
+		add("analytics.refresh_interval must not be negative")
+	}
+
+	if c.Payouts.HoldingPeriod < 0 {
+		add("payouts.holding_period must not be negative")
+	}
+	for currency, amount := range c.Payouts.MinimumAmounts {
+		if amount < 0 {
+			add("payouts.minimum_amounts.%s must not be negative", currency)
+		}
+		if !money.IsSupported(currency) {
+			add("payouts.minimum_amounts: unsupported currency %q", currency)
+		}
+	}
+
+	if !validSeriesPrefix(c.Invoice.SeriesPrefix) {
+		add("invoice.series_prefix must be 1-15 upper-case letters or digits (got %q)", c.Invoice.SeriesPrefix)
+	}
+	if c.Invoice.VATRate < 0 || c.Invoice.VATRate >= 10000 {
+		add("invoice.vat_rate must be in [0, 10000) basis points (got %d)", c.Invoice.VATRate)
+	}
+	if c.Invoice.SellerName == "" {
+		add("invoice.seller_name is required")
+	}
+
+	switch c.Promotions.CouponPolicy {
+	case "stack", "exclusive", "best_of":
+	default:
+		add("promotions.coupon_policy must be stack, exclusive or best_of (got %q)", c.Promotions.CouponPolicy)
+	}
+
+	if c.Gifts.VoucherValidity <= 0 {
+		add("gifts.voucher_validity must be positive")
+	}
+
+	if c.Subscriptions.SchedulerInterval < 0 {
+		add("subscriptions.scheduler_interval must not be negative")
+	}
+	if c.Subscriptions.RenewalLead < 0 {
+		add("subscriptions.renewal_lead must not be negative")
+	}
+	if c.Subscriptions.GracePeriod < 0 {
+		add("subscriptions.grace_period must not be negative")
+	}
+
+	if c.IsProduction() {
+		problems = append(problems, c.productionProblems()...)
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration:\n  - " + strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (c *Config) productionProblems() []string {
+	var problems []string
+
+	// Every field tagged secret:"true" that still holds its default (or a
+	// well-known weak value) is rejected.
+	defaults := reflect.ValueOf(Default()).Elem()
+	walkSecrets(reflect.ValueOf(c).Elem(), defaults, "", func(path, value, def string) {
+		if value == def && value == "" {
+			return // optional integration left unconfigured
+		}
+		if value == def || weakSecrets[value] {
+			problems = append(problems, fmt.Sprintf("%s uses a default or weak value in production", path))
+		}
+	})
+
+	if c.Database.Password == "" {
+		problems = append(problems, "database.password must be set in production")
+	}
+	if len(c.JWT.Secret) < 32 {
+		problems = append(problems, "jwt.secret must be at least 32 bytes in production")
+	}
+	if c.allowsAnyOrigin() {
+		problems = append(problems, "cors.allowed_origins must not contain * in production")
+	}
+	return problems
+}
+
+func walkSecrets(v, def reflect.Value, prefix string, visit func(path, value, def string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if field.Type.Kind() == reflect.Struct {
+			walkSecrets(v.Field(i), def.Field(i), path, visit)
+			continue
+		}
+		if field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.String {
+			visit(path, v.Field(i).String(), def.Field(i).String())
+		}
+	}
+}
+
+func (c *Config) allowsAnyOrigin() bool {
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// validSeriesPrefix keeps invoice numbers such as "TTC2026-000001" safe to
+// use in storage keys and file names.
+func validSeriesPrefix(p string) bool {
+	if p == "" || len(p) > 15 {
+		return false
+	}
+	for _, r := range p {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
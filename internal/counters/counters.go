@@ -0,0 +1,128 @@
+// Package counters checks the denormalized counter columns on courses and
+// instructor_profiles against their source tables. The columns are kept up
+// to date by database triggers; the expected_*_counters SQL functions those
+// triggers use are the single definition of each value, so Reconcile only
+// compares and, when asked, re-runs the same refresh functions.
+package counters
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Drift is one stored counter that differs from its recomputed value.
+type Drift struct {
+	Table  string
+	ID     string
+	Column string
+	Stored string
+	Actual string
+}
+
+// check describes one table: the row key, the refresh function and the
+// counter columns compared between the stored row (s) and expected (e).
+type check struct {
+	table   string
+	key     string
+	expect  string
+	refresh string
+	columns []string
+}
+
+var checks = []check{
+	{
+		table:   "courses",
+		key:     "id",
+		expect:  "expected_course_counters",
+		refresh: "refresh_course_counters",
+		columns: []string{"total_students", "total_reviews", "rating", "total_lectures", "duration_hours"},
+	},
+	{
+		table:   "instructor_profiles",
+		key:     "user_id",
+		expect:  "expected_instructor_counters",
+		refresh: "refresh_instructor_counters",
+		columns: []string{"rating", "total_students", "total_courses", "total_reviews"},
+	},
+}
+
+// Reconcile recomputes every counter and returns the ones that drifted.
+// When fix is true the drifted rows are refreshed in a single transaction;
+// the returned drift still describes the values found before the fix.
+func Reconcile(ctx context.Context, db *sql.DB, fix bool) ([]Drift, error) {
+	var drift []Drift
+	for _, ch := range checks {
+		d, err := ch.find(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		drift = append(drift, d...)
+	}
+	if !fix || len(drift) == 0 {
+		return drift, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, ch := range checks {
+		var ids []string
+		seen := map[string]bool{}
+		for _, d := range drift {
+			if d.Table == ch.table && !seen[d.ID] {
+				seen[d.ID] = true
+				ids = append(ids, d.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "SELECT "+ch.refresh+"(id) FROM unnest($1::uuid[]) AS id", ids); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// find returns one Drift per differing column. Values are compared as text
+// so DECIMAL ratings and INTEGER counts share one code path.
+func (ch check) find(ctx context.Context, db *sql.DB) ([]Drift, error) {
+	query := "SELECT s." + ch.key + "::text"
+	for _, col := range ch.columns {
+		query += ", COALESCE(s." + col + "::text, ''), e." + col + "::text"
+	}
+	query += " FROM " + ch.table + " s CROSS JOIN LATERAL " + ch.expect + "(s." + ch.key + ") e" +
+		" ORDER BY s." + ch.key
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drift []Drift
+	values := make([]string, 2*len(ch.columns))
+	dest := make([]interface{}, 1+len(values))
+	for rows.Next() {
+		var id string
+		dest[0] = &id
+		for i := range values {
+			dest[i+1] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, col := range ch.columns {
+			if stored, actual := values[2*i], values[2*i+1]; stored != actual {
+				drift = append(drift, Drift{Table: ch.table, ID: id, Column: col, Stored: stored, Actual: actual})
+			}
+		}
+	}
+	return drift, rows.Err()
+}
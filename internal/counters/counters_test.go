@@ -0,0 +1,83 @@
+package counters
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	courseID     = "0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c"
+	otherID      = "7a6b5c4d-3e2f-4a1b-9c8d-7e6f5a4b3c2d"
+	instructorID = "3e2d1c0b-9a8f-4e7d-8c6b-5a4f3e2d1c0b"
+)
+
+// idsConverter passes the []string of refresh IDs through as pgx would.
+type idsConverter struct{}
+
+func (idsConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	if ids, ok := v.([]string); ok {
+		return ids, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+// expectCounters answers both checks: one course has drifted in two
+// columns (a NULL rating reads as ”), the other course and the instructor
+// match.
+func expectCounters(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`FROM courses s CROSS JOIN LATERAL expected_course_counters\(s.id\) e ORDER BY s.id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id",
+			"s_total_students", "e_total_students", "s_total_reviews", "e_total_reviews", "s_rating", "e_rating",
+			"s_total_lectures", "e_total_lectures", "s_duration_hours", "e_duration_hours"}).
+			AddRow(courseID, "12", "13", "2", "2", "", "4.50", "8", "8", "3", "3").
+			AddRow(otherID, "0", "0", "0", "0", "0.00", "0.00", "1", "1", "0", "0"))
+	mock.ExpectQuery(`FROM instructor_profiles s CROSS JOIN LATERAL expected_instructor_counters\(s.user_id\) e`).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id",
+			"s_rating", "e_rating", "s_total_students", "e_total_students",
+			"s_total_courses", "e_total_courses", "s_total_reviews", "e_total_reviews"}).
+			AddRow(instructorID, "4.50", "4.50", "13", "13", "2", "2", "2", "2"))
+}
+
+func TestReconcile(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(idsConverter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	want := []Drift{
+		{Table: "courses", ID: courseID, Column: "total_students", Stored: "12", Actual: "13"},
+		{Table: "courses", ID: courseID, Column: "rating", Stored: "", Actual: "4.50"},
+	}
+
+	// Report only.
+	expectCounters(mock)
+	drift, err := Reconcile(context.Background(), db, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("drift = %+v, want %+v", drift, want)
+	}
+
+	// -fix refreshes each drifted row once, and only in its own table.
+	expectCounters(mock)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT refresh_course_counters\(id\) FROM unnest\(\$1::uuid\[\]\) AS id`).
+		WithArgs([]string{courseID}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	drift, err = Reconcile(context.Background(), db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("drift after fix = %+v, want the values found before it", drift)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
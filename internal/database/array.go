@@ -0,0 +1,31 @@
+package database
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// pgtype.Map caches per-type state and is not safe for concurrent use, so
+// scanners borrow one from a pool.
+var typeMaps = sync.Pool{
+	New: func() interface{} { return pgtype.NewMap() },
+}
+
+// Array returns a sql.Scanner that decodes a PostgreSQL array column into
+// dest, e.g. rows.Scan(database.Array(&course.Requirements)). Slices can be
+// passed as query arguments directly; the pgx driver encodes them natively.
+func Array(dest interface{}) sql.Scanner {
+	return &arrayScanner{dest: dest}
+}
+
+type arrayScanner struct {
+	dest interface{}
+}
+
+func (a *arrayScanner) Scan(src interface{}) error {
+	m := typeMaps.Get().(*pgtype.Map)
+	defer typeMaps.Put(m)
+	return m.SQLScanner(a.dest).Scan(src)
+}
@@ -0,0 +1,106 @@
+// Package database opens the PostgreSQL connection pool used by the API and
+// exposes pool statistics for health checks and monitoring.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"internal/config"
+)
+
+// DB wraps *sql.DB so handlers keep using database/sql. With the pgxpool
+// driver it also holds the native pool, which reports the pool statistics
+// and is closed with the handle.
+type DB struct {
+	*sql.DB
+	pool   *pgxpool.Pool
+	driver string
+}
+
+// Open connects with the configured driver, applies pool limits and pings
+// the server before returning.
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*DB, error) {
+	var db *DB
+	switch cfg.Driver {
+	case "pgxpool":
+		poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("parse database config: %w", err)
+		}
+		configureConn(poolConfig.ConnConfig, cfg)
+		poolConfig.MaxConns = int32(cfg.MaxOpenConns)
+		poolConfig.MinConns = int32(cfg.MinConns)
+		if cfg.ConnMaxLifetime > 0 {
+			poolConfig.MaxConnLifetime = cfg.ConnMaxLifetime
+		}
+		if cfg.ConnMaxIdleTime > 0 {
+			poolConfig.MaxConnIdleTime = cfg.ConnMaxIdleTime
+		}
+
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			return nil, fmt.Errorf("create pgx pool: %w", err)
+		}
+		db = &DB{DB: stdlib.OpenDBFromPool(pool), pool: pool, driver: cfg.Driver}
+
+	default:
+		connConfig, err := pgx.ParseConfig(cfg.DSN())
+		if err != nil {
+			return nil, fmt.Errorf("parse database config: %w", err)
+		}
+		configureConn(connConfig, cfg)
+
+		sqlDB := stdlib.OpenDB(*connConfig)
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		db = &DB{DB: sqlDB, driver: "stdlib"}
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout(cfg))
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+func configureConn(connConfig *pgx.ConnConfig, cfg config.DatabaseConfig) {
+	connConfig.Tracer = queryTracer{}
+	connConfig.StatementCacheCapacity = cfg.StatementCacheCapacity
+	if cfg.StatementCacheCapacity == 0 {
+		// Without a statement cache every query would be prepared and described
+		// first, an extra round trip; exec mode sends it as an unnamed statement
+		// with the parameters text-encoded from their Go types instead.
+		connConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	}
+}
+
+func pingTimeout(cfg config.DatabaseConfig) time.Duration {
+	if cfg.ConnectTimeout > 0 {
+		return cfg.ConnectTimeout
+	}
+	return 5 * time.Second
+}
+
+// Driver reports which driver the pool was opened with.
+func (db *DB) Driver() string {
+	return db.driver
+}
+
+// Close closes the database/sql handle and, if present, the native pool.
+func (db *DB) Close() error {
+	err := db.DB.Close()
+	if db.pool != nil {
+		db.pool.Close()
+	}
+	return err
+}
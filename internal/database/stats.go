@@ -0,0 +1,57 @@
+package database
+
+import "time"
+
+// PoolStats is a driver-independent snapshot of the connection pool. The
+// counts and durations after Idle are totals since the pool was opened;
+// AcquireCount and CanceledAcquireCount are only kept by pgxpool.
+type PoolStats struct {
+	Driver            string        `json:"driver"`
+	MaxOpenConns      int           `json:"max_open_conns"`
+	OpenConns         int           `json:"open_conns"`
+	InUse             int           `json:"in_use"`
+	Idle              int           `json:"idle"`
+	WaitCount         int64         `json:"wait_count"`
+	WaitDuration      time.Duration `json:"wait_duration_ns"`
+	MaxIdleClosed     int64         `json:"max_idle_closed"`
+	MaxLifetimeClosed int64         `json:"max_lifetime_closed"`
+
+	AcquireCount         int64 `json:"acquire_count,omitempty"`
+	CanceledAcquireCount int64 `json:"canceled_acquire_count,omitempty"`
+}
+
+// Stats returns the current pool statistics. For pgxpool the numbers come
+// from the native pool, since the database/sql adapter on top of it does not
+// hold connections of its own.
+func (db *DB) Stats() PoolStats {
+	if db.pool != nil {
+		s := db.pool.Stat()
+		return PoolStats{
+			Driver:            db.driver,
+			MaxOpenConns:      int(s.MaxConns()),
+			OpenConns:         int(s.TotalConns()),
+			InUse:             int(s.AcquiredConns()),
+			Idle:              int(s.IdleConns()),
+			WaitCount:         s.EmptyAcquireCount(),
+			WaitDuration:      s.AcquireDuration(),
+			MaxIdleClosed:     s.MaxIdleDestroyCount(),
+			MaxLifetimeClosed: s.MaxLifetimeDestroyCount(),
+
+			AcquireCount:         s.AcquireCount(),
+			CanceledAcquireCount: s.CanceledAcquireCount(),
+		}
+	}
+
+	s := db.DB.Stats()
+	return PoolStats{
+		Driver:            db.driver,
+		MaxOpenConns:      s.MaxOpenConnections,
+		OpenConns:         s.OpenConnections,
+		InUse:             s.InUse,
+		Idle:              s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitDuration:      s.WaitDuration,
+		MaxIdleClosed:     s.MaxIdleClosed + s.MaxIdleTimeClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
+	}
+}
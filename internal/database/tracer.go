@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"internal/tracing"
+)
+
+// maxStatementLength caps the db.statement attribute; some list queries are
+// assembled dynamically and grow with the number of filters.
+const maxStatementLength = 2048
+
+// queryTracer opens one span per SQL statement as a child of the span in the
+// query context, which the handlers pass as the request context.
+type queryTracer struct{}
+
+type querySpanKey struct{}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	name, operation := queryName(data.SQL)
+	statement := strings.TrimSpace(data.SQL)
+	if len(statement) > maxStatementLength {
+		statement = statement[:maxStatementLength]
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperation(operation),
+			semconv.DBStatement(statement),
+			attribute.String("db.query.name", name),
+		),
+	)
+	return context.WithValue(ctx, querySpanKey{}, span)
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span, ok := ctx.Value(querySpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	span.End()
+}
+
+var (
+	// "-- name: GetCourse :one" is the sqlc annotation used in internal/db/queries.
+	queryNameComment = regexp.MustCompile(`(?m)^\s*--\s*name:\s*(\w+)`)
+	leadingComments  = regexp.MustCompile(`^(\s*--[^\n]*\n)+`)
+	tableAfter       = regexp.MustCompile(`(?i)\b(?:from|into|update)\s+([a-z_][a-z0-9_.]*)`)
+)
+
+// queryName derives a low-cardinality span name for a statement. An explicit
+// "-- name: X" comment wins; otherwise the name is the operation and the
+// first table it touches, e.g. "SELECT enrollments".
+func queryName(sql string) (name, operation string) {
+	if m := queryNameComment.FindStringSubmatch(sql); m != nil {
+		name = m[1]
+	}
+
+	body := strings.TrimSpace(leadingComments.ReplaceAllString(sql, ""))
+	if i := strings.IndexFunc(body, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' || r == '(' }); i > 0 {
+		operation = strings.ToUpper(body[:i])
+	} else {
+		operation = strings.ToUpper(body)
+	}
+
+	if name != "" {
+		return name, operation
+	}
+	if m := tableAfter.FindStringSubmatch(body); m != nil {
+		return operation + " " + strings.ToLower(m[1]), operation
+	}
+	return operation, operation
+}
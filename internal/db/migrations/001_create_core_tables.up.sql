@@ -1,128 +1,128 @@
--- Migration: 001_create_core_tables.sql
-CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-CREATE EXTENSION IF NOT EXISTS "citext";
-
--- Bảng người dùng
-CREATE TABLE users (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    email CITEXT UNIQUE NOT NULL,
-    username VARCHAR(50) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    first_name VARCHAR(100) NOT NULL,
-    last_name VARCHAR(100) NOT NULL,
-    avatar_url TEXT,
-    bio TEXT,
-    role VARCHAR(20) NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'instructor', 'admin')),
-    is_verified BOOLEAN DEFAULT FALSE,
-    verification_token VARCHAR(255),
-    reset_password_token VARCHAR(255),
-    reset_password_expires TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Hồ sơ giảng viên
-CREATE TABLE instructor_profiles (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID UNIQUE NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    title VARCHAR(200),
-    expertise TEXT[],
-    experience_years INTEGER DEFAULT 0,
-    rating DECIMAL(3,2) DEFAULT 0.0,
-    total_students INTEGER DEFAULT 0,
-    total_courses INTEGER DEFAULT 0,
-    total_reviews INTEGER DEFAULT 0,
-    website_url TEXT,
-    linkedin_url TEXT,
-    github_url TEXT,
-    is_approved BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Danh mục khóa học
-CREATE TABLE categories (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    name VARCHAR(100) UNIQUE NOT NULL,
-    slug VARCHAR(100) UNIQUE NOT NULL,
-    description TEXT,
-    icon_url TEXT,
-    parent_id UUID REFERENCES categories(id) ON DELETE SET NULL,
-    sort_order INTEGER DEFAULT 0,
-    is_active BOOLEAN DEFAULT TRUE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Khóa học
-CREATE TABLE courses (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    title VARCHAR(200) NOT NULL,
-    slug VARCHAR(200) UNIQUE NOT NULL,
-    description TEXT,
-    short_description TEXT,
-    thumbnail_url TEXT,
-    preview_video_url TEXT,
-    instructor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
-    price DECIMAL(10,2) NOT NULL DEFAULT 0.00,
-    discount_price DECIMAL(10,2),
-    language VARCHAR(10) NOT NULL DEFAULT 'vi',
-    level VARCHAR(20) NOT NULL DEFAULT 'beginner' CHECK (level IN ('beginner', 'intermediate', 'advanced')),
-    duration_hours INTEGER DEFAULT 0,
-    total_lectures INTEGER DEFAULT 0,
-    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'pending', 'published', 'archived')),
-    requirements TEXT[],
-    what_you_learn TEXT[],
-    target_audience TEXT[],
-    rating DECIMAL(3,2) DEFAULT 0.0,
-    total_students INTEGER DEFAULT 0,
-    total_reviews INTEGER DEFAULT 0,
-    is_featured BOOLEAN DEFAULT FALSE,
-    published_at TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
-);
-
--- Chương của khóa học
-CREATE TABLE course_sections (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
-    title VARCHAR(200) NOT NULL,
-    description TEXT,
-    sort_order INTEGER NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(course_id, sort_order)
-);
-
--- Bài giảng
-CREATE TABLE course_lectures (
-    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    section_id UUID NOT NULL REFERENCES course_sections(id) ON DELETE CASCADE,
-    title VARCHAR(200) NOT NULL,
-    description TEXT,
-    content_type VARCHAR(20) NOT NULL DEFAULT 'video' CHECK (content_type IN ('video', 'article', 'quiz', 'file')),
-    video_url TEXT,
-    video_duration INTEGER, -- in seconds
-    article_content TEXT,
-    file_url TEXT,
-    sort_order INTEGER NOT NULL,
-    is_preview BOOLEAN DEFAULT FALSE,
-    is_downloadable BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(section_id, sort_order)
-);
-
-CREATE INDEX idx_users_email ON users(email);
-CREATE INDEX idx_users_role ON users(role);
-CREATE INDEX idx_instructor_profiles_user_id ON instructor_profiles(user_id);
-CREATE INDEX idx_categories_parent_id ON categories(parent_id);
-CREATE INDEX idx_categories_slug ON categories(slug);
-CREATE INDEX idx_courses_instructor_id ON courses(instructor_id);
-CREATE INDEX idx_courses_category_id ON courses(category_id);
-CREATE INDEX idx_courses_status ON courses(status);
-CREATE INDEX idx_courses_slug ON courses(slug);
-CREATE INDEX idx_course_sections_course_id ON course_sections(course_id);
-CREATE INDEX idx_course_lectures_section_id ON course_lectures(section_id);
+-- Migration: 001_create_core_tables.sql
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS "citext";
+
+-- Bảng người dùng
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email CITEXT UNIQUE NOT NULL,
+    username VARCHAR(50) UNIQUE NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    avatar_url TEXT,
+    bio TEXT,
+    role VARCHAR(20) NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'instructor', 'admin')),
+    is_verified BOOLEAN DEFAULT FALSE,
+    verification_token VARCHAR(255),
+    reset_password_token VARCHAR(255),
+    reset_password_expires TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Hồ sơ giảng viên
+CREATE TABLE instructor_profiles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID UNIQUE NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200),
+    expertise TEXT[],
+    experience_years INTEGER DEFAULT 0,
+    rating DECIMAL(3,2) DEFAULT 0.0,
+    total_students INTEGER DEFAULT 0,
+    total_courses INTEGER DEFAULT 0,
+    total_reviews INTEGER DEFAULT 0,
+    website_url TEXT,
+    linkedin_url TEXT,
+    github_url TEXT,
+    is_approved BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Danh mục khóa học
+CREATE TABLE categories (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) UNIQUE NOT NULL,
+    slug VARCHAR(100) UNIQUE NOT NULL,
+    description TEXT,
+    icon_url TEXT,
+    parent_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    sort_order INTEGER DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Khóa học
+CREATE TABLE courses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    slug VARCHAR(200) UNIQUE NOT NULL,
+    description TEXT,
+    short_description TEXT,
+    thumbnail_url TEXT,
+    preview_video_url TEXT,
+    instructor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+    price DECIMAL(10,2) NOT NULL DEFAULT 0.00,
+    discount_price DECIMAL(10,2),
+    language VARCHAR(10) NOT NULL DEFAULT 'vi',
+    level VARCHAR(20) NOT NULL DEFAULT 'beginner' CHECK (level IN ('beginner', 'intermediate', 'advanced')),
+    duration_hours INTEGER DEFAULT 0,
+    total_lectures INTEGER DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'pending', 'published', 'archived')),
+    requirements TEXT[],
+    what_you_learn TEXT[],
+    target_audience TEXT[],
+    rating DECIMAL(3,2) DEFAULT 0.0,
+    total_students INTEGER DEFAULT 0,
+    total_reviews INTEGER DEFAULT 0,
+    is_featured BOOLEAN DEFAULT FALSE,
+    published_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Chương của khóa học
+CREATE TABLE course_sections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    sort_order INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(course_id, sort_order)
+);
+
+-- Bài giảng
+CREATE TABLE course_lectures (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    section_id UUID NOT NULL REFERENCES course_sections(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    content_type VARCHAR(20) NOT NULL DEFAULT 'video' CHECK (content_type IN ('video', 'article', 'quiz', 'file')),
+    video_url TEXT,
+    video_duration INTEGER, -- in seconds
+    article_content TEXT,
+    file_url TEXT,
+    sort_order INTEGER NOT NULL,
+    is_preview BOOLEAN DEFAULT FALSE,
+    is_downloadable BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(section_id, sort_order)
+);
+
+CREATE INDEX idx_users_email ON users(email);
+CREATE INDEX idx_users_role ON users(role);
+CREATE INDEX idx_instructor_profiles_user_id ON instructor_profiles(user_id);
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX idx_categories_slug ON categories(slug);
+CREATE INDEX idx_courses_instructor_id ON courses(instructor_id);
+CREATE INDEX idx_courses_category_id ON courses(category_id);
+CREATE INDEX idx_courses_status ON courses(status);
+CREATE INDEX idx_courses_slug ON courses(slug);
+CREATE INDEX idx_course_sections_course_id ON course_sections(course_id);
+CREATE INDEX idx_course_lectures_section_id ON course_lectures(section_id);
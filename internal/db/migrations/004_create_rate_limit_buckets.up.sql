@@ -0,0 +1,12 @@
+-- Migration: 004_create_rate_limit_buckets.sql
+
+-- Token bucket của rate limiter dùng chung giữa các instance (rate_limit.backend = postgres).
+-- UNLOGGED: mất dữ liệu khi crash chỉ làm reset giới hạn, đổi lại ghi nhanh hơn.
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
@@ -0,0 +1,18 @@
+-- Migration: 005_create_idempotency_keys.sql
+
+-- Response đầu tiên của request có header Idempotency-Key, để trả lại khi client retry.
+-- scope là user_id của người gọi (rỗng với request ẩn danh); status_code NULL
+-- nghĩa là request đầu tiên đang được xử lý.
+CREATE TABLE idempotency_keys (
+    scope VARCHAR(64) NOT NULL DEFAULT '',
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
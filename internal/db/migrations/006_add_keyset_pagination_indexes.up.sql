@@ -0,0 +1,9 @@
+-- Migration: 006_add_keyset_pagination_indexes.sql
+
+-- Index cho phân trang cursor (?after=), sắp xếp mới nhất trước theo (thời gian, id)
+CREATE INDEX idx_notifications_created_at_id ON notifications(created_at DESC, id DESC);
+CREATE INDEX idx_notifications_user_created_at_id ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_lecture_progress_created_at_id ON lecture_progress(created_at DESC, id DESC);
+CREATE INDEX idx_lecture_progress_user_created_at_id ON lecture_progress(user_id, created_at DESC, id DESC);
+CREATE INDEX idx_enrollments_enrolled_at_id ON enrollments(enrolled_at DESC, id DESC);
+CREATE INDEX idx_enrollments_user_enrolled_at_id ON enrollments(user_id, enrolled_at DESC, id DESC);
@@ -0,0 +1,13 @@
+-- Migration: 007_deferrable_sort_order_constraints.sql
+
+-- Cho phép đổi thứ tự hàng loạt trong một transaction (SET CONSTRAINTS ... DEFERRED).
+-- Giữ nguyên tên constraint mặc định để mapping lỗi 409 không đổi.
+ALTER TABLE course_sections
+    DROP CONSTRAINT course_sections_course_id_sort_order_key,
+    ADD CONSTRAINT course_sections_course_id_sort_order_key
+        UNIQUE (course_id, sort_order) DEFERRABLE INITIALLY IMMEDIATE;
+
+ALTER TABLE course_lectures
+    DROP CONSTRAINT course_lectures_section_id_sort_order_key,
+    ADD CONSTRAINT course_lectures_section_id_sort_order_key
+        UNIQUE (section_id, sort_order) DEFERRABLE INITIALLY IMMEDIATE;
@@ -0,0 +1,7 @@
+-- Migration: 008_add_coupon_course.sql
+
+-- Coupon chỉ giảm cho một khóa học; NULL là giảm cho cả đơn như trước. Sao
+-- chép khóa học có thể sao chép kèm các coupon này
+ALTER TABLE coupons ADD COLUMN course_id UUID REFERENCES courses(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_coupons_course_id ON coupons(course_id) WHERE course_id IS NOT NULL;
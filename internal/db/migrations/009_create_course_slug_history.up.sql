@@ -0,0 +1,29 @@
+-- Migration: 009_create_course_slug_history.sql
+
+-- Slug cũ của khóa học, để URL cũ vẫn tìm được khóa học sau khi đổi slug
+CREATE TABLE course_slug_history (
+    slug VARCHAR(200) PRIMARY KEY,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_course_slug_history_course_id ON course_slug_history(course_id);
+
+-- Ghi lại slug cũ khi đổi slug. Slug đang dùng luôn được ưu tiên nên bản ghi
+-- lịch sử trùng với slug mới sẽ bị xóa.
+CREATE FUNCTION record_course_slug_change() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM course_slug_history WHERE slug = NEW.slug;
+    IF TG_OP = 'UPDATE' AND NEW.slug IS DISTINCT FROM OLD.slug THEN
+        INSERT INTO course_slug_history (slug, course_id)
+        VALUES (OLD.slug, NEW.id)
+        ON CONFLICT (slug) DO UPDATE
+            SET course_id = EXCLUDED.course_id, created_at = CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_courses_slug_history
+    AFTER INSERT OR UPDATE OF slug ON courses
+    FOR EACH ROW EXECUTE FUNCTION record_course_slug_change();
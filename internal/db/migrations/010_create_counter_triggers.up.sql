@@ -0,0 +1,163 @@
+-- Migration: 010_create_counter_triggers.sql
+
+-- Các cột đếm của courses và instructor_profiles được tính lại bằng trigger
+-- mỗi khi bảng nguồn thay đổi. expected_* là công thức duy nhất, dùng chung
+-- cho trigger và lệnh cmd/reconcile.
+
+-- Giá trị đúng của các cột đếm cho một khóa học
+CREATE FUNCTION expected_course_counters(p_course_id UUID)
+RETURNS TABLE (
+    total_students INTEGER,
+    total_reviews INTEGER,
+    rating DECIMAL(3,2),
+    total_lectures INTEGER,
+    duration_hours INTEGER
+) AS $$
+    SELECT
+        (SELECT COUNT(*) FROM enrollments WHERE course_id = p_course_id)::INTEGER,
+        (SELECT COUNT(*) FROM course_reviews WHERE course_id = p_course_id AND is_approved = TRUE)::INTEGER,
+        (SELECT COALESCE(ROUND(AVG(rating), 2), 0) FROM course_reviews
+          WHERE course_id = p_course_id AND is_approved = TRUE)::DECIMAL(3,2),
+        (SELECT COUNT(*) FROM course_lectures l JOIN course_sections s ON s.id = l.section_id
+          WHERE s.course_id = p_course_id)::INTEGER,
+        -- video_duration tính bằng giây, làm tròn lên theo giờ
+        (SELECT CEIL(COALESCE(SUM(l.video_duration), 0) / 3600.0) FROM course_lectures l
+          JOIN course_sections s ON s.id = l.section_id
+          WHERE s.course_id = p_course_id)::INTEGER
+$$ LANGUAGE sql STABLE;
+
+-- Giá trị đúng của các cột đếm cho hồ sơ giảng viên (theo user_id).
+-- total_courses chỉ tính khóa học đã publish.
+CREATE FUNCTION expected_instructor_counters(p_user_id UUID)
+RETURNS TABLE (
+    rating DECIMAL(3,2),
+    total_students INTEGER,
+    total_courses INTEGER,
+    total_reviews INTEGER
+) AS $$
+    SELECT
+        (SELECT COALESCE(ROUND(AVG(r.rating), 2), 0) FROM course_reviews r
+          JOIN courses c ON c.id = r.course_id
+          WHERE c.instructor_id = p_user_id AND r.is_approved = TRUE)::DECIMAL(3,2),
+        (SELECT COUNT(DISTINCT e.user_id) FROM enrollments e
+          JOIN courses c ON c.id = e.course_id
+          WHERE c.instructor_id = p_user_id)::INTEGER,
+        (SELECT COUNT(*) FROM courses WHERE instructor_id = p_user_id AND status = 'published')::INTEGER,
+        (SELECT COUNT(*) FROM course_reviews r
+          JOIN courses c ON c.id = r.course_id
+          WHERE c.instructor_id = p_user_id AND r.is_approved = TRUE)::INTEGER
+$$ LANGUAGE sql STABLE;
+
+-- Chỉ ghi khi giá trị thay đổi để tránh update thừa
+CREATE FUNCTION refresh_course_counters(p_course_id UUID) RETURNS VOID AS $$
+    UPDATE courses c
+    SET total_students = e.total_students,
+        total_reviews = e.total_reviews,
+        rating = e.rating,
+        total_lectures = e.total_lectures,
+        duration_hours = e.duration_hours
+    FROM expected_course_counters(p_course_id) e
+    WHERE c.id = p_course_id
+      AND (c.total_students, c.total_reviews, c.rating, c.total_lectures, c.duration_hours)
+          IS DISTINCT FROM (e.total_students, e.total_reviews, e.rating, e.total_lectures, e.duration_hours)
+$$ LANGUAGE sql;
+
+CREATE FUNCTION refresh_instructor_counters(p_user_id UUID) RETURNS VOID AS $$
+    UPDATE instructor_profiles p
+    SET rating = e.rating,
+        total_students = e.total_students,
+        total_courses = e.total_courses,
+        total_reviews = e.total_reviews
+    FROM expected_instructor_counters(p_user_id) e
+    WHERE p.user_id = p_user_id
+      AND (p.rating, p.total_students, p.total_courses, p.total_reviews)
+          IS DISTINCT FROM (e.rating, e.total_students, e.total_courses, e.total_reviews)
+$$ LANGUAGE sql;
+
+-- enrollments, course_reviews: ảnh hưởng khóa học và giảng viên của khóa học đó
+CREATE FUNCTION sync_counters_by_course() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_course_counters(OLD.course_id);
+        PERFORM refresh_instructor_counters((SELECT instructor_id FROM courses WHERE id = OLD.course_id));
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.course_id IS DISTINCT FROM OLD.course_id) THEN
+        PERFORM refresh_course_counters(NEW.course_id);
+        PERFORM refresh_instructor_counters((SELECT instructor_id FROM courses WHERE id = NEW.course_id));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_enrollments_counters
+    AFTER INSERT OR DELETE OR UPDATE OF course_id, user_id ON enrollments
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_by_course();
+
+CREATE TRIGGER trg_course_reviews_counters
+    AFTER INSERT OR DELETE OR UPDATE OF course_id, rating, is_approved ON course_reviews
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_by_course();
+
+-- course_lectures: số bài giảng và thời lượng của khóa học chứa section
+CREATE FUNCTION sync_counters_by_section() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_course_counters((SELECT course_id FROM course_sections WHERE id = OLD.section_id));
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.section_id IS DISTINCT FROM OLD.section_id) THEN
+        PERFORM refresh_course_counters((SELECT course_id FROM course_sections WHERE id = NEW.section_id));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_course_lectures_counters
+    AFTER INSERT OR DELETE OR UPDATE OF section_id, video_duration ON course_lectures
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_by_section();
+
+-- course_sections: xóa/chuyển section kéo theo lectures của nó
+CREATE FUNCTION sync_counters_by_section_course() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_course_counters(OLD.course_id);
+    IF TG_OP = 'UPDATE' THEN
+        PERFORM refresh_course_counters(NEW.course_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_course_sections_counters
+    AFTER DELETE OR UPDATE OF course_id ON course_sections
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_by_section_course();
+
+-- courses: đổi giảng viên, trạng thái publish hoặc xóa khóa học
+CREATE FUNCTION sync_counters_by_instructor() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM refresh_instructor_counters(OLD.instructor_id);
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.instructor_id IS DISTINCT FROM OLD.instructor_id) THEN
+        PERFORM refresh_instructor_counters(NEW.instructor_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_courses_counters
+    AFTER INSERT OR DELETE OR UPDATE OF instructor_id, status ON courses
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_by_instructor();
+
+-- Một hồ sơ giảng viên mới nhận ngay số liệu hiện có
+CREATE FUNCTION sync_counters_new_instructor() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_instructor_counters(NEW.user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_instructor_profiles_counters
+    AFTER INSERT ON instructor_profiles
+    FOR EACH ROW EXECUTE FUNCTION sync_counters_new_instructor();
+
+-- Đồng bộ dữ liệu hiện có
+SELECT refresh_course_counters(id) FROM courses;
+SELECT refresh_instructor_counters(user_id) FROM instructor_profiles;
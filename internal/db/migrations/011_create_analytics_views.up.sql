@@ -0,0 +1,100 @@
+-- Migration: 011_create_analytics_views.sql
+
+-- Số liệu thống kê cho giảng viên, gom theo ngày (giờ Việt Nam) và được
+-- refresh định kỳ bởi API (analytics.refresh_interval). API gom tiếp theo
+-- tuần/tháng khi truy vấn. Mỗi view có unique index để REFRESH CONCURRENTLY
+-- không khóa việc đọc.
+
+-- Thời điểm đơn được thanh toán (chuyển sang completed). Thống kê doanh thu
+-- tính theo ngày thanh toán chứ không theo ngày tạo đơn, vì đơn có thể chờ
+-- cổng thanh toán nhiều ngày
+ALTER TABLE orders ADD COLUMN paid_at TIMESTAMP WITH TIME ZONE;
+
+CREATE FUNCTION set_order_paid_at() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.payment_status = 'completed' AND NEW.paid_at IS NULL THEN
+        NEW.paid_at := CURRENT_TIMESTAMP;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_orders_paid_at
+    BEFORE INSERT OR UPDATE OF payment_status ON orders
+    FOR EACH ROW EXECUTE FUNCTION set_order_paid_at();
+
+-- Đơn cũ: lần cập nhật cuối là lần đổi trạng thái gần nhất, sát ngày thanh
+-- toán hơn ngày tạo đơn
+UPDATE orders SET paid_at = COALESCE(updated_at, created_at)
+WHERE payment_status IN ('completed', 'refunded');
+
+-- Đăng ký mới theo khóa học và ngày
+CREATE MATERIALIZED VIEW mv_course_daily_enrollments AS
+SELECT course_id,
+       (enrolled_at AT TIME ZONE 'Asia/Ho_Chi_Minh')::date AS day,
+       COUNT(*)::INTEGER AS enrollments
+FROM enrollments
+GROUP BY course_id, day;
+
+CREATE UNIQUE INDEX idx_mv_course_daily_enrollments ON mv_course_daily_enrollments(course_id, day);
+
+-- Doanh thu theo khóa học, tiền tệ và ngày thanh toán, chỉ tính đơn đã thanh
+-- toán; không cộng lẫn các tiền tệ với nhau
+CREATE MATERIALIZED VIEW mv_course_daily_revenue AS
+SELECT oi.course_id,
+       COALESCE(o.currency, 'VND') AS currency,
+       (o.paid_at AT TIME ZONE 'Asia/Ho_Chi_Minh')::date AS day,
+       COUNT(*)::INTEGER AS sales,
+       SUM(oi.final_price)::DECIMAL(14,2) AS revenue
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+WHERE o.payment_status = 'completed'
+GROUP BY oi.course_id, COALESCE(o.currency, 'VND'), day;
+
+CREATE UNIQUE INDEX idx_mv_course_daily_revenue ON mv_course_daily_revenue(course_id, currency, day);
+
+-- Đánh giá đã duyệt theo khóa học và ngày; lưu tổng điểm để tính trung bình
+-- đúng khi gom nhiều ngày
+CREATE MATERIALIZED VIEW mv_course_daily_ratings AS
+SELECT course_id,
+       (created_at AT TIME ZONE 'Asia/Ho_Chi_Minh')::date AS day,
+       COUNT(*)::INTEGER AS reviews,
+       SUM(rating)::INTEGER AS rating_sum
+FROM course_reviews
+WHERE is_approved = TRUE
+GROUP BY course_id, day;
+
+CREATE UNIQUE INDEX idx_mv_course_daily_ratings ON mv_course_daily_ratings(course_id, day);
+
+-- Tiến độ theo bài giảng và ngày học viên bắt đầu bài đó
+CREATE MATERIALIZED VIEW mv_lecture_daily_progress AS
+SELECT s.course_id,
+       l.section_id,
+       lp.lecture_id,
+       (lp.created_at AT TIME ZONE 'Asia/Ho_Chi_Minh')::date AS day,
+       COUNT(*)::INTEGER AS started,
+       COUNT(*) FILTER (WHERE lp.is_completed)::INTEGER AS completed,
+       COALESCE(SUM(lp.watch_time), 0)::BIGINT AS watch_time
+FROM lecture_progress lp
+JOIN course_lectures l ON l.id = lp.lecture_id
+JOIN course_sections s ON s.id = l.section_id
+GROUP BY s.course_id, l.section_id, lp.lecture_id, day;
+
+CREATE UNIQUE INDEX idx_mv_lecture_daily_progress ON mv_lecture_daily_progress(lecture_id, day);
+CREATE INDEX idx_mv_lecture_daily_progress_course ON mv_lecture_daily_progress(course_id, day);
+
+-- Tiến độ của từng học viên trong từng section, để tính funnel hoàn thành.
+-- started_on là ngày học viên bắt đầu bài đầu tiên của section.
+CREATE MATERIALIZED VIEW mv_section_learner_progress AS
+SELECT s.course_id,
+       l.section_id,
+       lp.user_id,
+       (MIN(lp.created_at) AT TIME ZONE 'Asia/Ho_Chi_Minh')::date AS started_on,
+       COUNT(*) FILTER (WHERE lp.is_completed)::INTEGER AS completed_lectures
+FROM lecture_progress lp
+JOIN course_lectures l ON l.id = lp.lecture_id
+JOIN course_sections s ON s.id = l.section_id
+GROUP BY s.course_id, l.section_id, lp.user_id;
+
+CREATE UNIQUE INDEX idx_mv_section_learner_progress ON mv_section_learner_progress(section_id, user_id);
+CREATE INDEX idx_mv_section_learner_progress_course ON mv_section_learner_progress(course_id, started_on);
@@ -0,0 +1,12 @@
+-- Migration: 012_add_order_coupon_and_report_indexes.sql
+
+-- Mã giảm giá đã áp dụng cho đơn hàng, để đo hiệu quả của từng coupon.
+-- Đơn hàng cũ không có thông tin này.
+ALTER TABLE orders ADD COLUMN coupon_id UUID REFERENCES coupons(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_orders_coupon_id ON orders(coupon_id);
+
+-- Báo cáo cho admin lọc theo khoảng thời gian
+CREATE INDEX idx_orders_created_at ON orders(created_at);
+CREATE INDEX idx_users_created_at ON users(created_at);
+CREATE INDEX idx_order_items_course_id ON order_items(course_id);
@@ -0,0 +1,214 @@
+-- Migration: 013_create_revenue_ledger.sql
+
+-- Bên chịu phần giảm giá của coupon, dùng để chọn tỉ lệ chia doanh thu
+ALTER TABLE coupons ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'platform'
+    CHECK (source IN ('platform', 'instructor'));
+
+-- Tỉ lệ doanh thu giảng viên nhận được. instructor_id / coupon_source NULL là
+-- áp dụng cho mọi giá trị; quy tắc cụ thể hơn được ưu tiên (giảng viên trước,
+-- rồi đến nguồn coupon). coupon_source 'none' là đơn không dùng coupon.
+CREATE TABLE revenue_share_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    instructor_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    coupon_source VARCHAR(20) CHECK (coupon_source IN ('none', 'platform', 'instructor')),
+    instructor_share DECIMAL(5,4) NOT NULL CHECK (instructor_share >= 0 AND instructor_share <= 1),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX revenue_share_rules_scope_key ON revenue_share_rules (
+    COALESCE(instructor_id, '00000000-0000-0000-0000-000000000000'::uuid),
+    COALESCE(coupon_source, '*')
+);
+
+-- Mặc định: giảng viên nhận 70%
+INSERT INTO revenue_share_rules (instructor_id, coupon_source, instructor_share) VALUES (NULL, NULL, 0.7);
+
+-- Đợt chi trả cho giảng viên
+CREATE TABLE payout_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'paid', 'failed', 'cancelled')),
+    -- Chỉ doanh thu phát sinh trước thời điểm này mới được chi trả
+    eligible_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE payouts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    batch_id UUID NOT NULL REFERENCES payout_batches(id) ON DELETE RESTRICT,
+    instructor_id UUID NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'paid', 'failed', 'cancelled')),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(batch_id, instructor_id, currency)
+);
+
+CREATE INDEX idx_payouts_instructor_id ON payouts(instructor_id);
+
+-- Sổ cái kép: mỗi giao dịch gồm các bút toán có tổng amount = 0
+-- (nợ dương, có âm). Tài khoản:
+--   cash                tiền nền tảng thu/chi
+--   platform_revenue    doanh thu của nền tảng
+--   instructor_payable  số tiền nợ giảng viên (instructor_id bắt buộc)
+CREATE TABLE ledger_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('sale', 'refund', 'payout', 'payout_reversal')),
+    order_item_id UUID REFERENCES order_items(id) ON DELETE SET NULL,
+    payout_id UUID REFERENCES payouts(id) ON DELETE RESTRICT,
+    instructor_share DECIMAL(5,4),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Mỗi order item chỉ được ghi nhận bán/hoàn tiền một lần
+CREATE UNIQUE INDEX ledger_transactions_order_item_kind_key
+    ON ledger_transactions(order_item_id, kind) WHERE order_item_id IS NOT NULL;
+CREATE UNIQUE INDEX ledger_transactions_payout_kind_key
+    ON ledger_transactions(payout_id, kind) WHERE payout_id IS NOT NULL;
+
+CREATE TABLE ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(id) ON DELETE RESTRICT,
+    account VARCHAR(30) NOT NULL CHECK (account IN ('cash', 'platform_revenue', 'instructor_payable')),
+    instructor_id UUID REFERENCES users(id) ON DELETE RESTRICT,
+    amount DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((account = 'instructor_payable') = (instructor_id IS NOT NULL))
+);
+
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_instructor ON ledger_entries(instructor_id, created_at)
+    WHERE account = 'instructor_payable';
+
+-- Kiểm tra cân bằng khi commit (constraint trigger hoãn đến cuối transaction)
+CREATE FUNCTION check_ledger_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM ledger_entries
+        WHERE transaction_id = NEW.transaction_id
+        GROUP BY currency
+        HAVING SUM(amount) <> 0
+    ) THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.transaction_id
+            USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_ledger_entries_balanced
+    AFTER INSERT OR UPDATE ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_ledger_balanced();
+
+-- Sổ cái chỉ được ghi thêm
+CREATE FUNCTION reject_ledger_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are append-only' USING ERRCODE = 'check_violation';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_ledger_entries_append_only
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION reject_ledger_change();
+
+-- Tỉ lệ áp dụng cho một giảng viên và nguồn coupon
+CREATE FUNCTION revenue_share_for(p_instructor_id UUID, p_coupon_source VARCHAR) RETURNS DECIMAL AS $$
+    SELECT instructor_share FROM revenue_share_rules
+    WHERE (instructor_id = p_instructor_id OR instructor_id IS NULL)
+      AND (coupon_source = p_coupon_source OR coupon_source IS NULL)
+    ORDER BY instructor_id IS NULL, coupon_source IS NULL
+    LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+-- Làm tròn theo đơn vị nhỏ nhất của tiền tệ (VND không có xu)
+CREATE FUNCTION round_money(p_amount DECIMAL, p_currency VARCHAR) RETURNS DECIMAL AS $$
+    SELECT ROUND(p_amount, CASE WHEN p_currency = 'VND' THEN 0 ELSE 2 END)
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Ghi nhận doanh thu cho từng order item chưa được ghi nhận của một đơn hàng:
+-- nợ cash toàn bộ giá bán, có instructor_payable phần của giảng viên và
+-- platform_revenue phần còn lại
+CREATE FUNCTION post_order_sale(p_order_id UUID) RETURNS VOID AS $$
+DECLARE
+    item RECORD;
+    tx_id UUID;
+    share DECIMAL(5,4);
+    earning DECIMAL(12,2);
+BEGIN
+    FOR item IN
+        SELECT oi.id, oi.final_price, c.instructor_id,
+               COALESCE(o.currency, 'VND') AS currency,
+               COALESCE(cp.source, 'none') AS coupon_source
+        FROM order_items oi
+        JOIN orders o ON o.id = oi.order_id
+        JOIN courses c ON c.id = oi.course_id
+        LEFT JOIN coupons cp ON cp.id = o.coupon_id
+        WHERE oi.order_id = p_order_id
+          AND NOT EXISTS (SELECT 1 FROM ledger_transactions t WHERE t.order_item_id = oi.id AND t.kind = 'sale')
+    LOOP
+        share := COALESCE(revenue_share_for(item.instructor_id, item.coupon_source), 0);
+        earning := round_money(item.final_price * share, item.currency);
+
+        INSERT INTO ledger_transactions (kind, order_item_id, instructor_share)
+        VALUES ('sale', item.id, share) RETURNING id INTO tx_id;
+
+        INSERT INTO ledger_entries (transaction_id, account, instructor_id, amount, currency) VALUES
+            (tx_id, 'cash', NULL, item.final_price, item.currency),
+            (tx_id, 'platform_revenue', NULL, earning - item.final_price, item.currency),
+            (tx_id, 'instructor_payable', item.instructor_id, -earning, item.currency);
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Đảo các bút toán bán hàng chưa được hoàn tiền của một đơn hàng
+CREATE FUNCTION post_order_refund(p_order_id UUID) RETURNS VOID AS $$
+DECLARE
+    sale RECORD;
+    tx_id UUID;
+BEGIN
+    FOR sale IN
+        SELECT t.id, t.order_item_id, t.instructor_share
+        FROM ledger_transactions t
+        JOIN order_items oi ON oi.id = t.order_item_id
+        WHERE oi.order_id = p_order_id AND t.kind = 'sale'
+          AND NOT EXISTS (SELECT 1 FROM ledger_transactions r
+                          WHERE r.order_item_id = t.order_item_id AND r.kind = 'refund')
+    LOOP
+        INSERT INTO ledger_transactions (kind, order_item_id, instructor_share)
+        VALUES ('refund', sale.order_item_id, sale.instructor_share) RETURNING id INTO tx_id;
+
+        INSERT INTO ledger_entries (transaction_id, account, instructor_id, amount, currency)
+        SELECT tx_id, account, instructor_id, -amount, currency
+        FROM ledger_entries WHERE transaction_id = sale.id;
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION post_order_ledger() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.payment_status = 'completed' THEN
+        PERFORM post_order_sale(NEW.id);
+    ELSIF NEW.payment_status = 'refunded' THEN
+        PERFORM post_order_refund(NEW.id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_orders_ledger
+    AFTER INSERT OR UPDATE OF payment_status ON orders
+    FOR EACH ROW
+    WHEN (NEW.payment_status IN ('completed', 'refunded'))
+    EXECUTE FUNCTION post_order_ledger();
+
+-- Ghi nhận các đơn đã hoàn tất / hoàn tiền trước migration này
+SELECT post_order_sale(id) FROM orders WHERE payment_status IN ('completed', 'refunded');
+SELECT post_order_refund(id) FROM orders WHERE payment_status = 'refunded';
@@ -0,0 +1,60 @@
+-- Migration: 014_create_invoices.sql
+
+-- Bộ đếm số hóa đơn theo ký hiệu (mỗi năm một ký hiệu). Số được cấp bằng
+-- UPDATE trong cùng transaction với INSERT hóa đơn: khóa dòng tuần tự hóa các
+-- lần cấp số và rollback trả lại số, nên dãy số không bị trùng hay nhảy cóc.
+CREATE TABLE invoice_sequences (
+    series VARCHAR(20) PRIMARY KEY,
+    last_number BIGINT NOT NULL DEFAULT 0
+);
+
+-- Hóa đơn cho đơn hàng đã thanh toán. Thông tin người mua, dòng hàng và số
+-- tiền được chụp lại lúc xuất để hóa đơn không đổi khi dữ liệu gốc thay đổi.
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID UNIQUE NOT NULL REFERENCES orders(id) ON DELETE RESTRICT,
+    series VARCHAR(20) NOT NULL,
+    number BIGINT NOT NULL,
+    invoice_no VARCHAR(40) UNIQUE NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    -- Người mua; công ty cần tên, mã số thuế và địa chỉ
+    buyer_name VARCHAR(255) NOT NULL,
+    buyer_email VARCHAR(255) NOT NULL,
+    buyer_company VARCHAR(255),
+    buyer_tax_code VARCHAR(20),
+    buyer_address TEXT,
+
+    -- Giá bán đã gồm VAT; subtotal là tổng giá gốc, discount gồm giảm giá
+    -- khóa học và coupon, total = subtotal - discount = net_amount + vat_amount
+    currency VARCHAR(3) NOT NULL,
+    coupon_code VARCHAR(50),
+    subtotal DECIMAL(12,2) NOT NULL,
+    discount DECIMAL(12,2) NOT NULL,
+    vat_rate DECIMAL(5,4) NOT NULL,
+    net_amount DECIMAL(12,2) NOT NULL,
+    vat_amount DECIMAL(12,2) NOT NULL,
+    total DECIMAL(12,2) NOT NULL,
+
+    -- Khóa của file đã render trong storage; NULL khi chưa lưu được
+    html_key TEXT,
+    pdf_key TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(series, number)
+);
+
+CREATE TABLE invoice_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    line_no INTEGER NOT NULL,
+    course_id UUID REFERENCES courses(id) ON DELETE SET NULL,
+    description VARCHAR(255) NOT NULL,
+    unit_price DECIMAL(12,2) NOT NULL,
+    discount DECIMAL(12,2) NOT NULL,
+    net_amount DECIMAL(12,2) NOT NULL,
+    vat_amount DECIMAL(12,2) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    UNIQUE(invoice_id, line_no)
+);
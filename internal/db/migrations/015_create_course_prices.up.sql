@@ -0,0 +1,52 @@
+-- Migration: 015_create_course_prices.sql
+
+-- Số chữ số của đơn vị nhỏ nhất theo tiền tệ; phải khớp với bảng currencies
+-- trong internal/money. API trao đổi số tiền bằng số nguyên đơn vị nhỏ nhất
+-- (đồng, cent), cột DECIMAL vẫn lưu theo đơn vị chính.
+CREATE FUNCTION currency_exponent(p_currency VARCHAR) RETURNS INTEGER AS $$
+    SELECT CASE p_currency WHEN 'VND' THEN 0 ELSE 2 END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION round_money(p_amount DECIMAL, p_currency VARCHAR) RETURNS DECIMAL AS $$
+    SELECT ROUND(p_amount, currency_exponent(p_currency))
+$$ LANGUAGE sql IMMUTABLE;
+
+-- DECIMAL (đơn vị chính) -> BIGINT (đơn vị nhỏ nhất), làm tròn nửa lên
+CREATE FUNCTION minor_units(p_amount DECIMAL, p_currency VARCHAR) RETURNS BIGINT AS $$
+    SELECT ROUND(p_amount * power(10::numeric, currency_exponent(p_currency)))::bigint
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+CREATE FUNCTION from_minor_units(p_amount BIGINT, p_currency VARCHAR) RETURNS DECIMAL AS $$
+    SELECT p_amount::numeric / power(10::numeric, currency_exponent(p_currency))
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+-- Tiền tệ của giá gốc khóa học. Giá cũ đều là VND
+ALTER TABLE courses ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'VND'
+    CHECK (currency IN ('VND', 'USD', 'EUR'));
+
+-- Giá không được lẻ hơn đơn vị nhỏ nhất; NOT VALID để không chặn dữ liệu cũ
+ALTER TABLE courses ADD CONSTRAINT courses_price_precision
+    CHECK (price = round_money(price, currency) AND discount_price = round_money(discount_price, currency)) NOT VALID;
+
+-- Bảng giá theo tiền tệ: giá của khóa học ở các tiền tệ khác tiền tệ gốc.
+-- Storefront hiển thị giá ở tiền tệ được yêu cầu nếu có, ngược lại giá gốc
+CREATE TABLE course_prices (
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL CHECK (currency IN ('VND', 'USD', 'EUR')),
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    discount_price DECIMAL(10,2) CHECK (discount_price >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, currency),
+    CONSTRAINT course_prices_precision
+        CHECK (price = round_money(price, currency) AND discount_price = round_money(discount_price, currency))
+);
+
+-- Số tiền của coupon giảm cố định và giá trị đơn tối thiểu tính theo tiền tệ
+-- này; coupon phần trăm áp dụng cho mọi tiền tệ nếu không có đơn tối thiểu
+ALTER TABLE coupons ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'VND'
+    CHECK (currency IN ('VND', 'USD', 'EUR'));
+
+-- API gửi phần trăm bằng basis point; không cho vượt 100%
+ALTER TABLE coupons ADD CONSTRAINT coupons_discount_value_check
+    CHECK (discount_type <> 'percentage' OR discount_value <= 100) NOT VALID;
@@ -0,0 +1,103 @@
+-- Migration: 016_create_promotions.sql
+
+-- Chương trình khuyến mãi theo thời gian: giảm phần trăm trên giá niêm yết
+-- hoặc bán đồng giá (fixed_price, theo currency) cho các khóa học được chọn
+-- trong khoảng [starts_at, ends_at). discount_value lưu phần trăm hoặc số tiền
+-- theo đơn vị chính như coupons; API gửi basis point / đơn vị nhỏ nhất.
+-- coupon_policy quyết định coupon có dùng chung với khuyến mãi không:
+--   stack     - coupon giảm tiếp trên giá khuyến mãi
+--   exclusive - khóa học đang khuyến mãi không nhận coupon
+--   best_of   - khách được giá tốt hơn giữa khuyến mãi và coupon
+CREATE TABLE promotions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    discount_type VARCHAR(20) NOT NULL CHECK (discount_type IN ('percentage', 'fixed_price')),
+    discount_value DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) CHECK (currency IN ('VND', 'USD', 'EUR')),
+    priority INTEGER NOT NULL DEFAULT 0,
+    coupon_policy VARCHAR(20) NOT NULL DEFAULT 'stack' CHECK (coupon_policy IN ('stack', 'exclusive', 'best_of')),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT promotions_discount_value_check CHECK (
+        discount_value >= 0
+        AND (discount_type <> 'percentage' OR (discount_value > 0 AND discount_value <= 100))
+        AND (discount_type <> 'fixed_price' OR discount_value = round_money(discount_value, currency))
+    ),
+    -- Giá đồng giá phải nói rõ tiền tệ; khuyến mãi phần trăm áp dụng mọi tiền tệ
+    CONSTRAINT promotions_currency_check CHECK ((discount_type = 'fixed_price') = (currency IS NOT NULL)),
+    CONSTRAINT promotions_ends_at_check CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_promotions_window ON promotions(starts_at, ends_at) WHERE is_active;
+
+-- Phạm vi khuyến mãi: khóa học, danh mục (gồm cả danh mục con) hoặc mọi khóa
+-- học của một giảng viên
+CREATE TABLE promotion_targets (
+    promotion_id UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('course', 'category', 'instructor')),
+    target_id UUID NOT NULL,
+    PRIMARY KEY (promotion_id, target_type, target_id)
+);
+
+CREATE INDEX idx_promotion_targets_target ON promotion_targets(target_type, target_id);
+
+-- Giá bán của khóa học tại thời điểm p_at ở p_currency (giá gốc nếu khóa học
+-- không có giá ở tiền tệ đó hoặc p_currency NULL).
+--   list_price    - giá niêm yết
+--   regular_price - giá không khuyến mãi: discount_price nếu có, ngược lại list_price
+--   price         - giá bán sau khuyến mãi
+-- Chỉ các khuyến mãi làm giá thấp hơn regular_price được xét; trong số đó
+-- priority cao nhất thắng, bằng nhau thì giá thấp hơn, rồi khuyến mãi bắt đầu
+-- trước. internal/pricing đọc giá qua hàm này.
+CREATE FUNCTION course_effective_price(p_course_id UUID, p_currency VARCHAR, p_at TIMESTAMPTZ)
+RETURNS TABLE (currency VARCHAR, list_price DECIMAL, regular_price DECIMAL, price DECIMAL, promotion_id UUID) AS $$
+    WITH RECURSIVE base AS (
+        SELECT co.id, co.category_id, co.instructor_id,
+               COALESCE(cp.currency, co.currency) AS currency,
+               COALESCE(cp.price, co.price) AS list_price,
+               LEAST(COALESCE(cp.price, co.price),
+                     CASE WHEN cp.course_id IS NULL THEN co.discount_price ELSE cp.discount_price END) AS regular_price
+        FROM courses co
+        LEFT JOIN course_prices cp ON cp.course_id = co.id AND cp.currency = p_currency AND cp.currency <> co.currency
+        WHERE co.id = p_course_id
+    ),
+    categories_up AS (
+        SELECT cat.id, cat.parent_id, 1 AS depth
+        FROM categories cat JOIN base b ON cat.id = b.category_id
+        UNION ALL
+        SELECT cat.id, cat.parent_id, up.depth + 1
+        FROM categories cat JOIN categories_up up ON cat.id = up.parent_id
+        WHERE up.depth < 10
+    ),
+    candidates AS (
+        SELECT pr.id, pr.priority, pr.starts_at,
+               CASE pr.discount_type
+                   WHEN 'percentage' THEN round_money(b.list_price * (100 - pr.discount_value) / 100, b.currency)
+                   ELSE pr.discount_value
+               END AS price
+        FROM base b
+        JOIN promotions pr ON pr.is_active AND pr.starts_at <= p_at AND pr.ends_at > p_at
+        WHERE (pr.discount_type = 'percentage' OR pr.currency = b.currency)
+          AND EXISTS (
+              SELECT 1 FROM promotion_targets t
+              WHERE t.promotion_id = pr.id
+                AND ((t.target_type = 'course' AND t.target_id = b.id)
+                  OR (t.target_type = 'instructor' AND t.target_id = b.instructor_id)
+                  OR (t.target_type = 'category' AND t.target_id IN (SELECT up.id FROM categories_up up)))
+          )
+    ),
+    best AS (
+        SELECT c.id, c.price
+        FROM candidates c, base b
+        WHERE c.price < b.regular_price
+        ORDER BY c.priority DESC, c.price, c.starts_at, c.id
+        LIMIT 1
+    )
+    SELECT b.currency, b.list_price, b.regular_price, COALESCE(best.price, b.regular_price), best.id
+    FROM base b LEFT JOIN best ON TRUE
+$$ LANGUAGE sql STABLE;
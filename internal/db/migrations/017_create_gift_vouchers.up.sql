@@ -0,0 +1,47 @@
+-- Migration: 017_create_gift_vouchers.sql
+
+-- Đơn quà tặng: mỗi suất là một order_item và một voucher, nên sổ cái, hóa
+-- đơn và thống kê xử lý như đơn thường
+ALTER TABLE orders ADD COLUMN is_gift BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE orders ADD COLUMN gift_message TEXT;
+
+-- Voucher khóa học (khác coupons giảm giá): ai đổi mã thì được ghi danh vào
+-- khóa học. Chỉ đổi được khi đơn đã thanh toán, chưa hết hạn, và nếu có
+-- recipient_email thì phải đúng email của người đổi
+CREATE TABLE gift_vouchers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(32) UNIQUE NOT NULL,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    order_item_id UUID REFERENCES order_items(id) ON DELETE SET NULL,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    purchaser_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_email VARCHAR(255),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    redeemed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    redeemed_at TIMESTAMP WITH TIME ZONE,
+    enrollment_id UUID REFERENCES enrollments(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT gift_vouchers_redeemed_by_check CHECK (redeemed_by IS NULL OR redeemed_at IS NOT NULL)
+);
+
+CREATE INDEX idx_gift_vouchers_order_id ON gift_vouchers(order_id);
+CREATE INDEX idx_gift_vouchers_purchaser_id ON gift_vouchers(purchaser_id);
+CREATE INDEX idx_gift_vouchers_recipient_email ON gift_vouchers(LOWER(recipient_email)) WHERE redeemed_at IS NULL;
+CREATE INDEX idx_orders_user_id_gift ON orders(user_id, created_at DESC) WHERE is_gift;
+
+-- Kết quả thanh toán do admin ghi nhận (PUT /admin/orders/:id/status). Chưa
+-- có callback đã xác thực từ cổng thanh toán nên mỗi lần ghi nhận giữ lại
+-- người thực hiện, mã giao dịch và lý do để đối soát
+CREATE TABLE order_settlements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('completed', 'failed')),
+    transaction_id VARCHAR(255),
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    settled_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT order_settlements_transaction_check CHECK (status <> 'completed' OR transaction_id IS NOT NULL)
+);
+
+CREATE INDEX idx_order_settlements_order_id ON order_settlements(order_id);
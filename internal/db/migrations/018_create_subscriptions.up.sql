@@ -0,0 +1,156 @@
+-- Migration: 018_create_subscriptions.sql
+
+-- Gói thuê bao: trả tiền theo kỳ (billing_interval x interval_count) để học
+-- các khóa học trong phạm vi của gói trong khi thuê bao còn hiệu lực. Khác
+-- với enrollment mua đứt, quyền học hết khi thuê bao hết hạn.
+--   scope all        - mọi khóa học đã xuất bản
+--   scope categories - khóa học thuộc các danh mục trong subscription_plan_items (gồm danh mục con)
+--   scope courses    - danh sách khóa học chọn tay trong subscription_plan_items
+-- price lưu theo đơn vị chính như orders; API gửi đơn vị nhỏ nhất
+CREATE TABLE subscription_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    billing_interval VARCHAR(10) NOT NULL CHECK (billing_interval IN ('month', 'year')),
+    interval_count INTEGER NOT NULL DEFAULT 1,
+    price DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'VND' CHECK (currency IN ('VND', 'USD', 'EUR')),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('all', 'categories', 'courses')),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT subscription_plans_interval_count_check CHECK (interval_count BETWEEN 1 AND 12),
+    CONSTRAINT subscription_plans_price_check CHECK (price > 0 AND price = round_money(price, currency))
+);
+
+CREATE TABLE subscription_plan_items (
+    plan_id UUID NOT NULL REFERENCES subscription_plans(id) ON DELETE CASCADE,
+    item_type VARCHAR(20) NOT NULL CHECK (item_type IN ('category', 'course')),
+    item_id UUID NOT NULL,
+    PRIMARY KEY (plan_id, item_type, item_id)
+);
+
+CREATE INDEX idx_subscription_plan_items_item ON subscription_plan_items(item_type, item_id);
+
+-- Thuê bao của user. Vòng đời:
+--   incomplete - mới đăng ký, chờ thanh toán kỳ đầu
+--   active     - đã thanh toán kỳ hiện tại
+--   past_due   - kỳ đã hết mà chưa thanh toán kỳ tiếp, vẫn được học đến grace_until
+--   cancelled  - user hủy, quyền học hết ở cuối kỳ đã trả tiền
+--   expired    - hết thời gian gia hạn mà không thanh toán, hoặc kỳ đầu không được thanh toán
+-- Quyền học kéo dài đến COALESCE(grace_until, current_period_end); job thuê bao
+-- (internal/subscription) đặt grace_until khi tạo khoản gia hạn, nên quyền
+-- học không phụ thuộc vào việc job chạy đúng giờ
+CREATE TABLE subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    plan_id UUID NOT NULL REFERENCES subscription_plans(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'incomplete'
+        CHECK (status IN ('incomplete', 'active', 'past_due', 'cancelled', 'expired')),
+    current_period_start TIMESTAMP WITH TIME ZONE,
+    current_period_end TIMESTAMP WITH TIME ZONE,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    grace_until TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Thuê bao chưa từng được thanh toán thì không có kỳ
+    CONSTRAINT subscriptions_period_check CHECK (
+        (current_period_start IS NULL) = (current_period_end IS NULL)
+        AND current_period_end > current_period_start
+        AND (status <> 'incomplete' OR current_period_end IS NULL)
+        AND (status NOT IN ('active', 'past_due') OR current_period_end IS NOT NULL)
+    )
+);
+
+-- Mỗi user chỉ có một thuê bao còn mở cho mỗi gói
+CREATE UNIQUE INDEX uq_subscriptions_user_plan_open ON subscriptions(user_id, plan_id)
+    WHERE status IN ('incomplete', 'active', 'past_due');
+CREATE INDEX idx_subscriptions_user_id ON subscriptions(user_id, created_at DESC);
+CREATE INDEX idx_subscriptions_due ON subscriptions(current_period_end)
+    WHERE status IN ('active', 'past_due');
+
+-- Khoản thanh toán cho từng kỳ. Cổng thanh toán báo kết quả qua
+-- PUT /api/v1/admin/subscription-payments/:id/status; khoản paid đẩy kỳ của
+-- thuê bao sang [period_start, period_end). void là khoản bị bỏ vì thuê bao
+-- đã hủy hoặc hết hạn
+CREATE TABLE subscription_payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'failed', 'void')),
+    transaction_id VARCHAR(255),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT subscription_payments_period_check CHECK (period_end > period_start)
+);
+
+-- Một khoản cho mỗi kỳ; khoản void không chặn việc lập lại khoản khi user
+-- tiếp tục thuê bao đã hủy
+CREATE UNIQUE INDEX uq_subscription_payments_period ON subscription_payments(subscription_id, period_start)
+    WHERE status <> 'void';
+CREATE INDEX idx_subscription_payments_pending ON subscription_payments(created_at) WHERE status = 'pending';
+
+-- Cuối kỳ bắt đầu từ p_start của gói thuê bao
+CREATE FUNCTION subscription_period_end(p_start TIMESTAMPTZ, p_interval VARCHAR, p_count INTEGER)
+RETURNS TIMESTAMPTZ AS $$
+    SELECT p_start + make_interval(
+        months => CASE p_interval WHEN 'year' THEN 12 * p_count ELSE p_count END)
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+-- Gói p_plan_id có gồm khóa học p_course_id không
+CREATE FUNCTION subscription_plan_covers(p_plan_id UUID, p_course_id UUID) RETURNS BOOLEAN AS $$
+    WITH RECURSIVE course AS (
+        SELECT co.id, co.category_id, co.status FROM courses co WHERE co.id = p_course_id
+    ),
+    categories_up AS (
+        SELECT cat.id, cat.parent_id, 1 AS depth
+        FROM categories cat JOIN course ON cat.id = course.category_id
+        UNION ALL
+        SELECT cat.id, cat.parent_id, up.depth + 1
+        FROM categories cat JOIN categories_up up ON cat.id = up.parent_id
+        WHERE up.depth < 10
+    )
+    SELECT EXISTS (
+        SELECT 1
+        FROM subscription_plans sp, course
+        WHERE sp.id = p_plan_id
+          AND CASE sp.scope
+              WHEN 'all' THEN course.status = 'published'
+              WHEN 'categories' THEN course.status = 'published' AND EXISTS (
+                  SELECT 1 FROM subscription_plan_items i
+                  WHERE i.plan_id = sp.id AND i.item_type = 'category'
+                    AND i.item_id IN (SELECT up.id FROM categories_up up))
+              ELSE EXISTS (
+                  SELECT 1 FROM subscription_plan_items i
+                  WHERE i.plan_id = sp.id AND i.item_type = 'course' AND i.item_id = course.id)
+          END
+    )
+$$ LANGUAGE sql STABLE;
+
+-- Quyền học khóa học của user: enrollment (mua, quà tặng, ghi danh tay) hoặc
+-- thuê bao còn hiệu lực có gói gồm khóa học. Enrollment được ưu tiên vì không
+-- hết hạn; giữa các thuê bao chọn thuê bao hết hạn muộn nhất. Không có quyền
+-- thì không trả về dòng nào. internal/entitlement đọc quyền qua hàm này.
+CREATE FUNCTION course_access(p_user_id UUID, p_course_id UUID, p_at TIMESTAMPTZ)
+RETURNS TABLE (source VARCHAR, enrollment_id UUID, subscription_id UUID, expires_at TIMESTAMPTZ) AS $$
+    SELECT 'enrollment'::varchar, e.id, NULL::uuid, NULL::timestamptz
+    FROM enrollments e
+    WHERE e.user_id = p_user_id AND e.course_id = p_course_id
+    UNION ALL
+    SELECT 'subscription'::varchar, NULL::uuid, s.id, COALESCE(s.grace_until, s.current_period_end)
+    FROM subscriptions s
+    WHERE s.user_id = p_user_id
+      AND s.status IN ('active', 'past_due')
+      AND s.current_period_start <= p_at
+      AND COALESCE(s.grace_until, s.current_period_end) > p_at
+      AND subscription_plan_covers(s.plan_id, p_course_id)
+    ORDER BY 4 DESC NULLS FIRST
+    LIMIT 1
+$$ LANGUAGE sql STABLE;
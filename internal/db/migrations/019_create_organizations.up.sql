@@ -0,0 +1,87 @@
+-- Migration: 019_create_organizations.sql
+
+-- Tổ chức (khách hàng doanh nghiệp) mua suất học cho nhân viên
+CREATE TABLE organizations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Thành viên và vai trò: owner quản lý tổ chức, thành viên và mua suất;
+-- manager thêm learner, giao khóa học và xem báo cáo; learner chỉ thấy khóa
+-- học được giao cho mình
+CREATE TABLE organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'learner' CHECK (role IN ('owner', 'manager', 'learner')),
+    added_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+-- Đơn mua suất của tổ chức: mỗi suất là một order_item như đơn quà tặng, nên
+-- sổ cái, hóa đơn và thống kê xử lý như đơn thường
+ALTER TABLE orders ADD COLUMN organization_id UUID REFERENCES organizations(id);
+
+CREATE INDEX idx_orders_organization_id ON orders(organization_id, created_at DESC) WHERE organization_id IS NOT NULL;
+
+-- Kho suất theo khóa học của một đơn. Suất chỉ dùng được khi đơn đã
+-- completed; seats_used không giảm khi gỡ bài được giao vì học viên vẫn giữ
+-- ghi danh
+CREATE TABLE organization_seat_pools (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    seats INTEGER NOT NULL CHECK (seats > 0),
+    seats_used INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (order_id, course_id),
+    CONSTRAINT organization_seat_pools_seats_used_check CHECK (seats_used BETWEEN 0 AND seats)
+);
+
+CREATE INDEX idx_organization_seat_pools_org_course ON organization_seat_pools(organization_id, course_id, created_at);
+
+-- Khóa học giao cho thành viên. Thành viên chưa ghi danh thì lấy một suất
+-- của tổ chức và được ghi danh; seat_pool_id NULL nghĩa là đã ghi danh từ
+-- trước, không tốn suất. Tiến độ đọc từ enrollments.progress_percentage
+CREATE TABLE organization_course_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    course_id UUID NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    seat_pool_id UUID REFERENCES organization_seat_pools(id) ON DELETE SET NULL,
+    enrollment_id UUID REFERENCES enrollments(id) ON DELETE SET NULL,
+    due_date DATE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_organization_course_assignments UNIQUE (organization_id, user_id, course_id),
+    FOREIGN KEY (organization_id, user_id) REFERENCES organization_members(organization_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_organization_course_assignments_course ON organization_course_assignments(organization_id, course_id);
+CREATE INDEX idx_organization_course_assignments_user_id ON organization_course_assignments(user_id);
+
+-- Lời mời vào tổ chức theo email. Thành viên chỉ được thêm khi người được
+-- mời chấp nhận; lời mời được tạo cả khi email chưa đăng ký để phản hồi không
+-- cho biết email nào có tài khoản. Lời mời bị xóa khi được chấp nhận, từ chối
+-- hoặc thu hồi
+CREATE TABLE organization_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'learner' CHECK (role IN ('owner', 'manager', 'learner')),
+    invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_organization_invitations UNIQUE (organization_id, email)
+);
+
+CREATE INDEX idx_organization_invitations_email ON organization_invitations(email);
@@ -0,0 +1,96 @@
+// Package entitlement answers whether a user may study a course. Learners get
+// access from an enrollment (purchase, gift or manual enrollment) or from an
+// active subscription whose plan covers the course; the course_access SQL
+// function (migration 018) decides between them. Admins and the course's
+// instructor can always open its content but are not learners of it.
+package entitlement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Access sources, most specific first.
+const (
+	SourceEnrollment   = "enrollment"
+	SourceSubscription = "subscription"
+	SourceInstructor   = "instructor"
+	SourceAdmin        = "admin"
+)
+
+// Grant is a user's access to one course.
+type Grant struct {
+	CourseID       string
+	Source         string
+	EnrollmentID   *string
+	SubscriptionID *string
+	// ExpiresAt is when subscription access ends; nil for enrollments and staff.
+	ExpiresAt *time.Time
+}
+
+// Learner reports whether the grant comes from an enrollment or a
+// subscription rather than from the user's role.
+func (g Grant) Learner() bool {
+	return g.Source == SourceEnrollment || g.Source == SourceSubscription
+}
+
+// Querier is satisfied by *sql.DB and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Check returns the user's access to a course at time at, or nil when the
+// user has none. Unknown users and courses have no access.
+func Check(ctx context.Context, q Querier, userID, courseID string, at time.Time) (*Grant, error) {
+	grants, err := CheckMany(ctx, q, userID, []string{courseID}, at)
+	if err != nil {
+		return nil, err
+	}
+	if g, ok := grants[courseID]; ok {
+		return &g, nil
+	}
+	return nil, nil
+}
+
+// CheckMany is Check for several courses. Courses the user cannot open are
+// left out of the result.
+func CheckMany(ctx context.Context, q Querier, userID string, courseIDs []string, at time.Time) (map[string]Grant, error) {
+	grants := make(map[string]Grant, len(courseIDs))
+	if userID == "" || len(courseIDs) == 0 {
+		return grants, nil
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT co.id,
+		       COALESCE(a.source, CASE WHEN u.role = 'admin' THEN 'admin'
+		                               WHEN co.instructor_id = u.id THEN 'instructor' END),
+		       a.enrollment_id, a.subscription_id, a.expires_at
+		FROM users u
+		JOIN courses co ON co.id = ANY($2)
+		LEFT JOIN LATERAL course_access(u.id, co.id, $3) a ON TRUE
+		WHERE u.id = $1
+	`, userID, courseIDs, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var g Grant
+		var source sql.NullString
+		var expiresAt sql.NullTime
+		if err := rows.Scan(&g.CourseID, &source, &g.EnrollmentID, &g.SubscriptionID, &expiresAt); err != nil {
+			return nil, err
+		}
+		if !source.Valid {
+			continue
+		}
+		g.Source = source.String
+		if expiresAt.Valid {
+			g.ExpiresAt = &expiresAt.Time
+		}
+		grants[g.CourseID] = g
+	}
+	return grants, rows.Err()
+}
@@ -0,0 +1,89 @@
+// Package export streams tabular reports as CSV or XLSX. Rows are written
+// as they are produced, so a report never has to fit in memory.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Formats accepted by New.
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// Writer writes one table. Cells may be string, bool, any integer or float
+// type, time.Time or nil; numbers stay numeric in XLSX.
+type Writer interface {
+	Write(row []interface{}) error
+	// Close finishes the document; it does not close the underlying writer.
+	Close() error
+}
+
+// New returns a Writer for format (CSV or XLSX).
+func New(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w)
+	case XLSX:
+		return newXLSXWriter(w, sheet)
+	}
+	return nil, fmt.Errorf("export: unknown format %q", format)
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	if format == XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (Writer, error) {
+	// The byte order mark makes Excel read the file as UTF-8 (Vietnamese text).
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) Write(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, v := range row {
+		record[i] = formatCell(v)
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// formatCell renders v the way it appears in a CSV cell.
+func formatCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
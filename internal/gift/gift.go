@@ -0,0 +1,77 @@
+// Package gift issues and reads the codes of gift vouchers. A gift order buys
+// one seat per voucher; whoever redeems a voucher is enrolled in its course.
+// Vouchers are created with the order and become redeemable once the order
+// is paid, so payment providers need no knowledge of gifts.
+package gift
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// Voucher statuses as reported to the purchaser. Status computes them in SQL.
+const (
+	StatusPendingPayment = "pending_payment"
+	StatusAvailable      = "available"
+	StatusRedeemed       = "redeemed"
+	StatusExpired        = "expired"
+	StatusCancelled      = "cancelled" // the order failed or was refunded
+)
+
+// Status is a SQL expression for the status of voucher v of order o.
+const Status = `
+	CASE WHEN v.redeemed_at IS NOT NULL THEN 'redeemed'
+	     WHEN o.payment_status = 'pending' THEN 'pending_payment'
+	     WHEN o.payment_status <> 'completed' THEN 'cancelled'
+	     WHEN v.expires_at <= now() THEN 'expired'
+	     ELSE 'available' END`
+
+// alphabet leaves out 0, 1, I and O, which are easily misread.
+const alphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+const (
+	codeLength = 16 // 80 bits
+	groupSize  = 4
+)
+
+// NewCode returns a random code such as "7K3M-Q9XA-2BHD-TNPW".
+func NewCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		// 256 is a multiple of len(alphabet), so this is unbiased
+		buf[i] = alphabet[int(b)%len(alphabet)]
+	}
+	return format(string(buf)), nil
+}
+
+// NormalizeCode turns user input into the stored form: upper case, grouped
+// by dashes, with spaces and stray dashes ignored. It returns "" when the
+// input cannot be a code.
+func NormalizeCode(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case strings.ContainsRune(alphabet, r):
+			b.WriteRune(r)
+		default:
+			return ""
+		}
+	}
+	if b.Len() != codeLength {
+		return ""
+	}
+	return format(b.String())
+}
+
+func format(s string) string {
+	groups := make([]string, 0, len(s)/groupSize)
+	for i := 0; i < len(s); i += groupSize {
+		groups = append(groups, s[i:i+groupSize])
+	}
+	return strings.Join(groups, "-")
+}
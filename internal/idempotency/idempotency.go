@@ -0,0 +1,151 @@
+// Package idempotency stores the first response to a request carrying an
+// Idempotency-Key so that retries of the same request can be answered
+// without running the handler again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sweepInterval is how often expired keys are deleted.
+const sweepInterval = 10 * time.Minute
+
+var (
+	// ErrInProgress means another request with the same key has not
+	// finished yet.
+	ErrInProgress = errors.New("idempotency: request with this key is in progress")
+	// ErrMismatch means the key was first used with a different request.
+	ErrMismatch = errors.New("idempotency: key reused with a different request")
+)
+
+// Response is a stored response, replayed verbatim on retry.
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// Store keeps keys in the idempotency_keys table. A key is scoped to the
+// caller (the middleware passes the user, or the client IP and route for
+// anonymous calls) and expires after ttl.
+type Store struct {
+	db  *sql.DB
+	ttl time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewStore(db *sql.DB, ttl time.Duration) *Store {
+	return &Store{db: db, ttl: ttl, lastSweep: time.Now()}
+}
+
+// Hash fingerprints a request so a reused key with another payload can be
+// told apart from a retry.
+func Hash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Begin claims key for a request with requestHash. It returns (nil, nil)
+// when the caller should run the handler and then Complete or Release the
+// key, or the stored response when the request is a retry of a finished one.
+// An expired key is claimed again as if it were new.
+func (s *Store) Begin(ctx context.Context, scope, key, requestHash string) (*Response, error) {
+	now := time.Now()
+	s.maybeSweep(now)
+
+	var claimed string
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING key
+	`, scope, key, requestHash, now, now.Add(s.ttl)).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	// The key is held by an earlier request.
+	var storedHash string
+	var statusCode sql.NullInt32
+	var contentType sql.NullString
+	var body []byte
+	err = s.db.QueryRowContext(ctx, `
+		SELECT request_hash, status_code, content_type, response_body
+		FROM idempotency_keys WHERE scope = $1 AND key = $2
+	`, scope, key).Scan(&storedHash, &statusCode, &contentType, &body)
+	if err == sql.ErrNoRows {
+		// Released between the two statements; the client may retry.
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	if storedHash != requestHash {
+		return nil, ErrMismatch
+	}
+	if !statusCode.Valid {
+		return nil, ErrInProgress
+	}
+	return &Response{StatusCode: int(statusCode.Int32), ContentType: contentType.String, Body: body}, nil
+}
+
+// Complete stores the response for a key claimed with Begin.
+func (s *Store) Complete(ctx context.Context, scope, key string, resp Response) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET status_code = $3, content_type = $4, response_body = $5
+		WHERE scope = $1 AND key = $2
+	`, scope, key, resp.StatusCode, resp.ContentType, resp.Body)
+	return err
+}
+
+// Release frees a claimed key without storing a response, so the request
+// can be retried (used when the handler failed with a server error).
+func (s *Store) Release(ctx context.Context, scope, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2", scope, key)
+	return err
+}
+
+// maybeSweep deletes expired keys in the background at most once per
+// sweepInterval per instance.
+func (s *Store) maybeSweep(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if _, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= $1", now); err != nil {
+			logrus.WithError(err).Warn("idempotency: failed to sweep expired keys")
+		}
+	}()
+}
@@ -0,0 +1,161 @@
+package invoice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// trueType is the subset of a TrueType font the PDF writer needs: the
+// character map, glyph advances and the metrics for the font descriptor.
+// The whole file is embedded, so no subsetting is done here.
+type trueType struct {
+	data       []byte
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	advances   []uint16
+	cmap       map[rune]uint16
+}
+
+// loadTrueType reads a .ttf file. Only fonts with a Unicode BMP (format 4)
+// cmap are supported, which covers the usual Vietnamese-capable fonts such
+// as DejaVu Sans or Noto Sans.
+func loadTrueType(path string) (*trueType, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 12 {
+		return nil, errors.New("invoice: font file too short")
+	}
+
+	tables := map[string][]byte{}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, errors.New("invoice: truncated font table directory")
+		}
+		tag := string(data[rec : rec+4])
+		off := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if off+length > len(data) {
+			return nil, fmt.Errorf("invoice: font table %s out of range", tag)
+		}
+		tables[tag] = data[off : off+length]
+	}
+	for _, tag := range []string{"head", "hhea", "hmtx", "cmap"} {
+		if tables[tag] == nil {
+			return nil, fmt.Errorf("invoice: font has no %s table", tag)
+		}
+	}
+
+	head, hhea := tables["head"], tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 {
+		return nil, errors.New("invoice: malformed head or hhea table")
+	}
+	f := &trueType{
+		data:       data,
+		unitsPerEm: int(binary.BigEndian.Uint16(head[18:])),
+		ascent:     int(int16(binary.BigEndian.Uint16(hhea[4:]))),
+		descent:    int(int16(binary.BigEndian.Uint16(hhea[6:]))),
+		cmap:       map[rune]uint16{},
+	}
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+
+	hmtx := tables["hmtx"]
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if 4*numMetrics > len(hmtx) {
+		return nil, errors.New("invoice: malformed hmtx table")
+	}
+	f.advances = make([]uint16, numMetrics)
+	for i := range f.advances {
+		f.advances[i] = binary.BigEndian.Uint16(hmtx[4*i:])
+	}
+
+	if err := f.parseCmap(tables["cmap"]); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *trueType) parseCmap(cmap []byte) error {
+	if len(cmap) < 4 {
+		return errors.New("invoice: malformed cmap table")
+	}
+	n := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < n; i++ {
+		rec := 4 + 8*i
+		if rec+8 > len(cmap) {
+			break
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		off := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		unicode := platform == 0 || (platform == 3 && encoding == 1)
+		if !unicode || off+14 > len(cmap) || binary.BigEndian.Uint16(cmap[off:]) != 4 {
+			continue
+		}
+		return f.parseFormat4(cmap[off:])
+	}
+	return errors.New("invoice: font has no Unicode BMP cmap")
+}
+
+func (f *trueType) parseFormat4(t []byte) error {
+	segCount := int(binary.BigEndian.Uint16(t[6:])) / 2
+	ends := 14
+	starts := ends + 2*segCount + 2
+	deltas := starts + 2*segCount
+	rangeOffsets := deltas + 2*segCount
+	if rangeOffsets+2*segCount > len(t) {
+		return errors.New("invoice: malformed cmap format 4 subtable")
+	}
+
+	u16 := func(off int) uint16 {
+		if off+2 > len(t) {
+			return 0
+		}
+		return binary.BigEndian.Uint16(t[off:])
+	}
+	for s := 0; s < segCount; s++ {
+		end, start := u16(ends+2*s), u16(starts+2*s)
+		delta, rangeOffset := u16(deltas+2*s), u16(rangeOffsets+2*s)
+		for c := uint32(start); c <= uint32(end) && c != 0xFFFF; c++ {
+			var gid uint16
+			if rangeOffset == 0 {
+				gid = uint16(c) + delta
+			} else {
+				gid = u16(rangeOffsets + 2*s + int(rangeOffset) + 2*int(c-uint32(start)))
+				if gid != 0 {
+					gid += delta
+				}
+			}
+			if gid != 0 {
+				f.cmap[rune(c)] = gid
+			}
+		}
+	}
+	return nil
+}
+
+// advance returns the width of a glyph in 1/1000 of the font size.
+func (f *trueType) advance(gid uint16) float64 {
+	if len(f.advances) == 0 {
+		return 0
+	}
+	w := f.advances[len(f.advances)-1]
+	if int(gid) < len(f.advances) {
+		w = f.advances[gid]
+	}
+	return float64(w) * 1000 / float64(f.unitsPerEm)
+}
+
+// scale converts font units to the 1/1000 text space PDF metrics use.
+func (f *trueType) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
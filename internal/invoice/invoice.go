@@ -0,0 +1,279 @@
+// Package invoice issues invoices for paid orders and renders them to HTML
+// and PDF. Numbers are allocated per series (prefix and year) from the
+// invoice_sequences table inside the issuing transaction, so a number is
+// only used once the invoice exists and the sequence has no gaps. Amounts
+// are computed in SQL with the same rounding as the ledger (migration 013).
+package invoice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNotFound       = errors.New("invoice: not found")
+	ErrOrderNotFound  = errors.New("invoice: order not found")
+	ErrOrderNotPaid   = errors.New("invoice: order is not completed")
+	ErrAlreadyIssued  = errors.New("invoice: already issued for this order")
+	errNoInvoiceItems = errors.New("invoice: order has no items")
+)
+
+// Zone is the time zone invoice dates and series years are expressed in.
+var Zone = mustLoadLocation("Asia/Ho_Chi_Minh")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, 7*60*60)
+	}
+	return loc
+}
+
+// Options configures issuing.
+type Options struct {
+	Prefix  string // series prefix, e.g. "TTC" gives series "TTC2026"
+	VATRate int64  // basis points; prices are VAT inclusive, 1000 means 10%
+}
+
+// Company holds the details a business buyer needs on the invoice.
+type Company struct {
+	Name    string
+	TaxCode string
+	Address string
+}
+
+// Seller is printed in the invoice header.
+type Seller struct {
+	Name    string
+	TaxCode string
+	Address string
+	Email   string
+}
+
+type Item struct {
+	LineNo      int
+	CourseID    *string
+	Description string
+	UnitPrice   int64 // amounts are in minor units of the invoice currency
+	Discount    int64
+	NetAmount   int64
+	VATAmount   int64
+	Amount      int64
+}
+
+type Invoice struct {
+	ID        string
+	OrderID   string
+	UserID    string
+	Series    string
+	Number    int64
+	No        string
+	IssuedAt  time.Time
+	Buyer     string
+	Email     string
+	Company   *Company
+	Currency  string
+	Coupon    *string
+	Subtotal  int64 // minor units of Currency
+	Discount  int64
+	VATRate   int64 // basis points
+	NetAmount int64
+	VATAmount int64
+	Total     int64
+	Items     []Item
+	HTMLKey   *string
+	PDFKey    *string
+}
+
+// Issue creates the invoice for a completed order. Concurrent calls for the
+// same order are serialised on the order row; the loser gets
+// ErrAlreadyIssued.
+func Issue(ctx context.Context, db *sql.DB, orderID string, company *Company, opts Options) (*Invoice, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, "SELECT payment_status FROM orders WHERE id = $1 FOR UPDATE", orderID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != "completed" {
+		return nil, ErrOrderNotPaid
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM invoices WHERE order_id = $1)", orderID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrAlreadyIssued
+	}
+
+	issuedAt := time.Now()
+	series := fmt.Sprintf("%s%d", opts.Prefix, issuedAt.In(Zone).Year())
+
+	// The row lock taken here is held until commit, so the next issuer in
+	// this series waits and a rolled back transaction gives its number back.
+	var number int64
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO invoice_sequences (series, last_number) VALUES ($1, 1)
+		ON CONFLICT (series) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number`, series).Scan(&number); err != nil {
+		return nil, err
+	}
+
+	var c Company
+	if company != nil {
+		c = *company
+	}
+	var invoiceID string
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO invoices (order_id, series, number, invoice_no, issued_at,
+		                      buyer_name, buyer_email, buyer_company, buyer_tax_code, buyer_address,
+		                      currency, coupon_code, subtotal, discount, vat_rate, net_amount, vat_amount, total)
+		SELECT o.id, $2, $3, $4, $5,
+		       u.first_name || ' ' || u.last_name, u.email, NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''),
+		       COALESCE(o.currency, 'VND'), cp.code, 0, 0, $9::numeric / 10000, 0, 0, 0
+		FROM orders o
+		JOIN users u ON u.id = o.user_id
+		LEFT JOIN coupons cp ON cp.id = o.coupon_id
+		WHERE o.id = $1
+		RETURNING id`,
+		orderID, series, number, fmt.Sprintf("%s-%06d", series, number), issuedAt,
+		c.Name, c.TaxCode, c.Address, opts.VATRate,
+	).Scan(&invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One line per course at its own discounted price, then one negative
+	// line for any order-level discount the item prices do not reflect.
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO invoice_items (invoice_id, line_no, course_id, description, unit_price, discount,
+		                           net_amount, vat_amount, amount)
+		SELECT $1, ROW_NUMBER() OVER (ORDER BY oi.created_at, oi.id), oi.course_id, c.title,
+		       oi.price, oi.price - oi.final_price,
+		       round_money(oi.final_price / (1 + $2::numeric / 10000), i.currency),
+		       oi.final_price - round_money(oi.final_price / (1 + $2::numeric / 10000), i.currency),
+		       oi.final_price
+		FROM order_items oi
+		JOIN courses c ON c.id = oi.course_id
+		JOIN invoices i ON i.id = $1
+		WHERE oi.order_id = i.order_id`, invoiceID, opts.VATRate)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, errNoInvoiceItems
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO invoice_items (invoice_id, line_no, description, unit_price, discount,
+		                           net_amount, vat_amount, amount)
+		SELECT i.id, d.lines + 1,
+		       'Giảm giá đơn hàng' || COALESCE(' (' || i.coupon_code || ')', ''),
+		       0, d.extra,
+		       -round_money(d.extra / (1 + $2::numeric / 10000), i.currency),
+		       -(d.extra - round_money(d.extra / (1 + $2::numeric / 10000), i.currency)),
+		       -d.extra
+		FROM invoices i
+		JOIN orders o ON o.id = i.order_id
+		CROSS JOIN LATERAL (
+		    SELECT COUNT(*) AS lines, SUM(ii.amount) - o.final_amount AS extra
+		    FROM invoice_items ii WHERE ii.invoice_id = i.id
+		) d
+		WHERE i.id = $1 AND d.extra > 0`, invoiceID, opts.VATRate); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE invoices i
+		SET subtotal = t.subtotal, discount = t.discount, net_amount = t.net,
+		    vat_amount = t.vat, total = t.total
+		FROM (
+		    SELECT SUM(unit_price) AS subtotal, SUM(discount) AS discount,
+		           SUM(net_amount) AS net, SUM(vat_amount) AS vat, SUM(amount) AS total
+		    FROM invoice_items WHERE invoice_id = $1
+		) t
+		WHERE i.id = $1`, invoiceID); err != nil {
+		return nil, err
+	}
+
+	inv, err := load(ctx, tx, "i.id = $1", invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return inv, nil
+}
+
+// Load returns the invoice of an order.
+func Load(ctx context.Context, db *sql.DB, orderID string) (*Invoice, error) {
+	return load(ctx, db, "i.order_id = $1", orderID)
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func load(ctx context.Context, q querier, where string, arg string) (*Invoice, error) {
+	var inv Invoice
+	var company, taxCode, address sql.NullString
+	err := q.QueryRowContext(ctx, `
+		SELECT i.id, i.order_id, o.user_id, i.series, i.number, i.invoice_no, i.issued_at,
+		       i.buyer_name, i.buyer_email, i.buyer_company, i.buyer_tax_code, i.buyer_address,
+		       i.currency, i.coupon_code, minor_units(i.subtotal, i.currency), minor_units(i.discount, i.currency),
+		       (i.vat_rate * 10000)::bigint, minor_units(i.net_amount, i.currency), minor_units(i.vat_amount, i.currency),
+		       minor_units(i.total, i.currency), i.html_key, i.pdf_key
+		FROM invoices i
+		JOIN orders o ON o.id = i.order_id
+		WHERE `+where, arg,
+	).Scan(&inv.ID, &inv.OrderID, &inv.UserID, &inv.Series, &inv.Number, &inv.No, &inv.IssuedAt,
+		&inv.Buyer, &inv.Email, &company, &taxCode, &address,
+		&inv.Currency, &inv.Coupon, &inv.Subtotal, &inv.Discount, &inv.VATRate,
+		&inv.NetAmount, &inv.VATAmount, &inv.Total, &inv.HTMLKey, &inv.PDFKey)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if company.Valid || taxCode.Valid || address.Valid {
+		inv.Company = &Company{Name: company.String, TaxCode: taxCode.String, Address: address.String}
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT ii.line_no, ii.course_id, ii.description,
+		       minor_units(ii.unit_price, i.currency), minor_units(ii.discount, i.currency),
+		       minor_units(ii.net_amount, i.currency), minor_units(ii.vat_amount, i.currency),
+		       minor_units(ii.amount, i.currency)
+		FROM invoice_items ii
+		JOIN invoices i ON i.id = ii.invoice_id
+		WHERE ii.invoice_id = $1
+		ORDER BY ii.line_no`, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var it Item
+		if err := rows.Scan(&it.LineNo, &it.CourseID, &it.Description, &it.UnitPrice, &it.Discount,
+			&it.NetAmount, &it.VATAmount, &it.Amount); err != nil {
+			return nil, err
+		}
+		inv.Items = append(inv.Items, it)
+	}
+	return &inv, rows.Err()
+}
@@ -0,0 +1,232 @@
+package invoice
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A4 in points.
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// pdfDoc is a minimal PDF writer: text and rules on A4 pages. With a
+// TrueType font the text is written as glyph IDs (Identity-H), so any
+// character the font covers renders, Vietnamese included. Without one it
+// falls back to the built-in Courier font and ASCII transliteration.
+type pdfDoc struct {
+	font  *trueType
+	pages []*bytes.Buffer
+	used  map[uint16]rune
+}
+
+func newPDF(font *trueType) *pdfDoc {
+	d := &pdfDoc{font: font, used: map[uint16]rune{}}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDoc) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *pdfDoc) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// y is measured from the top of the page, which suits a layout that flows
+// downwards; PDF coordinates start at the bottom.
+func (d *pdfDoc) text(x, y, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F1 %.2f Tf %.2f %.2f Td %s Tj ET\n", size, x, pageHeight-y, d.encode(s))
+}
+
+func (d *pdfDoc) textRight(right, y, size float64, s string) {
+	d.text(right-d.width(s, size), y, size, s)
+}
+
+func (d *pdfDoc) line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.page(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", 0.5, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+func (d *pdfDoc) width(s string, size float64) float64 {
+	if d.font == nil {
+		return float64(len(transliterate(s))) * 0.6 * size // Courier is 600 units wide
+	}
+	var w float64
+	for _, r := range norm.NFC.String(s) {
+		w += d.font.advance(d.font.cmap[r])
+	}
+	return w * size / 1000
+}
+
+// wrap splits s into lines no wider than max.
+func (d *pdfDoc) wrap(s string, size, max float64) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		next := word
+		if line != "" {
+			next = line + " " + word
+		}
+		if line != "" && d.width(next, size) > max {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (d *pdfDoc) encode(s string) string {
+	if d.font == nil {
+		s = transliterate(s)
+		r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+		return "(" + r.Replace(s) + ")"
+	}
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range norm.NFC.String(s) {
+		gid := d.font.cmap[r]
+		if gid != 0 {
+			d.used[gid] = r
+		}
+		fmt.Fprintf(&b, "%04X", gid)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// transliterate reduces s to printable ASCII for the Courier fallback.
+func transliterate(s string) string {
+	s = strings.NewReplacer("đ", "d", "Đ", "D", "–", "-", "—", "-", "“", `"`, "”", `"`, "’", "'").Replace(s)
+	if t, _, err := transform.String(stripMarks, s); err == nil {
+		s = t
+	}
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+// WriteTo serialises the document.
+func (d *pdfDoc) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	var offsets []int
+	obj := func(body string) int {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+		return len(offsets)
+	}
+	stream := func(dict string, data []byte) int {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(data)
+		zw.Close()
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n<< %s /Filter /FlateDecode /Length %d >>\nstream\n", len(offsets), dict, z.Len())
+		out.Write(z.Bytes())
+		out.WriteString("\nendstream\nendobj\n")
+		return len(offsets)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 and 2 are the catalog and page tree; the page tree is
+	// written last, once the page object numbers are known.
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	offsets = append(offsets, 0)
+
+	font := d.writeFont(obj, stream)
+
+	var kids []string
+	for _, p := range d.pages {
+		content := stream("", p.Bytes())
+		page := obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, font, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+
+	offsets[1] = out.Len()
+	fmt.Fprintf(&out, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(kids))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(out.Bytes())
+	return int64(n), err
+}
+
+func (d *pdfDoc) writeFont(obj func(string) int, stream func(string, []byte) int) int {
+	f := d.font
+	if f == nil {
+		return obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	}
+
+	gids := make([]int, 0, len(d.used))
+	for gid := range d.used {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+
+	var widths, toUnicode strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%.0f] ", gid, f.advance(uint16(gid)))
+	}
+	toUnicode.WriteString("/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def /CMapType 2 def\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n")
+	for i := 0; i < len(gids); i += 100 {
+		chunk := gids[i:]
+		if len(chunk) > 100 {
+			chunk = chunk[:100]
+		}
+		fmt.Fprintf(&toUnicode, "%d beginbfchar\n", len(chunk))
+		for _, gid := range chunk {
+			fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", gid, utf16Hex(d.used[uint16(gid)]))
+		}
+		toUnicode.WriteString("endbfchar\n")
+	}
+	toUnicode.WriteString("endcmap CMapName currentdict /CMap defineresource pop end end\n")
+
+	file := stream(fmt.Sprintf("/Length1 %d", len(f.data)), f.data)
+	descriptor := obj(fmt.Sprintf("<< /Type /FontDescriptor /FontName /InvoiceFont /Flags 32 "+
+		"/FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), file))
+	cidFont := obj(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /InvoiceFont "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>", descriptor, widths.String()))
+	cmap := stream("", []byte(toUnicode.String()))
+	return obj(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /InvoiceFont /Encoding /Identity-H "+
+		"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", cidFont, cmap))
+}
+
+func utf16Hex(r rune) string {
+	if r < 0x10000 {
+		return fmt.Sprintf("%04X", r)
+	}
+	r -= 0x10000
+	return fmt.Sprintf("%04X%04X", 0xD800+(r>>10), 0xDC00+(r&0x3FF))
+}
@@ -0,0 +1,329 @@
+package invoice
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"internal/money"
+	"internal/storage"
+)
+
+// Publisher renders invoices and keeps the rendered files in storage.
+type Publisher struct {
+	db     *sql.DB
+	store  storage.Storage
+	seller Seller
+	font   *trueType
+}
+
+// NewPublisher loads the PDF font once. An empty fontPath selects the
+// built-in Courier font, which cannot show Vietnamese marks.
+func NewPublisher(db *sql.DB, store storage.Storage, seller Seller, fontPath string) (*Publisher, error) {
+	p := &Publisher{db: db, store: store, seller: seller}
+	if fontPath != "" {
+		font, err := loadTrueType(fontPath)
+		if err != nil {
+			return nil, fmt.Errorf("invoice: load font %s: %w", fontPath, err)
+		}
+		p.font = font
+	}
+	return p, nil
+}
+
+// Formats an invoice can be downloaded in.
+const (
+	HTML = "html"
+	PDF  = "pdf"
+)
+
+func ContentType(format string) string {
+	if format == PDF {
+		return "application/pdf"
+	}
+	return "text/html; charset=utf-8"
+}
+
+// Key returns where the rendered file of inv is stored.
+func Key(inv *Invoice, format string) string {
+	return "invoices/" + inv.Series + "/" + inv.No + "." + format
+}
+
+// Publish renders every format not yet stored and records the keys. It is
+// safe to call again after a partial failure.
+func (p *Publisher) Publish(ctx context.Context, inv *Invoice) error {
+	for _, format := range []string{HTML, PDF} {
+		column, stored := "html_key", inv.HTMLKey
+		if format == PDF {
+			column, stored = "pdf_key", inv.PDFKey
+		}
+		if stored != nil {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := p.Render(&buf, inv, format); err != nil {
+			return err
+		}
+		key := Key(inv, format)
+		if err := p.store.Put(ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), ContentType(format)); err != nil {
+			return err
+		}
+		if _, err := p.db.ExecContext(ctx,
+			"UPDATE invoices SET "+column+" = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", inv.ID, key); err != nil {
+			return err
+		}
+		if format == PDF {
+			inv.PDFKey = &key
+		} else {
+			inv.HTMLKey = &key
+		}
+	}
+	return nil
+}
+
+// Open returns the stored file of inv in format.
+func (p *Publisher) Open(ctx context.Context, inv *Invoice, format string) (io.ReadCloser, error) {
+	key := inv.HTMLKey
+	if format == PDF {
+		key = inv.PDFKey
+	}
+	if key == nil {
+		return nil, storage.ErrNotFound
+	}
+	return p.store.Open(ctx, *key)
+}
+
+func (p *Publisher) Render(w io.Writer, inv *Invoice, format string) error {
+	if format == PDF {
+		_, err := p.renderPDF(inv).WriteTo(w)
+		return err
+	}
+	return htmlTemplate.Execute(w, struct {
+		Seller Seller
+		*Invoice
+	}{p.seller, inv})
+}
+
+func (p *Publisher) renderPDF(inv *Invoice) *pdfDoc {
+	d := newPDF(p.font)
+	const left, right = 40.0, pageWidth - 40
+
+	y := 50.0
+	d.text(left, y, 13, p.seller.Name)
+	d.textRight(right, y, 15, "HÓA ĐƠN / INVOICE")
+	y += 16
+	for _, s := range []string{p.seller.Address, label("MST", p.seller.TaxCode), p.seller.Email} {
+		if s != "" {
+			d.text(left, y, 8.5, s)
+			y += 11
+		}
+	}
+	d.textRight(right, 66, 9, "Số / No: "+inv.No)
+	d.textRight(right, 78, 9, "Ngày / Date: "+inv.IssuedAt.In(Zone).Format("02/01/2006"))
+
+	y = math.Max(y, 90) + 12
+	d.line(left, y, right, y)
+	y += 18
+	d.text(left, y, 9, "Người mua / Buyer: "+inv.Buyer)
+	y += 12
+	d.text(left, y, 9, "Email: "+inv.Email)
+	if c := inv.Company; c != nil {
+		for _, s := range []string{label("Đơn vị / Company", c.Name), label("MST / Tax code", c.TaxCode), label("Địa chỉ / Address", c.Address)} {
+			if s != "" {
+				y += 12
+				d.text(left, y, 9, s)
+			}
+		}
+	}
+	y += 12
+	d.text(left, y, 9, "Tiền tệ / Currency: "+inv.Currency)
+
+	// Columns: #, description, unit price, discount, VAT, amount
+	cols := []float64{left + 14, 330, 400, 465, right}
+	header := func() {
+		y += 22
+		d.text(left, y, 8.5, "#")
+		d.text(left+18, y, 8.5, "Nội dung")
+		d.text(left+18, y+10, 7.5, "Description")
+		for i, h := range [][2]string{{"Đơn giá", "Unit price"}, {"Giảm giá", "Discount"}, {"Thuế GTGT", "VAT"}, {"Thành tiền", "Amount"}} {
+			d.textRight(cols[i+1], y, 8.5, h[0])
+			d.textRight(cols[i+1], y+10, 7.5, h[1])
+		}
+		y += 15
+		d.line(left, y, right, y)
+	}
+	header()
+
+	for _, it := range inv.Items {
+		lines := d.wrap(it.Description, 9, 210)
+		if y+float64(len(lines))*11+14 > pageHeight-60 {
+			d.newPage()
+			y = 40
+			header()
+		}
+		y += 14
+		d.text(left, y, 9, strconv.Itoa(it.LineNo))
+		for i, line := range lines {
+			d.text(left+18, y+float64(i)*11, 9, line)
+		}
+		for i, v := range []int64{it.UnitPrice, it.Discount, it.VATAmount, it.Amount} {
+			d.textRight(cols[i+1], y, 9, formatAmount(v, inv.Currency))
+		}
+		y += float64(len(lines)-1) * 11
+	}
+
+	if y > pageHeight-150 {
+		d.newPage()
+		y = 40
+	}
+	y += 8
+	d.line(left, y, right, y)
+	for _, row := range totals(inv) {
+		y += 15
+		size := 9.0
+		if row.Strong {
+			size = 10.5
+		}
+		d.textRight(cols[2], y, size, row.Label)
+		d.textRight(right, y, size, row.Value)
+	}
+	return d
+}
+
+type totalRow struct {
+	Label, Value string
+	Strong       bool
+}
+
+func totals(inv *Invoice) []totalRow {
+	rows := []totalRow{{Label: "Tổng tiền hàng / Subtotal", Value: formatMoney(inv.Subtotal, inv.Currency)}}
+	if inv.Discount != 0 {
+		discount := "Giảm giá / Discount"
+		if inv.Coupon != nil {
+			discount += " (" + *inv.Coupon + ")"
+		}
+		rows = append(rows, totalRow{Label: discount, Value: "-" + formatMoney(inv.Discount, inv.Currency)})
+	}
+	return append(rows,
+		totalRow{Label: "Tiền trước thuế / Net amount", Value: formatMoney(inv.NetAmount, inv.Currency)},
+		totalRow{Label: fmt.Sprintf("Thuế GTGT / VAT (%s%%)", formatRate(inv.VATRate)), Value: formatMoney(inv.VATAmount, inv.Currency)},
+		totalRow{Label: "Tổng thanh toán / Total", Value: formatMoney(inv.Total, inv.Currency), Strong: true},
+	)
+}
+
+func label(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return name + ": " + value
+}
+
+// formatAmount writes v (minor units) the Vietnamese way: "." between
+// thousands and "," before decimals.
+func formatAmount(v int64, currency string) string {
+	s := money.New(v, currency).Decimal()
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString("," + frac)
+	}
+	return b.String()
+}
+
+func formatMoney(v int64, currency string) string {
+	return formatAmount(v, currency) + " " + currency
+}
+
+// formatRate writes bp basis points as a percentage: 1000 is "10", 850 is
+// "8,5".
+func formatRate(bp int64) string {
+	s := strconv.FormatInt(bp/100, 10)
+	if frac := strings.TrimRight(fmt.Sprintf("%02d", bp%100), "0"); frac != "" {
+		s += "," + frac
+	}
+	return s
+}
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount": formatAmount,
+	"money":  formatMoney,
+	"date":   func(inv *Invoice) string { return inv.IssuedAt.In(Zone).Format("02/01/2006") },
+	"totals": totals,
+}).Parse(`<!DOCTYPE html>
+<html lang="vi">
+<head>
+<meta charset="utf-8">
+<title>Hóa đơn {{.No}}</title>
+<style>
+body { font-family: "DejaVu Sans", Arial, sans-serif; font-size: 13px; color: #222; max-width: 800px; margin: 24px auto; }
+header { display: flex; justify-content: space-between; border-bottom: 1px solid #999; padding-bottom: 12px; }
+h1 { font-size: 20px; margin: 0 0 6px; text-align: right; }
+table { width: 100%; border-collapse: collapse; margin-top: 18px; }
+th, td { padding: 6px 4px; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; white-space: nowrap; }
+.totals td { border: none; }
+.strong td { font-weight: bold; font-size: 15px; }
+</style>
+</head>
+<body>
+<header>
+  <div>
+    <strong>{{.Seller.Name}}</strong><br>
+    {{with .Seller.Address}}{{.}}<br>{{end}}
+    {{with .Seller.TaxCode}}MST: {{.}}<br>{{end}}
+    {{.Seller.Email}}
+  </div>
+  <div>
+    <h1>HÓA ĐƠN / INVOICE</h1>
+    Số / No: <strong>{{.No}}</strong><br>
+    Ngày / Date: {{date .Invoice}}
+  </div>
+</header>
+<section>
+  <p>
+    Người mua / Buyer: {{.Buyer}}<br>
+    Email: {{.Email}}<br>
+    {{with .Company}}
+    {{with .Name}}Đơn vị / Company: {{.}}<br>{{end}}
+    {{with .TaxCode}}MST / Tax code: {{.}}<br>{{end}}
+    {{with .Address}}Địa chỉ / Address: {{.}}<br>{{end}}
+    {{end}}
+    Tiền tệ / Currency: {{.Currency}}
+  </p>
+</section>
+<table>
+  <thead>
+    <tr><th>#</th><th>Nội dung / Description</th><th class="num">Đơn giá / Price</th><th class="num">Giảm / Discount</th><th class="num">VAT</th><th class="num">Thành tiền / Amount</th></tr>
+  </thead>
+  <tbody>
+  {{$cur := .Currency}}
+  {{range .Items}}
+    <tr><td>{{.LineNo}}</td><td>{{.Description}}</td><td class="num">{{amount .UnitPrice $cur}}</td><td class="num">{{amount .Discount $cur}}</td><td class="num">{{amount .VATAmount $cur}}</td><td class="num">{{amount .Amount $cur}}</td></tr>
+  {{end}}
+  </tbody>
+</table>
+<table class="totals">
+  {{range totals .Invoice}}
+  <tr{{if .Strong}} class="strong"{{end}}><td class="num">{{.Label}}</td><td class="num">{{.Value}}</td></tr>
+  {{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,39 @@
+package invoice
+
+import "testing"
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		bp   int64
+		want string
+	}{
+		{1000, "10"},
+		{850, "8,5"},
+		{525, "5,25"},
+		{5, "0,05"},
+		{0, "0"},
+	}
+	for _, tt := range tests {
+		if got := formatRate(tt.bp); got != tt.want {
+			t.Errorf("formatRate(%d) = %q, want %q", tt.bp, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		v        int64
+		currency string
+		want     string
+	}{
+		{1299000, "VND", "1.299.000"},
+		{-50000, "VND", "-50.000"},
+		{123456, "USD", "1.234,56"},
+		{5, "EUR", "0,05"},
+	}
+	for _, tt := range tests {
+		if got := formatAmount(tt.v, tt.currency); got != tt.want {
+			t.Errorf("formatAmount(%d, %s) = %q, want %q", tt.v, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+// Package ledger pays out instructor earnings recorded in the double-entry
+// ledger. Sales and refunds are posted by database triggers on orders
+// (migration 013); this package posts the payout side: a payout debits the
+// instructor's payable account against cash, and a payout that fails or is
+// cancelled is reversed so the amount is owed again.
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Payout and batch statuses. Paid, failed and cancelled are final.
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusPaid       = "paid"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+)
+
+var (
+	ErrNotFound          = errors.New("ledger: payout or batch not found")
+	ErrInvalidTransition = errors.New("ledger: status change not allowed")
+)
+
+// runLockKey serialises payout runs so two runs cannot pay the same balance.
+const runLockKey = 0x7061796f7574 // "payout"
+
+// transitions lists the statuses reachable from each non-final status.
+var transitions = map[string][]string{
+	StatusPending:    {StatusProcessing, StatusPaid, StatusFailed, StatusCancelled},
+	StatusProcessing: {StatusPaid, StatusFailed},
+}
+
+// CanTransition reports whether a payout or batch may move from one status
+// to another.
+func CanTransition(from, to string) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Batch summarises a payout run.
+type Batch struct {
+	ID             string
+	EligibleBefore time.Time
+	Payouts        int
+}
+
+// payableEntries joins instructor_payable entries (e) to their transaction
+// (t) and, for refunds, the sale they reverse (s).
+const payableEntries = `
+	FROM ledger_entries e
+	JOIN ledger_transactions t ON t.id = e.transaction_id
+	LEFT JOIN ledger_transactions s ON s.order_item_id = t.order_item_id AND s.kind = 'sale'
+	WHERE e.account = 'instructor_payable'`
+
+// eligible is true for entries that can be paid out at cutoff $1: sales
+// older than cutoff, refunds of those sales and every payout posting.
+const eligible = `(t.payout_id IS NOT NULL OR COALESCE(s.created_at, t.created_at) < $1)`
+
+// eligibleBalances returns each instructor's eligible payable balance per
+// currency. Positive means the platform owes the instructor. Balances are
+// compared in minor units against the minimum for their currency, given as
+// the parallel arrays $2 (currency) and $3 (amount).
+const eligibleBalances = `
+	SELECT e.instructor_id, e.currency, -SUM(e.amount) AS balance` + payableEntries + `
+	  AND ` + eligible + `
+	GROUP BY e.instructor_id, e.currency
+	HAVING -SUM(e.amount) > 0
+	   AND minor_units(-SUM(e.amount), e.currency) >= COALESCE((
+	       SELECT m.amount FROM unnest($2::text[], $3::bigint[]) AS m(currency, amount)
+	       WHERE m.currency = e.currency), 0)`
+
+// Balance is what the platform owes one instructor in one currency.
+// Amounts are in minor units. Available is the part past the holding
+// period; the rest is on hold.
+type Balance struct {
+	Currency  string
+	Total     int64
+	Available int64
+}
+
+// OnHold is the part of the balance still inside the holding period.
+func (b Balance) OnHold() int64 {
+	return b.Total - b.Available
+}
+
+// Balances returns an instructor's current balances, one per currency.
+func Balances(ctx context.Context, db *sql.DB, instructorID string, hold time.Duration) ([]Balance, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT e.currency, minor_units(-SUM(e.amount), e.currency),
+		       minor_units(COALESCE(-SUM(e.amount) FILTER (WHERE `+eligible+`), 0), e.currency)`+payableEntries+`
+		  AND e.instructor_id = $2
+		GROUP BY e.currency
+		ORDER BY e.currency`, time.Now().Add(-hold), instructorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balances []Balance
+	for rows.Next() {
+		var b Balance
+		if err := rows.Scan(&b.Currency, &b.Total, &b.Available); err != nil {
+			return nil, err
+		}
+		balances = append(balances, b)
+	}
+	return balances, rows.Err()
+}
+
+// RunPayouts creates a batch paying every instructor whose eligible balance
+// reaches the minimum for its currency. minimums are in minor units keyed by
+// currency; a currency without an entry has no minimum. Earnings younger
+// than hold are left for a later run. It returns nil when nobody is due a
+// payout.
+func RunPayouts(ctx context.Context, db *sql.DB, hold time.Duration, minimums map[string]int64, createdBy *string) (*Batch, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", runLockKey); err != nil {
+		return nil, err
+	}
+
+	batch := &Batch{EligibleBefore: time.Now().Add(-hold)}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO payout_batches (eligible_before, created_by) VALUES ($1, $2) RETURNING id`,
+		batch.EligibleBefore, createdBy,
+	).Scan(&batch.ID); err != nil {
+		return nil, err
+	}
+
+	currencies := make([]string, 0, len(minimums))
+	amounts := make([]int64, 0, len(minimums))
+	for currency, amount := range minimums {
+		currencies = append(currencies, currency)
+		amounts = append(amounts, amount)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		WITH due AS (`+eligibleBalances+`)
+		INSERT INTO payouts (batch_id, instructor_id, amount, currency)
+		SELECT $4, instructor_id, balance, currency FROM due
+		RETURNING id`, batch.EligibleBefore, currencies, amounts, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	for _, id := range ids {
+		if err := post(ctx, tx, "payout", id, 1); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	batch.Payouts = len(ids)
+	return batch, nil
+}
+
+// SetPayoutStatus moves a single payout to status, reversing its ledger
+// posting when it fails or is cancelled.
+func SetPayoutStatus(ctx context.Context, db *sql.DB, payoutID, status string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM payouts WHERE id = $1 FOR UPDATE", payoutID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !CanTransition(current, status) {
+		return ErrInvalidTransition
+	}
+	if err := setPayout(ctx, tx, payoutID, status); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetBatchStatus moves a batch to status and every payout in it that is
+// not already final along with it.
+func SetBatchStatus(ctx context.Context, db *sql.DB, batchID, status string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM payout_batches WHERE id = $1 FOR UPDATE", batchID).Scan(&current)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !CanTransition(current, status) {
+		return ErrInvalidTransition
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, status FROM payouts WHERE batch_id = $1 ORDER BY id FOR UPDATE`, batchID)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for rows.Next() {
+		var id, s string
+		if err := rows.Scan(&id, &s); err != nil {
+			rows.Close()
+			return err
+		}
+		if CanTransition(s, status) {
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := setPayout(ctx, tx, id, status); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE payout_batches SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`,
+		batchID, status); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func setPayout(ctx context.Context, tx *sql.Tx, payoutID, status string) error {
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE payouts
+		SET status = $2,
+		    paid_at = CASE WHEN $2 = 'paid' THEN CURRENT_TIMESTAMP ELSE paid_at END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, payoutID, status); err != nil {
+		return err
+	}
+	if status == StatusFailed || status == StatusCancelled {
+		return post(ctx, tx, "payout_reversal", payoutID, -1)
+	}
+	return nil
+}
+
+// post records a payout (sign 1) or its reversal (sign -1): the instructor's
+// payable account is debited and cash credited by the payout amount.
+func post(ctx context.Context, tx *sql.Tx, kind, payoutID string, sign int) error {
+	var txID string
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO ledger_transactions (kind, payout_id) VALUES ($1, $2) RETURNING id`,
+		kind, payoutID,
+	).Scan(&txID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_entries (transaction_id, account, instructor_id, amount, currency)
+		SELECT $1, 'instructor_payable', instructor_id, $3 * amount, currency FROM payouts WHERE id = $2
+		UNION ALL
+		SELECT $1, 'cash', NULL, -$3 * amount, currency FROM payouts WHERE id = $2`,
+		txID, payoutID, sign)
+	return err
+}
@@ -0,0 +1,83 @@
+package ledger
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const instructorID = "5b7e2f0a-8c1d-4e3f-9a2b-6c4d8e0f1a2b"
+
+// arrayConverter lets sqlmock accept the slices that pgx binds as
+// PostgreSQL arrays.
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(v interface{}) (driver.Value, error) {
+	switch v.(type) {
+	case []string, []int64:
+		return v, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+func TestBalances(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Sums are converted to minor units in SQL, so USD comes back in cents.
+	mock.ExpectQuery(`minor_units\(-SUM\(e.amount\), e.currency\)`).WithArgs(sqlmock.AnyArg(), instructorID).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "total", "available"}).
+			AddRow("USD", 12345, 10000).
+			AddRow("VND", 700000, 700000))
+
+	balances, err := Balances(context.Background(), db, instructorID, 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Balance{{"USD", 12345, 10000}, {"VND", 700000, 700000}}
+	if len(balances) != len(want) {
+		t.Fatalf("balances = %+v", balances)
+	}
+	for i, b := range balances {
+		if b != want[i] {
+			t.Errorf("balance %d = %+v, want %+v", i, b, want[i])
+		}
+	}
+	if got := balances[0].OnHold(); got != 2345 {
+		t.Errorf("OnHold = %d, want 2345", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRunPayoutsMinimumPerCurrency(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs(runLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`INSERT INTO payout_batches`).WithArgs(sqlmock.AnyArg(), nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("batch-1"))
+	mock.ExpectQuery(`minor_units\(-SUM\(e.amount\), e.currency\) >= COALESCE`).
+		WithArgs(sqlmock.AnyArg(), []string{"USD"}, []int64{2000}, "batch-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	batch, err := RunPayouts(context.Background(), db, time.Hour, map[string]int64{"USD": 2000}, nil)
+	if err != nil || batch != nil {
+		t.Fatalf("RunPayouts = %+v, %v; want no batch", batch, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"net/http"
+	"runtime"
+
+	"internal/database"
+)
+
+// HTTP metrics, labelled by route template (c.FullPath()) rather than raw
+// path so that IDs in URLs do not explode cardinality.
+var (
+	HTTPRequestsTotal = NewCounterVec("http_requests_total",
+		"Total HTTP requests processed, by method, route template and status code.",
+		"method", "route", "status")
+	HTTPRequestDuration = NewHistogramVec("http_request_duration_seconds",
+		"HTTP request latency in seconds, by method and route template.",
+		DefBuckets, "method", "route")
+	HTTPRequestsInFlight = NewGaugeVec("http_requests_in_flight",
+		"HTTP requests currently being served.")
+	HTTPResponseSize = NewHistogramVec("http_response_size_bytes",
+		"HTTP response body size in bytes, by method and route template.",
+		[]float64{100, 1000, 10000, 100000, 1000000}, "method", "route")
+	RateLimitRejected = NewCounterVec("rate_limit_rejected_total",
+		"Requests rejected with 429, by rate limit policy.",
+		"policy")
+)
+
+// Business counters.
+var (
+	EnrollmentsCreated = NewCounterVec("enrollments_created_total",
+		"Enrollments created, by source (direct, order, voucher, organization).",
+		"source")
+	OrdersCompleted = NewCounterVec("orders_completed_total",
+		"Orders whose payment completed, by payment method.",
+		"payment_method")
+	RevenueTotal = NewCounterVec("revenue_total",
+		"Revenue from completed orders in the order currency's major unit.",
+		"currency")
+	CouponRedemptions = NewCounterVec("coupon_redemptions_total",
+		"Coupons redeemed by completed orders, by discount type.",
+		"discount_type")
+	QuizAttempts = NewCounterVec("quiz_attempts_total",
+		"Quiz lecture attempts saved as lecture progress, by result (completed, incomplete).",
+		"result")
+)
+
+var goGoroutines = NewGaugeFunc("go_goroutines", "Number of goroutines that currently exist.",
+	func() float64 { return float64(runtime.NumGoroutine()) })
+
+// RegisterPoolStats exposes connection pool statistics, read at scrape time.
+// Totals kept by the pool are counters; acquire counts are only registered
+// for pgxpool, the only driver that keeps them.
+func RegisterPoolStats(stats func() database.PoolStats) {
+	NewGaugeFunc("db_pool_max_open_connections", "Maximum number of open connections to the database.",
+		func() float64 { return float64(stats().MaxOpenConns) })
+	NewGaugeFunc("db_pool_open_connections", "Established connections, both in use and idle.",
+		func() float64 { return float64(stats().OpenConns) })
+	NewGaugeFunc("db_pool_in_use_connections", "Connections currently in use.",
+		func() float64 { return float64(stats().InUse) })
+	NewGaugeFunc("db_pool_idle_connections", "Idle connections.",
+		func() float64 { return float64(stats().Idle) })
+	NewCounterFunc("db_pool_waits_total", "Connections waited for because none was idle (empty acquires).",
+		func() float64 { return float64(stats().WaitCount) })
+	NewCounterFunc("db_pool_wait_duration_seconds_total", "Time spent acquiring connections.",
+		func() float64 { return stats().WaitDuration.Seconds() })
+	NewCounterFunc("db_pool_max_idle_closed_total", "Connections closed due to idle limits.",
+		func() float64 { return float64(stats().MaxIdleClosed) })
+	NewCounterFunc("db_pool_max_lifetime_closed_total", "Connections closed due to max lifetime.",
+		func() float64 { return float64(stats().MaxLifetimeClosed) })
+	if stats().Driver == "pgxpool" {
+		NewCounterFunc("db_pool_acquires_total", "Connections acquired from the pool.",
+			func() float64 { return float64(stats().AcquireCount) })
+		NewCounterFunc("db_pool_canceled_acquires_total", "Acquires canceled by their context.",
+			func() float64 { return float64(stats().CanceledAcquireCount) })
+	}
+}
+
+// Handler serves the default registry in Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Default.Write(w)
+	})
+}
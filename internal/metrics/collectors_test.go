@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"internal/database"
+)
+
+// Totals kept by the pool are exported as counters, so rate() works on them.
+func TestRegisterPoolStats(t *testing.T) {
+	RegisterPoolStats(func() database.PoolStats {
+		return database.PoolStats{Driver: "pgxpool", MaxOpenConns: 25, OpenConns: 4, InUse: 1, Idle: 3,
+			WaitCount: 7, WaitDuration: 1500 * time.Millisecond, AcquireCount: 120, CanceledAcquireCount: 2}
+	})
+	var b strings.Builder
+	Default.Write(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE db_pool_open_connections gauge\ndb_pool_open_connections 4\n",
+		"# TYPE db_pool_waits_total counter\ndb_pool_waits_total 7\n",
+		"# TYPE db_pool_wait_duration_seconds_total counter\ndb_pool_wait_duration_seconds_total 1.5\n",
+		"# TYPE db_pool_acquires_total counter\ndb_pool_acquires_total 120\n",
+		"# TYPE db_pool_canceled_acquires_total counter\ndb_pool_canceled_acquires_total 2\n",
+		"# TYPE quiz_attempts_total counter\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output lacks %q", want)
+		}
+	}
+}
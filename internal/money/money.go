@@ -0,0 +1,226 @@
+// Package money represents amounts as integer minor units of a currency so
+// prices, discounts and totals never pass through float64. The database keeps
+// DECIMAL columns in major units; queries convert with the minor_units and
+// from_minor_units SQL functions (migration 015), which use the same
+// exponents as the table below.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("money: unsupported currency")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrPrecision        = errors.New("money: more decimals than the currency allows")
+	ErrSyntax           = errors.New("money: invalid amount")
+)
+
+// Currency describes how amounts in a currency are stored and rounded.
+type Currency struct {
+	Code string
+	// Exponent is the number of decimals of the minor unit: 0 for VND
+	// (no minor unit), 2 for USD cents.
+	Exponent int
+}
+
+// Default is the currency of prices that do not name one.
+const Default = "VND"
+
+// currencies must agree with currency_exponent() in the database.
+var currencies = map[string]Currency{
+	"VND": {Code: "VND", Exponent: 0},
+	"USD": {Code: "USD", Exponent: 2},
+	"EUR": {Code: "EUR", Exponent: 2},
+}
+
+// Lookup returns a supported currency by its ISO 4217 code.
+func Lookup(code string) (Currency, error) {
+	cur, ok := currencies[code]
+	if !ok {
+		return Currency{}, fmt.Errorf("%w: %q", ErrUnknownCurrency, code)
+	}
+	return cur, nil
+}
+
+// Supported lists the supported currency codes in alphabetical order.
+func Supported() []string {
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// IsSupported reports whether code can be used for prices.
+func IsSupported(code string) bool {
+	_, ok := currencies[code]
+	return ok
+}
+
+// Money is an amount in minor units of Currency.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse reads a decimal in major units, e.g. "19.99" or "199000.00". Digits
+// past the currency's exponent must be zero; anything else is ErrPrecision
+// rather than a silent rounding.
+func Parse(s, currency string) (Money, error) {
+	cur, err := Lookup(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), ".")
+	if whole == "" || !digits(whole) || !digits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	if len(frac) > cur.Exponent {
+		if strings.Trim(frac[cur.Exponent:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q in %s", ErrPrecision, s, currency)
+		}
+		frac = frac[:cur.Exponent]
+	}
+	frac += strings.Repeat("0", cur.Exponent-len(frac))
+
+	amount, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrSyntax, s)
+	}
+	if neg {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func digits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) exponent() int {
+	return currencies[m.Currency].Exponent
+}
+
+// Decimal formats m in major units for SQL parameters: "19.99", "199000".
+func (m Money) Decimal() string {
+	exp := m.exponent()
+	s := strconv.FormatInt(abs(m.Amount), 10)
+	if exp > 0 {
+		if len(s) <= exp {
+			s = strings.Repeat("0", exp-len(s)+1) + s
+		}
+		s = s[:len(s)-exp] + "." + s[len(s)-exp:]
+	}
+	if m.Amount < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float64 returns m in major units. It is inexact and only meant for
+// reporting such as metrics; amounts are never computed with it.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(m.exponent())
+}
+
+// String formats m for messages: "19.99 USD".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) check(o Money) error {
+	if m.Currency != o.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than o.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.check(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul returns m * num / den rounded to the minor unit, halves away from
+// zero. This is the rounding rule of every currency and matches
+// round_money() in the database.
+func (m Money) Mul(num, den int64) Money {
+	if den == 0 {
+		panic("money: division by zero")
+	}
+	q := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	d := big.NewInt(den)
+	if d.Sign() < 0 {
+		q.Neg(q)
+		d.Neg(d)
+	}
+	sign := q.Sign()
+	r := new(big.Int)
+	q.QuoRem(q, d, r)
+	// QuoRem truncates; a remainder of at least half rounds away from zero
+	if r.Abs(r).Lsh(r, 1).Cmp(d) >= 0 {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	if !q.IsInt64() {
+		panic("money: amount overflows int64")
+	}
+	return Money{Amount: q.Int64(), Currency: m.Currency}
+}
+
+// Percent returns bp basis points (1/100 of a percent) of m, so 1250 is
+// 12.5%.
+func (m Money) Percent(bp int64) Money {
+	return m.Mul(bp, 10000)
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
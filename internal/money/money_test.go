@@ -0,0 +1,118 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, currency string
+		want         int64
+		err          error
+	}{
+		{"199000", "VND", 199000, nil},
+		{"199000.00", "VND", 199000, nil},
+		{"19.99", "USD", 1999, nil},
+		{"19.9", "USD", 1990, nil},
+		{"19.990", "USD", 1999, nil},
+		{" 7 ", "EUR", 700, nil},
+		{"-0.5", "USD", -50, nil},
+		{"19.5", "VND", 0, ErrPrecision},
+		{"1.999", "USD", 0, ErrPrecision},
+		{"", "USD", 0, ErrSyntax},
+		{".5", "USD", 0, ErrSyntax},
+		{"1e3", "USD", 0, ErrSyntax},
+		{"1,50", "EUR", 0, ErrSyntax},
+		{"99999999999999999999", "VND", 0, ErrSyntax},
+		{"10", "JPY", 0, ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.currency)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q, %s) error = %v, want %v", tt.in, tt.currency, err, tt.err)
+			continue
+		}
+		if err == nil && got != New(tt.want, tt.currency) {
+			t.Errorf("Parse(%q, %s) = %+v, want %d", tt.in, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(199000, "VND"), "199000"},
+		{New(-5000, "VND"), "-5000"},
+		{New(1999, "USD"), "19.99"},
+		{New(100, "USD"), "1.00"},
+		{New(5, "EUR"), "0.05"},
+		{New(0, "USD"), "0.00"},
+		{New(-1999, "USD"), "-19.99"},
+		{New(-5, "USD"), "-0.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Decimal(); got != tt.want {
+			t.Errorf("%+v.Decimal() = %q, want %q", tt.m, got, tt.want)
+		}
+		if back, err := Parse(tt.want, tt.m.Currency); err != nil || back != tt.m {
+			t.Errorf("Parse(%q) = %+v, %v; want round trip", tt.want, back, err)
+		}
+	}
+}
+
+func TestMulRoundsHalfAwayFromZero(t *testing.T) {
+	tests := []struct {
+		amount, num, den, want int64
+	}{
+		{100, 1, 3, 33},
+		{200, 1, 3, 67},
+		{5, 1, 2, 3},
+		{-5, 1, 2, -3},
+		{5, 1, -2, -3},
+		{15, 1, 10, 2},
+		{14, 1, 10, 1},
+		{-14, 1, 10, -1},
+		{-15, 1, 10, -2},
+		{199000, 7, 10, 139300},
+		{0, 1, 3, 0},
+	}
+	for _, tt := range tests {
+		if got := New(tt.amount, "VND").Mul(tt.num, tt.den).Amount; got != tt.want {
+			t.Errorf("%d * %d / %d = %d, want %d", tt.amount, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount, bp, want int64
+	}{
+		{999, 1250, 125}, // 124.875
+		{1999, 1000, 200},
+		{199000, 1500, 29850},
+		{100, 10000, 100},
+	}
+	for _, tt := range tests {
+		if got := New(tt.amount, "USD").Percent(tt.bp).Amount; got != tt.want {
+			t.Errorf("%d bp of %d = %d, want %d", tt.bp, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	if _, err := New(1, "USD").Add(New(1, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add across currencies: err = %v", err)
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	if got := New(1999, "USD").Float64(); got != 19.99 {
+		t.Errorf("1999 USD minor units = %v", got)
+	}
+	if got := New(199000, "VND").Float64(); got != 199000 {
+		t.Errorf("199000 VND = %v", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Package orders settles course orders once an admin has checked the
+// outcome with the payment provider. Orders, including gift purchases
+// (migration 017) and organization seat purchases (migration 019), are
+// created pending; Settle completes or fails them. A completed purchase
+// enrols the buyer in its courses, while gift vouchers and organization seats
+// simply become usable because they are read against the order's status. The
+// revenue ledger posts the sale from the orders trigger of migration 013.
+//
+// There is no verified callback from the provider, so each settlement is
+// kept in order_settlements with the admin who made it and why.
+package orders
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// Payment statuses. Completed, failed and refunded are final for Settle.
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+	StatusRefunded  = "refunded"
+)
+
+// Order kinds, by what the buyer gets once the order is paid.
+const (
+	KindPurchase = "purchase" // the buyer is enrolled
+	KindGift     = "gift"     // one voucher per seat
+	KindSeats    = "seats"    // seats of an organization
+)
+
+var (
+	ErrNotFound          = errors.New("orders: order not found")
+	ErrInvalidTransition = errors.New("orders: invalid payment status transition")
+	// ErrNoTransaction means a completion did not name the provider's
+	// transaction that paid the order.
+	ErrNoTransaction = errors.New("orders: completing an order needs its transaction ID")
+)
+
+// Settlement is an admin's record of a payment outcome. TransactionID is
+// the provider's reference for the payment and is required to complete an
+// order; Reason and SettledBy are kept for audit.
+type Settlement struct {
+	Status        string
+	TransactionID string
+	Reason        string
+	SettledBy     string
+}
+
+// Order is the result of Settle. FinalAmount is in minor units.
+type Order struct {
+	ID            string
+	UserID        string
+	Kind          string
+	PaymentStatus string
+	Currency      string
+	FinalAmount   int64
+	PaymentMethod string // empty when the provider did not report one
+	// CouponType is the discount type of the order's coupon, empty without one.
+	CouponType    string
+	TransactionID *string
+	// Enrolled counts the enrollments created for a completed purchase.
+	Enrolled int
+}
+
+// Settle records the outcome (completed or failed) of a pending order.
+func Settle(ctx context.Context, db *sql.DB, orderID string, s Settlement, now time.Time) (*Order, error) {
+	status, transactionID := s.Status, s.TransactionID
+	if status != StatusCompleted && status != StatusFailed {
+		return nil, ErrInvalidTransition
+	}
+	if status == StatusCompleted && transactionID == "" {
+		return nil, ErrNoTransaction
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order := Order{ID: orderID}
+	var isGift, forOrganization bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT user_id, payment_status, is_gift, organization_id IS NOT NULL,
+		       currency, minor_units(final_amount, currency), COALESCE(payment_method, ''),
+		       COALESCE((SELECT discount_type FROM coupons WHERE id = coupon_id), '')
+		FROM orders
+		WHERE id = $1
+		FOR UPDATE
+	`, orderID).Scan(&order.UserID, &order.PaymentStatus, &isGift, &forOrganization, &order.Currency, &order.FinalAmount,
+		&order.PaymentMethod, &order.CouponType)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if order.PaymentStatus != StatusPending {
+		return nil, ErrInvalidTransition
+	}
+	switch {
+	case isGift:
+		order.Kind = KindGift
+	case forOrganization:
+		order.Kind = KindSeats
+	default:
+		order.Kind = KindPurchase
+	}
+
+	txID := sql.NullString{String: transactionID, Valid: transactionID != ""}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE orders SET payment_status = $2, transaction_id = $3, updated_at = $4 WHERE id = $1
+	`, orderID, status, txID, now); err != nil {
+		return nil, err
+	}
+	order.PaymentStatus = status
+	if txID.Valid {
+		order.TransactionID = &transactionID
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO order_settlements (order_id, status, transaction_id, reason, settled_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, orderID, status, txID, s.Reason, s.SettledBy, now); err != nil {
+		return nil, err
+	}
+
+	if status == StatusCompleted && order.Kind == KindPurchase {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO enrollments (user_id, course_id, enrolled_at)
+			SELECT DISTINCT $2::uuid, course_id, $3::timestamptz FROM order_items WHERE order_id = $1
+			ON CONFLICT (user_id, course_id) DO NOTHING
+		`, orderID, order.UserID, now)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		order.Enrolled = int(n)
+	}
+	return &order, tx.Commit()
+}
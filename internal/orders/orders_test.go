@@ -0,0 +1,116 @@
+package orders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	orderID = "7e6d5c4b-3a29-4180-9f7e-6d5c4b3a2918"
+	buyerID = "6f1c2b1e-1d5b-4c1a-9a57-3f0d6f2d8c11"
+	adminID = "2b8e4c1d-6a3f-4e5b-8c7d-9f0a1b2c3d4e"
+	reason  = "Đã đối soát với sao kê MoMo"
+)
+
+func expectOrder(mock sqlmock.Sqlmock, status string, isGift, forOrganization bool) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM orders\s+WHERE id = \$1\s+FOR UPDATE`).WithArgs(orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "payment_status", "is_gift", "for_organization", "currency", "final_amount", "payment_method", "coupon_type"}).
+			AddRow(buyerID, status, isGift, forOrganization, "VND", 1299000, "momo", "percentage"))
+}
+
+func TestSettle(t *testing.T) {
+	now := time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		status          string
+		isGift, forOrg  bool
+		wantKind        string
+		wantEnrollments int
+	}{
+		{name: "paid purchase enrols the buyer", status: StatusCompleted, wantKind: KindPurchase, wantEnrollments: 2},
+		{name: "paid gift order", status: StatusCompleted, isGift: true, wantKind: KindGift},
+		{name: "paid seat order", status: StatusCompleted, forOrg: true, wantKind: KindSeats},
+		{name: "failed purchase", status: StatusFailed, wantKind: KindPurchase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			expectOrder(mock, StatusPending, tt.isGift, tt.forOrg)
+			mock.ExpectExec(`UPDATE orders SET payment_status = \$2`).
+				WithArgs(orderID, tt.status, "TXN-1", now).WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectExec(`INSERT INTO order_settlements`).
+				WithArgs(orderID, tt.status, "TXN-1", reason, adminID, now).WillReturnResult(sqlmock.NewResult(0, 1))
+			if tt.wantEnrollments > 0 {
+				mock.ExpectExec(`INSERT INTO enrollments`).WithArgs(orderID, buyerID, now).
+					WillReturnResult(sqlmock.NewResult(0, int64(tt.wantEnrollments)))
+			}
+			mock.ExpectCommit()
+
+			order, err := Settle(context.Background(), db, orderID,
+				Settlement{Status: tt.status, TransactionID: "TXN-1", Reason: reason, SettledBy: adminID}, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order.Kind != tt.wantKind || order.PaymentStatus != tt.status || order.Enrolled != tt.wantEnrollments {
+				t.Errorf("order = %+v", order)
+			}
+			if order.FinalAmount != 1299000 || order.TransactionID == nil || *order.TransactionID != "TXN-1" {
+				t.Errorf("amount = %d, transaction = %v", order.FinalAmount, order.TransactionID)
+			}
+			if order.PaymentMethod != "momo" || order.CouponType != "percentage" {
+				t.Errorf("payment method = %q, coupon type = %q", order.PaymentMethod, order.CouponType)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSettleRejects(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx, now := context.Background(), time.Now()
+
+	settle := func(status, transactionID string) error {
+		_, err := Settle(ctx, db, orderID, Settlement{Status: status, TransactionID: transactionID, Reason: reason, SettledBy: adminID}, now)
+		return err
+	}
+
+	if err := settle(StatusRefunded, ""); err != ErrInvalidTransition {
+		t.Errorf("refund through Settle: err = %v", err)
+	}
+	if err := settle(StatusCompleted, ""); err != ErrNoTransaction {
+		t.Errorf("completion without a transaction: err = %v", err)
+	}
+
+	expectOrder(mock, StatusCompleted, true, false)
+	mock.ExpectRollback()
+	if err := settle(StatusFailed, ""); err != ErrInvalidTransition {
+		t.Errorf("settling a completed order: err = %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM orders`).WithArgs(orderID).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectRollback()
+	if err := settle(StatusCompleted, "TXN-1"); err != ErrNotFound {
+		t.Errorf("missing order: err = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
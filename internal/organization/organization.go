@@ -0,0 +1,98 @@
+// Package organization holds the rules of organization accounts. Members
+// have one role per organization; owners run the organization and buy seats,
+// managers enrol learners and see their progress, and learners only see the
+// courses assigned to them. Seats are bought per course through an order
+// (migration 019) and become usable once orders.Settle completes the order;
+// assigning a course to a member who is not enrolled yet uses one seat and
+// enrols them.
+package organization
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// Member roles, most privileged first.
+const (
+	RoleOwner   = "owner"
+	RoleManager = "manager"
+	RoleLearner = "learner"
+)
+
+// Seat pool statuses as reported to the organization. SeatPoolStatus
+// computes them in SQL.
+const (
+	SeatsPendingPayment = "pending_payment"
+	SeatsAvailable      = "available"
+	SeatsCancelled      = "cancelled" // the order failed or was refunded
+)
+
+// SeatPoolStatus is a SQL expression for the status of seat pool p of order o.
+const SeatPoolStatus = `
+	CASE WHEN o.payment_status = 'pending' THEN 'pending_payment'
+	     WHEN o.payment_status <> 'completed' THEN 'cancelled'
+	     ELSE 'available' END`
+
+// Assignment statuses, from the learner's enrollment.
+const (
+	AssignmentNotStarted = "not_started"
+	AssignmentInProgress = "in_progress"
+	AssignmentCompleted  = "completed"
+)
+
+// AssignmentStatus is a SQL expression for the status of an assignment whose
+// learner's enrollment is e (LEFT JOINed on user and course).
+const AssignmentStatus = `
+	CASE WHEN e.completed_at IS NOT NULL OR e.progress_percentage >= 100 THEN 'completed'
+	     WHEN COALESCE(e.progress_percentage, 0) > 0 THEN 'in_progress'
+	     ELSE 'not_started' END`
+
+// Overdue is a SQL condition for assignment a with enrollment e that is not
+// completed by its due date, in Vietnam time.
+const Overdue = `(a.due_date IS NOT NULL AND a.due_date < (now() AT TIME ZONE 'Asia/Ho_Chi_Minh')::date
+	AND e.completed_at IS NULL AND COALESCE(e.progress_percentage, 0) < 100)`
+
+// ErrNoSeats is returned by TakeSeat when no paid seat of the course is left.
+var ErrNoSeats = errors.New("organization: no seats available")
+
+var rank = map[string]int{RoleLearner: 1, RoleManager: 2, RoleOwner: 3}
+
+// AtLeast reports whether role grants at least the rights of min.
+func AtLeast(role, min string) bool {
+	return rank[role] >= rank[min]
+}
+
+// CanManage reports whether a member with role may add, change or remove
+// members with role target: owners manage everyone, managers only learners.
+func CanManage(role, target string) bool {
+	if role == RoleOwner {
+		return true
+	}
+	return role == RoleManager && target == RoleLearner
+}
+
+// TakeSeat uses one paid seat of the course, oldest order first, and
+// returns its pool. It must run in the transaction that creates the
+// enrollment so the seat is given back if that fails.
+func TakeSeat(ctx context.Context, tx *sql.Tx, organizationID, courseID string) (string, error) {
+	var poolID string
+	err := tx.QueryRowContext(ctx, `
+		UPDATE organization_seat_pools
+		SET seats_used = seats_used + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT p.id
+			FROM organization_seat_pools p
+			JOIN orders o ON o.id = p.order_id
+			WHERE p.organization_id = $1 AND p.course_id = $2
+			  AND o.payment_status = 'completed' AND p.seats_used < p.seats
+			ORDER BY p.created_at, p.id
+			LIMIT 1
+			FOR UPDATE OF p
+		)
+		RETURNING id`, organizationID, courseID).Scan(&poolID)
+	if err == sql.ErrNoRows {
+		return "", ErrNoSeats
+	}
+	return poolID, err
+}
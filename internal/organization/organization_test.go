@@ -0,0 +1,74 @@
+package organization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	orgID    = "4d3c2b1a-0f9e-4d8c-b7a6-958473625140"
+	courseID = "0b8e4c3a-2f1d-4e5a-8b7c-9d0e1f2a3b4c"
+	poolID   = "1a2b3c4d-5e6f-4071-8293-a4b5c6d7e8f9"
+)
+
+func TestTakeSeat(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	// Only pools of completed orders are candidates.
+	mock.ExpectQuery(`o.payment_status = 'completed' AND p.seats_used < p.seats`).WithArgs(orgID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(poolID))
+	mock.ExpectQuery(`UPDATE organization_seat_pools`).WithArgs(orgID, courseID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if got, err := TakeSeat(ctx, tx, orgID, courseID); err != nil || got != poolID {
+		t.Fatalf("TakeSeat = %q, %v; want %q", got, err, poolID)
+	}
+	if _, err := TakeSeat(ctx, tx, orgID, courseID); err != ErrNoSeats {
+		t.Fatalf("TakeSeat without paid seats: err = %v, want ErrNoSeats", err)
+	}
+	tx.Rollback()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		role, target string
+		atLeast      bool
+		canManage    bool
+	}{
+		{RoleOwner, RoleOwner, true, true},
+		{RoleOwner, RoleLearner, true, true},
+		{RoleManager, RoleOwner, false, false},
+		{RoleManager, RoleManager, true, false},
+		{RoleManager, RoleLearner, true, true},
+		{RoleLearner, RoleLearner, true, false},
+		{RoleLearner, RoleManager, false, false},
+		{"", RoleLearner, false, false},
+	}
+	for _, tt := range tests {
+		if got := AtLeast(tt.role, tt.target); got != tt.atLeast {
+			t.Errorf("AtLeast(%q, %q) = %v", tt.role, tt.target, got)
+		}
+		if got := CanManage(tt.role, tt.target); got != tt.canManage {
+			t.Errorf("CanManage(%q, %q) = %v", tt.role, tt.target, got)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package pricing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{10, []int64{2, 8}, []int64{2, 8}},
+		{10, []int64{1, 1, 1}, []int64{4, 3, 3}},
+		{7, []int64{5, 3, 2}, []int64{4, 2, 1}},
+		{100, []int64{199000, 99000}, []int64{67, 33}},
+		{0, []int64{5, 5}, []int64{0, 0}},
+		{50, []int64{0, 0}, []int64{0, 0}},
+		{30, []int64{0, 30}, []int64{0, 30}},
+		{5, []int64{}, []int64{}},
+	}
+	for _, tt := range tests {
+		got := allocate(tt.amount, tt.weights)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("allocate(%d, %v) = %v, want %v", tt.amount, tt.weights, got, tt.want)
+		}
+		total := weightSum(tt.weights)
+		var sum int64
+		for i, share := range got {
+			sum += share
+			// No share exceeds its weight while the amount fits the weights.
+			if tt.amount <= total && share > tt.weights[i] {
+				t.Errorf("allocate(%d, %v): share %d exceeds its weight", tt.amount, tt.weights, share)
+			}
+		}
+		if total > 0 && sum != tt.amount {
+			t.Errorf("allocate(%d, %v) adds up to %d", tt.amount, tt.weights, sum)
+		}
+	}
+}
+
+func weightSum(weights []int64) int64 {
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	return sum
+}
@@ -0,0 +1,234 @@
+// Package pricing computes what a buyer pays for courses: the list price,
+// the sale price after the best running promotion and how a coupon combines
+// with promotions. Promotion selection lives in the course_effective_price
+// SQL function (migration 016) so course listings and checkout agree; this
+// package loads its result and applies coupons according to each
+// promotion's coupon policy.
+package pricing
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"sort"
+	"time"
+
+	"internal/money"
+)
+
+// Coupon policies of a promotion.
+const (
+	// PolicyStack applies the coupon on top of the promotion price.
+	PolicyStack = "stack"
+	// PolicyExclusive keeps coupons off courses on promotion.
+	PolicyExclusive = "exclusive"
+	// PolicyBestOf gives the buyer the lower of the promotion price and the
+	// regular price less the coupon.
+	PolicyBestOf = "best_of"
+)
+
+// Policies lists the valid coupon policies.
+var Policies = []string{PolicyStack, PolicyExclusive, PolicyBestOf}
+
+// Promotion is the promotion that set a line's price.
+type Promotion struct {
+	ID           string
+	Name         string
+	EndsAt       time.Time
+	CouponPolicy string
+}
+
+// Line is one course priced in one currency.
+type Line struct {
+	CourseID string
+	Title    string
+	Currency string
+	// List is the catalogue price, Regular the price without promotions
+	// (the course's discount price when set) and Price the sale price.
+	List    money.Money
+	Regular money.Money
+	Price   money.Money
+	// Promotion is nil when no promotion beats the regular price.
+	Promotion *Promotion
+	// CouponDiscount is the part of the order coupon taken off this line.
+	CouponDiscount money.Money
+}
+
+// Total is what the buyer pays for the line.
+func (l Line) Total() money.Money {
+	return money.New(l.Price.Amount-l.CouponDiscount.Amount, l.Currency)
+}
+
+// Load prices the courses at time at in currency. Courses without a price in
+// currency keep their base currency, so callers that need one currency must
+// check Line.Currency. An empty currency means each course's base currency.
+// Unknown course IDs are left out of the result.
+func Load(ctx context.Context, db *sql.DB, courseIDs []string, currency string, at time.Time) (map[string]Line, error) {
+	lines := make(map[string]Line, len(courseIDs))
+	if len(courseIDs) == 0 {
+		return lines, nil
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.id, c.title, ep.currency,
+		       minor_units(ep.list_price, ep.currency), minor_units(ep.regular_price, ep.currency),
+		       minor_units(ep.price, ep.currency),
+		       p.id, p.name, p.ends_at, p.coupon_policy
+		FROM courses c
+		CROSS JOIN LATERAL course_effective_price(c.id, $2, $3) ep
+		LEFT JOIN promotions p ON p.id = ep.promotion_id
+		WHERE c.id = ANY($1)
+	`, courseIDs, sql.NullString{String: currency, Valid: currency != ""}, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l Line
+		var promoID, promoName, policy sql.NullString
+		var endsAt sql.NullTime
+		if err := rows.Scan(&l.CourseID, &l.Title, &l.Currency,
+			&l.List.Amount, &l.Regular.Amount, &l.Price.Amount,
+			&promoID, &promoName, &endsAt, &policy); err != nil {
+			return nil, err
+		}
+		l.List.Currency, l.Regular.Currency, l.Price.Currency = l.Currency, l.Currency, l.Currency
+		l.CouponDiscount = money.New(0, l.Currency)
+		if promoID.Valid {
+			l.Promotion = &Promotion{
+				ID:           promoID.String,
+				Name:         promoName.String,
+				EndsAt:       endsAt.Time,
+				CouponPolicy: policy.String,
+			}
+		}
+		lines[l.CourseID] = l
+	}
+	return lines, rows.Err()
+}
+
+// Coupon is an order coupon. Value is basis points for percentage coupons
+// and minor units of the order currency for fixed ones.
+type Coupon struct {
+	DiscountType string // "percentage" or "fixed"
+	Value        int64
+	// CourseID limits the coupon to one course; empty for the whole order.
+	CourseID string
+}
+
+// Subtotal sums the sale prices of lines, before any coupon.
+func Subtotal(lines []Line) int64 {
+	var total int64
+	for _, l := range lines {
+		total += l.Price.Amount
+	}
+	return total
+}
+
+// couponBase is the amount of a line the coupon is computed on: the sale
+// price, the regular price for best_of promotions and nothing for exclusive
+// ones or courses outside the coupon's course.
+func couponBase(l Line, c Coupon) int64 {
+	if c.CourseID != "" && l.CourseID != c.CourseID {
+		return 0
+	}
+	if l.Promotion == nil {
+		return l.Price.Amount
+	}
+	switch l.Promotion.CouponPolicy {
+	case PolicyExclusive:
+		return 0
+	case PolicyBestOf:
+		return l.Regular.Amount
+	}
+	return l.Price.Amount
+}
+
+// Eligible reports whether c can discount any of lines.
+func Eligible(lines []Line, c Coupon) bool {
+	for _, l := range lines {
+		if couponBase(l, c) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyCoupon sets CouponDiscount on lines, which must share one currency.
+// A percentage coupon discounts every eligible line; a fixed coupon is split
+// across them in proportion to their base. On a best_of line the buyer keeps
+// the promotion when it is cheaper than the regular price less the coupon
+// share, and that share is forfeited; otherwise the promotion is dropped.
+func ApplyCoupon(lines []Line, c Coupon) {
+	bases := make([]int64, len(lines))
+	var eligible int64
+	for i, l := range lines {
+		bases[i] = couponBase(l, c)
+		eligible += bases[i]
+	}
+	if eligible == 0 {
+		return
+	}
+
+	var shares []int64
+	if c.DiscountType == "percentage" {
+		shares = make([]int64, len(lines))
+		for i, l := range lines {
+			shares[i] = money.New(bases[i], l.Currency).Percent(c.Value).Amount
+		}
+	} else {
+		amount := c.Value
+		if amount > eligible {
+			amount = eligible
+		}
+		shares = allocate(amount, bases)
+	}
+
+	for i := range lines {
+		l := &lines[i]
+		if bases[i] == 0 {
+			continue
+		}
+		if l.Promotion != nil && l.Promotion.CouponPolicy == PolicyBestOf {
+			if l.Regular.Amount-shares[i] >= l.Price.Amount {
+				continue
+			}
+			l.Price, l.Promotion = l.Regular, nil
+		}
+		l.CouponDiscount = money.New(shares[i], l.Currency)
+	}
+}
+
+// allocate splits amount across weights by the largest remainder method, so
+// the shares add up to amount exactly and none exceeds its weight when
+// amount is at most the sum of weights.
+func allocate(amount int64, weights []int64) []int64 {
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	shares := make([]int64, len(weights))
+	if sum == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		rem   *big.Int
+	}
+	rems := make([]remainder, 0, len(weights))
+	total, left := big.NewInt(sum), amount
+	for i, w := range weights {
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(big.NewInt(amount), big.NewInt(w)), total, new(big.Int))
+		shares[i] = q.Int64()
+		left -= shares[i]
+		rems = append(rems, remainder{i, r})
+	}
+	sort.SliceStable(rems, func(a, b int) bool { return rems[a].rem.Cmp(rems[b].rem) > 0 })
+	for k := 0; left > 0; k++ {
+		shares[rems[k].index]++
+		left--
+	}
+	return shares
+}
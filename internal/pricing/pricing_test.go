@@ -0,0 +1,49 @@
+package pricing
+
+import (
+	"testing"
+
+	"internal/money"
+)
+
+func line(courseID string, price int64, promotion *Promotion) Line {
+	p := money.New(price, "VND")
+	return Line{CourseID: courseID, Currency: "VND", List: p, Regular: p, Price: p, Promotion: promotion,
+		CouponDiscount: money.New(0, "VND")}
+}
+
+func TestApplyCoupon(t *testing.T) {
+	exclusive := &Promotion{CouponPolicy: PolicyExclusive}
+	tests := []struct {
+		name   string
+		coupon Coupon
+		lines  []Line
+		want   []int64
+	}{
+		{"percentage on every line", Coupon{DiscountType: "percentage", Value: 1000},
+			[]Line{line("a", 200000, nil), line("b", 100000, nil)}, []int64{20000, 10000}},
+		{"fixed split by price", Coupon{DiscountType: "fixed", Value: 30000},
+			[]Line{line("a", 200000, nil), line("b", 100000, nil)}, []int64{20000, 10000}},
+		{"exclusive promotion keeps the coupon off", Coupon{DiscountType: "fixed", Value: 30000},
+			[]Line{line("a", 200000, exclusive), line("b", 100000, nil)}, []int64{0, 30000}},
+		{"course coupon only discounts its course", Coupon{DiscountType: "percentage", Value: 1000, CourseID: "b"},
+			[]Line{line("a", 200000, nil), line("b", 100000, nil)}, []int64{0, 10000}},
+		{"fixed course coupon is capped at its course", Coupon{DiscountType: "fixed", Value: 150000, CourseID: "b"},
+			[]Line{line("a", 200000, nil), line("b", 100000, nil)}, []int64{0, 100000}},
+	}
+	for _, tt := range tests {
+		if got := Eligible(tt.lines, tt.coupon); !got {
+			t.Errorf("%s: not eligible", tt.name)
+		}
+		ApplyCoupon(tt.lines, tt.coupon)
+		for i, l := range tt.lines {
+			if l.CouponDiscount.Amount != tt.want[i] {
+				t.Errorf("%s: line %s discount = %d, want %d", tt.name, l.CourseID, l.CouponDiscount.Amount, tt.want[i])
+			}
+		}
+	}
+
+	if Eligible([]Line{line("a", 200000, nil)}, Coupon{DiscountType: "fixed", Value: 1000, CourseID: "b"}) {
+		t.Error("course coupon eligible for an order without its course")
+	}
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreClockGoingBackwards(t *testing.T) {
+	s := NewMemoryStore(NewManualClock(start))
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		if _, err := s.Take(ctx, "k", perSecond, start); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A request stamped earlier (another goroutine read the clock first)
+	// neither refills the bucket nor moves its refill point back.
+	res, err := s.Take(ctx, "k", perSecond, start.Add(-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed {
+		t.Errorf("earlier request allowed: %+v", res)
+	}
+	if res, _ := s.Take(ctx, "k", perSecond, start.Add(time.Second)); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("one second later: %+v", res)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s := NewMemoryStore(NewManualClock(start))
+	ctx := context.Background()
+	now := start.Add(sweepInterval)
+
+	s.Take(ctx, "idle", perSecond, start)
+	// Emptied two seconds before the sweep, so still refilling during it.
+	for i := 0; i < 5; i++ {
+		s.Take(ctx, "busy", perSecond, now.Add(-2*time.Second))
+	}
+
+	s.Take(ctx, "other", perSecond, now)
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("refilled bucket was not swept")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Error("refilling bucket was swept")
+	}
+
+	// Dropping a full bucket loses nothing: it comes back full.
+	if res, _ := s.Take(ctx, "idle", perSecond, now); !res.Allowed || res.Remaining != 4 {
+		t.Errorf("swept bucket: %+v", res)
+	}
+	if res, _ := s.Take(ctx, "busy", perSecond, now); !res.Allowed || res.Remaining != 1 {
+		t.Errorf("kept bucket: %+v", res)
+	}
+}
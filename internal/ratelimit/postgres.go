@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// bucketRetention is how long an untouched bucket is kept in the shared
+// table. It only needs to exceed the longest configured refill time.
+const bucketRetention = 24 * time.Hour
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every API
+// instance enforces the same limits. Each Take is a single upsert, so
+// concurrent requests for one key serialize on its row.
+type PostgresStore struct {
+	db *sql.DB
+	mu sync.Mutex
+	// lastSweep throttles the cleanup of idle buckets.
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *sql.DB, clock Clock) *PostgresStore {
+	return &PostgresStore{db: db, lastSweep: clock.Now()}
+}
+
+// takeQuery refills the bucket up to $3 and takes one token when a whole
+// one is available. Arguments: key, burst, now, tokens per second.
+const takeQuery = `
+	-- name: RateLimitTake
+	INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+	VALUES ($1, $2::double precision - 1, TRUE, $3)
+	ON CONFLICT (key) DO UPDATE SET
+		(tokens, allowed) = (
+			SELECT CASE WHEN level >= 1 THEN level - 1 ELSE level END, level >= 1
+			FROM (
+				SELECT LEAST($2::double precision,
+					b.tokens + GREATEST(EXTRACT(EPOCH FROM ($3 - b.updated_at))::double precision, 0) * $4) AS level
+			) refilled
+		),
+		updated_at = GREATEST(b.updated_at, $3)
+	RETURNING tokens, allowed`
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error) {
+	s.maybeSweep(now)
+
+	var tokens float64
+	var allowed bool
+	err := s.db.QueryRowContext(ctx, takeQuery, key, p.Burst, now, p.rate()).Scan(&tokens, &allowed)
+	if err != nil {
+		return Result{}, err
+	}
+	return p.result(allowed, tokens), nil
+}
+
+// maybeSweep deletes idle buckets in the background at most once per
+// sweepInterval per instance.
+func (s *PostgresStore) maybeSweep(now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := s.db.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < $1", now.Add(-bucketRetention))
+		if err != nil {
+			logrus.WithError(err).Warn("rate limit: failed to sweep idle buckets")
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+// Package ratelimit implements token-bucket rate limiting with pluggable
+// storage: an in-process map for single instances and a PostgreSQL table
+// shared by every instance of the API.
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"internal/config"
+)
+
+// Key strategies for Policy.KeyBy.
+const (
+	KeyByIP   = "ip"
+	KeyByUser = "user"
+)
+
+// Policy is a token bucket. Burst tokens fit in the bucket and Requests
+// tokens are added back evenly over Period; each request takes one.
+type Policy struct {
+	Name     string
+	Requests int
+	Period   time.Duration
+	Burst    int
+	KeyBy    string
+}
+
+// PolicyFromConfig names a configured policy.
+func PolicyFromConfig(name string, p config.RateLimitPolicy) Policy {
+	return Policy{Name: name, Requests: p.Requests, Period: p.Period, Burst: p.Burst, KeyBy: p.KeyBy}
+}
+
+// rate is the refill speed in tokens per second.
+func (p Policy) rate() float64 {
+	return float64(p.Requests) / p.Period.Seconds()
+}
+
+// Result is the outcome of one Take, in the units of the RateLimit headers.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request would be allowed; zero
+	// when Allowed.
+	RetryAfter time.Duration
+}
+
+// result derives the header values from the bucket level after a Take.
+func (p Policy) result(allowed bool, tokens float64) Result {
+	r := Result{
+		Allowed:   allowed,
+		Limit:     p.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(p.Burst) - tokens) / p.rate()),
+	}
+	if r.Remaining < 0 {
+		r.Remaining = 0
+	}
+	if !allowed {
+		r.RetryAfter = secondsToDuration((1 - tokens) / p.rate())
+	}
+	return r
+}
+
+func secondsToDuration(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// refill returns the bucket level at now, given the level at last.
+func (p Policy) refill(tokens float64, last, now time.Time) float64 {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens += elapsed * p.rate()
+	}
+	return math.Min(tokens, float64(p.Burst))
+}
+
+// Store keeps bucket state. Take refills the bucket identified by key up to
+// now and, if a whole token is available, removes it.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy, now time.Time) (Result, error)
+}
+
+// Limiter applies policies against a store using clock for the current time.
+type Limiter struct {
+	store Store
+	clock Clock
+}
+
+func New(store Store, clock Clock) *Limiter {
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	return &Limiter{store: store, clock: clock}
+}
+
+// NewFromConfig builds a limiter for the configured backend.
+func NewFromConfig(cfg config.RateLimitConfig, db *sql.DB) *Limiter {
+	clock := SystemClock{}
+	if cfg.Backend == "postgres" {
+		return New(NewPostgresStore(db, clock), clock)
+	}
+	return New(NewMemoryStore(clock), clock)
+}
+
+// Allow takes a token for key under policy p.
+func (l *Limiter) Allow(ctx context.Context, p Policy, key string) (Result, error) {
+	return l.store.Take(ctx, p.Name+":"+key, p, l.clock.Now())
+}
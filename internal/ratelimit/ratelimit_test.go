@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// perSecond refills one token a second into a bucket of five.
+var perSecond = Policy{Name: "test", Requests: 60, Period: time.Minute, Burst: 5, KeyBy: KeyByIP}
+
+var start = time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func TestRefill(t *testing.T) {
+	tests := []struct {
+		name    string
+		tokens  float64
+		elapsed time.Duration
+		want    float64
+	}{
+		{"no time passed", 2, 0, 2},
+		{"one token a second", 0, 3 * time.Second, 3},
+		{"fractions accumulate", 0.5, 250 * time.Millisecond, 0.75},
+		{"capped at burst", 1, time.Hour, 5},
+		{"clock going backwards", 2, -time.Second, 2},
+	}
+	for _, tt := range tests {
+		if got := perSecond.refill(tt.tokens, start, start.Add(tt.elapsed)); got != tt.want {
+			t.Errorf("%s: refill = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed bool
+		tokens  float64
+		want    Result
+	}{
+		{"full bucket", true, 4, Result{Allowed: true, Limit: 5, Remaining: 4, Reset: time.Second}},
+		{"partial token", true, 2.5, Result{Allowed: true, Limit: 5, Remaining: 2, Reset: 2500 * time.Millisecond}},
+		{"empty bucket", false, 0, Result{Limit: 5, Reset: 5 * time.Second, RetryAfter: time.Second}},
+		{"almost a token", false, 0.75, Result{Limit: 5, Reset: 4250 * time.Millisecond, RetryAfter: 250 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		if got := perSecond.result(tt.allowed, tt.tokens); got != tt.want {
+			t.Errorf("%s: result = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLimiterBurstAndRefill(t *testing.T) {
+	clock := NewManualClock(start)
+	l := New(NewMemoryStore(clock), clock)
+	ctx := context.Background()
+
+	allow := func(key string) Result {
+		t.Helper()
+		res, err := l.Allow(ctx, perSecond, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// The whole burst is available at once.
+	for i := 4; i >= 0; i-- {
+		if res := allow("1.2.3.4"); !res.Allowed || res.Remaining != i {
+			t.Fatalf("request %d: %+v", 5-i, res)
+		}
+	}
+	res := allow("1.2.3.4")
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 5*time.Second {
+		t.Fatalf("request over the burst: %+v", res)
+	}
+
+	// Other keys have their own bucket.
+	if res := allow("5.6.7.8"); !res.Allowed || res.Remaining != 4 {
+		t.Errorf("other key: %+v", res)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if res := allow("1.2.3.4"); res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("half a token: %+v", res)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if res := allow("1.2.3.4"); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after one second: %+v", res)
+	}
+
+	// An idle bucket refills to the burst, not beyond.
+	clock.Advance(time.Hour)
+	if res := allow("1.2.3.4"); !res.Allowed || res.Remaining != 4 {
+		t.Errorf("after an hour: %+v", res)
+	}
+}
+
+func TestPostgresStoreTake(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`INSERT INTO rate_limit_buckets`).WithArgs("test:1.2.3.4", 5, start, 1.0).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(2.5, true))
+	mock.ExpectQuery(`INSERT INTO rate_limit_buckets`).WithArgs("test:1.2.3.4", 5, start, 1.0).
+		WillReturnRows(sqlmock.NewRows([]string{"tokens", "allowed"}).AddRow(0.25, false))
+
+	clock := NewManualClock(start)
+	l := New(NewPostgresStore(db, clock), clock)
+	res, err := l.Allow(context.Background(), perSecond, "1.2.3.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Allowed: true, Limit: 5, Remaining: 2, Reset: 2500 * time.Millisecond}); res != want {
+		t.Errorf("allowed: %+v, want %+v", res, want)
+	}
+	res, err = l.Allow(context.Background(), perSecond, "1.2.3.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Allowed || res.RetryAfter != 750*time.Millisecond {
+		t.Errorf("rejected: %+v", res)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package slug builds URL slugs from titles. Vietnamese text is
+// transliterated to ASCII ("Lập trình Go cơ bản" -> "lap-trinh-go-co-ban")
+// and Unique appends -2, -3, ... until the slug is free.
+package slug
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fallback is used when a title has no letters or digits to build on.
+const Fallback = "untitled"
+
+// suffixRoom is reserved at the end of a truncated slug for "-N".
+const suffixRoom = len("-9999")
+
+// Querier is satisfied by *sql.DB and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// stripMarks decomposes characters and drops the combining marks, which
+// removes Vietnamese tone and vowel marks (ế -> e, ư -> u).
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// Make returns the slug of s: lower-case ASCII letters and digits separated
+// by single hyphens, at most maxLen bytes (0 means no limit). Characters
+// that cannot be transliterated are dropped.
+func Make(s string, maxLen int) string {
+	s = strings.NewReplacer("đ", "d", "Đ", "D").Replace(s)
+	if t, _, err := transform.String(stripMarks, s); err == nil {
+		s = t
+	}
+
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			// Non-Latin script without an ASCII form; drop it.
+		default:
+			pendingHyphen = true
+		}
+	}
+	return truncate(b.String(), maxLen)
+}
+
+// Unique returns base, or base with the lowest "-N" suffix (N >= 2) that is
+// not used as a slug in any of tables. An empty base becomes Fallback. The
+// check is advisory: callers still rely on the unique constraint to catch a
+// concurrent insert of the same slug.
+func Unique(ctx context.Context, q Querier, base string, maxLen int, tables ...string) (string, error) {
+	if base == "" {
+		base = Fallback
+	}
+	base = truncate(base, maxLen)
+	root := base
+	if maxLen > 0 {
+		root = truncate(base, maxLen-suffixRoom)
+	}
+
+	var union []string
+	for _, table := range tables {
+		union = append(union, "SELECT slug FROM "+table+" WHERE slug = $1 OR slug LIKE $2")
+	}
+	rows, err := q.QueryContext(ctx, strings.Join(union, " UNION ALL "), base, escapeLike(root)+"-%")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	taken := map[string]bool{}
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return "", err
+		}
+		taken[s] = true
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	if !taken[base] {
+		return base, nil
+	}
+	for n := 2; ; n++ {
+		if candidate := fmt.Sprintf("%s-%d", root, n); !taken[candidate] {
+			return candidate, nil
+		}
+	}
+}
+
+func truncate(s string, maxLen int) string {
+	if maxLen <= 0 || len(s) <= maxLen {
+		return s
+	}
+	return strings.TrimRight(s[:maxLen], "-")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,119 @@
+package slug
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"course title", "Lập trình Go cơ bản", 0, "lap-trinh-go-co-ban"},
+		{"d with stroke", "Đường đi đến Đà Lạt", 0, "duong-di-den-da-lat"},
+		{"horned vowels", "ƯU ĐÃI thương mại, cờ vua", 0, "uu-dai-thuong-mai-co-vua"},
+		{"tone marks", "Hỏi Ngã Sắc Huyền Nặng", 0, "hoi-nga-sac-huyen-nang"},
+		{"punctuation collapses", "  --Go & Rust!! 2024--  ", 0, "go-rust-2024"},
+		{"non-Latin dropped", "日本語 Go", 0, "go"},
+		{"nothing left", "!!! ???", 0, ""},
+		{"truncated at a word", "Lập trình Go", 9, "lap-trinh"},
+		{"no trailing hyphen", "Lập trình Go", 10, "lap-trinh"},
+		{"truncated mid word", "Lập trình Go", 6, "lap-tr"},
+		{"within limit", "Go", 50, "go"},
+	}
+	for _, tt := range tests {
+		if got := Make(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("%s: Make(%q, %d) = %q, want %q", tt.name, tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		maxLen   int
+		taken    []string
+		wantArgs [2]string
+		want     string
+	}{
+		{"free", "lap-trinh-go", 50, nil, [2]string{"lap-trinh-go", "lap-trinh-go-%"}, "lap-trinh-go"},
+		{"taken", "lap-trinh-go", 50, []string{"lap-trinh-go"}, [2]string{"lap-trinh-go", "lap-trinh-go-%"}, "lap-trinh-go-2"},
+		{"next free suffix", "go", 50, []string{"go", "go-2", "go-3"}, [2]string{"go", "go-%"}, "go-4"},
+		{"fills a gap", "go", 50, []string{"go", "go-3"}, [2]string{"go", "go-%"}, "go-2"},
+		{"suffixed slug only", "go", 50, []string{"go-2"}, [2]string{"go", "go-%"}, "go"},
+		{"empty base", "", 50, nil, [2]string{Fallback, Fallback + "-%"}, Fallback},
+		{"suffix fits the limit", "khoa-hoc-lap-trinh", 12, []string{"khoa-hoc-lap"},
+			[2]string{"khoa-hoc-lap", "khoa-ho-%"}, "khoa-ho-2"},
+		{"LIKE wildcards escaped", "100%_go", 0, nil, [2]string{"100%_go", `100\%\_go-%`}, "100%_go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			rows := sqlmock.NewRows([]string{"slug"})
+			for _, s := range tt.taken {
+				rows.AddRow(s)
+			}
+			mock.ExpectQuery(`SELECT slug FROM tags WHERE slug = \$1 OR slug LIKE \$2`).
+				WithArgs(tt.wantArgs[0], tt.wantArgs[1]).WillReturnRows(rows)
+
+			got, err := Unique(context.Background(), db, tt.base, tt.maxLen, "tags")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Unique = %q, want %q", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// A slug is taken if any of the tables uses it, so old course slugs that
+// still redirect are not handed out again.
+func TestUniqueAcrossTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT slug FROM courses WHERE .+ UNION ALL SELECT slug FROM course_slug_history WHERE`).
+		WithArgs("go", "go-%").
+		WillReturnRows(sqlmock.NewRows([]string{"slug"}).AddRow("go-2").AddRow("go"))
+
+	got, err := Unique(context.Background(), db, "go", 200, "courses", "course_slug_history")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "go-3" {
+		t.Errorf("Unique = %q, want go-3", got)
+	}
+}
+
+func TestUniqueQueryError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	boom := errors.New("connection reset")
+	mock.ExpectQuery(`SELECT slug FROM tags`).WillReturnError(boom)
+	if _, err := Unique(context.Background(), db, "go", 50, "tags"); !errors.Is(err, boom) {
+		t.Errorf("err = %v, want %v", err, boom)
+	}
+}
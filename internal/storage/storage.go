@@ -0,0 +1,49 @@
+// Package storage stores generated files (invoices, exports) behind the
+// driver selected in StorageConfig: the local filesystem or an S3
+// compatible bucket. Keys are slash-separated paths such as
+// "invoices/2026/TTC-2026-000001.pdf".
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"internal/config"
+)
+
+// ErrNotFound is returned by Open when no object exists under the key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage is implemented by every driver.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any existing
+	// object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the object stored under key; the caller closes it.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// New returns the driver configured in cfg.
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocal(cfg.LocalPath), nil
+	case "s3":
+		return NewS3(cfg), nil
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}
+
+// cleanKey rejects keys that would escape the storage root.
+func cleanKey(key string) (string, error) {
+	clean := path.Clean("/" + key)[1:]
+	if clean == "" || clean != strings.TrimPrefix(key, "/") {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return clean, nil
+}
@@ -0,0 +1,287 @@
+// Package subscription runs the billing cycle of subscription plans. A
+// subscription is paid one period at a time through subscription_payments
+// (migration 018): Process creates the renewal payment shortly before a
+// period ends, moves unpaid subscriptions to past_due with a grace period,
+// ends subscriptions cancelled at period end and revokes access when the
+// grace period runs out. Settle records the payment provider's verdict on a
+// payment. Course access itself is read through internal/entitlement.
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Subscription statuses.
+const (
+	StatusIncomplete = "incomplete"
+	StatusActive     = "active"
+	StatusPastDue    = "past_due"
+	StatusCancelled  = "cancelled"
+	StatusExpired    = "expired"
+)
+
+// Payment statuses. Paid, failed and void are final.
+const (
+	PaymentPending = "pending"
+	PaymentPaid    = "paid"
+	PaymentFailed  = "failed"
+	PaymentVoid    = "void"
+)
+
+// Plan billing intervals and scopes.
+const (
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+
+	ScopeAll        = "all"
+	ScopeCategories = "categories"
+	ScopeCourses    = "courses"
+)
+
+var (
+	ErrNotFound          = errors.New("subscription: payment not found")
+	ErrInvalidTransition = errors.New("subscription: payment is not pending")
+)
+
+// runLockKey keeps concurrent runs from billing the same period twice.
+const runLockKey = 0x7375627363 // "subsc"
+
+// Result counts what one Process run changed.
+type Result struct {
+	Renewals  int // renewal payments created
+	PastDue   int
+	Cancelled int // ended at period end after the user cancelled
+	Expired   int // revoked after the grace period, or never paid
+}
+
+// Process runs one billing pass at now. Renewal payments are created lead
+// before the period ends and subscriptions stay usable until grace after
+// it. It reports false without doing anything when another run holds the
+// lock.
+func Process(ctx context.Context, db *sql.DB, now time.Time, lead, grace time.Duration) (Result, bool, error) {
+	var res Result
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, false, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_try_advisory_xact_lock($1)", runLockKey).Scan(&locked); err != nil {
+		return res, false, err
+	}
+	if !locked {
+		return res, false, nil
+	}
+
+	// Cancelled subscriptions end with the period they paid for
+	err = tx.QueryRowContext(ctx, `
+		WITH ended AS (
+			UPDATE subscriptions
+			SET status = 'cancelled', ended_at = current_period_end, grace_until = NULL, updated_at = $1
+			WHERE status IN ('active', 'past_due') AND cancel_at_period_end AND current_period_end <= $1
+			RETURNING id
+		), voided AS (
+			UPDATE subscription_payments p SET status = 'void', updated_at = $1
+			FROM ended WHERE p.subscription_id = ended.id AND p.status = 'pending'
+		)
+		SELECT COUNT(*) FROM ended
+	`, now).Scan(&res.Cancelled)
+	if err != nil {
+		return res, true, err
+	}
+
+	// Bill the next period at the plan's current price. Access is extended
+	// to the end of the grace period right away, so it does not depend on
+	// this job running on time
+	err = tx.QueryRowContext(ctx, `
+		WITH due AS (
+			SELECT s.id, s.current_period_end AS starts_at, sp.billing_interval, sp.interval_count, sp.price, sp.currency
+			FROM subscriptions s
+			JOIN subscription_plans sp ON sp.id = s.plan_id
+			WHERE s.status = 'active' AND NOT s.cancel_at_period_end AND s.current_period_end <= $2
+			FOR UPDATE OF s
+		), created AS (
+			INSERT INTO subscription_payments (subscription_id, period_start, period_end, amount, currency)
+			SELECT id, starts_at, subscription_period_end(starts_at, billing_interval, interval_count), price, currency
+			FROM due
+			ON CONFLICT (subscription_id, period_start) WHERE status <> 'void' DO NOTHING
+			RETURNING subscription_id
+		), graced AS (
+			UPDATE subscriptions s
+			SET grace_until = s.current_period_end + make_interval(secs => $3), updated_at = $1
+			FROM created WHERE s.id = created.subscription_id
+		)
+		SELECT COUNT(*) FROM created
+	`, now, now.Add(lead), grace.Seconds()).Scan(&res.Renewals)
+	if err != nil {
+		return res, true, err
+	}
+
+	// Periods that ended unpaid; the learner keeps access until grace_until
+	err = tx.QueryRowContext(ctx, `
+		WITH lapsed AS (
+			UPDATE subscriptions
+			SET status = 'past_due',
+			    grace_until = COALESCE(grace_until, current_period_end + make_interval(secs => $2)),
+			    updated_at = $1
+			WHERE status = 'active' AND NOT cancel_at_period_end AND current_period_end <= $1
+			RETURNING id, user_id, grace_until
+		), notified AS (
+			INSERT INTO notifications (user_id, title, message, type, related_id)
+			SELECT user_id, 'Thuê bao chưa được gia hạn',
+			       'Kỳ thanh toán mới của thuê bao chưa được thanh toán. Bạn vẫn học được đến '
+			           || to_char(grace_until AT TIME ZONE 'Asia/Ho_Chi_Minh', 'DD/MM/YYYY HH24:MI') || '.',
+			       'subscription_past_due', id
+			FROM lapsed
+		)
+		SELECT COUNT(*) FROM lapsed
+	`, now, grace.Seconds()).Scan(&res.PastDue)
+	if err != nil {
+		return res, true, err
+	}
+
+	// Revoke access after the grace period, and give up on first payments
+	// that never arrived
+	err = tx.QueryRowContext(ctx, `
+		WITH ended AS (
+			UPDATE subscriptions
+			SET status = 'expired', ended_at = $1, updated_at = $1
+			WHERE (status = 'past_due' AND COALESCE(grace_until, current_period_end) <= $1)
+			   OR (status = 'incomplete' AND created_at <= $2)
+			RETURNING id, user_id, current_period_end IS NOT NULL AS was_paid
+		), voided AS (
+			UPDATE subscription_payments p SET status = 'void', updated_at = $1
+			FROM ended WHERE p.subscription_id = ended.id AND p.status = 'pending'
+		), notified AS (
+			INSERT INTO notifications (user_id, title, message, type, related_id)
+			SELECT user_id, 'Thuê bao đã hết hạn',
+			       'Thuê bao đã hết hạn do không được gia hạn; bạn không còn truy cập các khóa học của gói.',
+			       'subscription_expired', id
+			FROM ended WHERE was_paid
+		)
+		SELECT COUNT(*) FROM ended
+	`, now, now.Add(-grace)).Scan(&res.Expired)
+	if err != nil {
+		return res, true, err
+	}
+
+	return res, true, tx.Commit()
+}
+
+// Run calls Process every interval until ctx is cancelled. Failures are
+// logged and retried on the next tick.
+func Run(ctx context.Context, db *sql.DB, interval, lead, grace time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, ran, err := Process(ctx, db, time.Now(), lead, grace)
+			switch {
+			case err != nil && ctx.Err() == nil:
+				logrus.WithError(err).Error("Failed to process subscriptions")
+			case ran:
+				logrus.WithFields(logrus.Fields{
+					"renewals":  res.Renewals,
+					"past_due":  res.PastDue,
+					"cancelled": res.Cancelled,
+					"expired":   res.Expired,
+				}).Debug("Processed subscriptions")
+			}
+		}
+	}
+}
+
+// Settle records the outcome of a pending payment. A paid payment starts
+// or extends the subscription's period: a first payment starts the period
+// at now, a renewal continues from the previous period even when it is paid
+// during the grace period. A failed first payment ends the subscription; a
+// failed renewal leaves it to lapse when the grace period ends. It returns
+// the ID of the payment's subscription.
+func Settle(ctx context.Context, db *sql.DB, paymentID, status, transactionID string, now time.Time) (string, error) {
+	if status != PaymentPaid && status != PaymentFailed {
+		return "", ErrInvalidTransition
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	var subscriptionID, paymentStatus, subStatus string
+	err = tx.QueryRowContext(ctx, `
+		SELECT p.subscription_id, p.status, s.status
+		FROM subscription_payments p
+		JOIN subscriptions s ON s.id = p.subscription_id
+		WHERE p.id = $1
+		FOR UPDATE
+	`, paymentID).Scan(&subscriptionID, &paymentStatus, &subStatus)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if paymentStatus != PaymentPending {
+		return "", ErrInvalidTransition
+	}
+
+	txID := sql.NullString{String: transactionID, Valid: transactionID != ""}
+
+	if status == PaymentFailed {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE subscription_payments SET status = 'failed', transaction_id = $2, updated_at = $3 WHERE id = $1
+		`, paymentID, txID, now); err != nil {
+			return "", err
+		}
+		if subStatus == StatusIncomplete {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE subscriptions SET status = 'expired', ended_at = $2, updated_at = $2 WHERE id = $1
+			`, subscriptionID, now); err != nil {
+				return "", err
+			}
+		}
+		return subscriptionID, tx.Commit()
+	}
+
+	// First payments are billed from the moment they are paid
+	if subStatus == StatusIncomplete {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE subscription_payments p
+			SET period_start = $2, period_end = subscription_period_end($2, sp.billing_interval, sp.interval_count)
+			FROM subscriptions s JOIN subscription_plans sp ON sp.id = s.plan_id
+			WHERE p.id = $1 AND s.id = p.subscription_id
+		`, paymentID, now)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		WITH paid AS (
+			UPDATE subscription_payments
+			SET status = 'paid', transaction_id = $2, paid_at = $3, updated_at = $3
+			WHERE id = $1
+			RETURNING subscription_id, period_start, period_end
+		)
+		UPDATE subscriptions s
+		SET status = 'active', current_period_start = paid.period_start, current_period_end = paid.period_end,
+		    grace_until = NULL, updated_at = $3
+		FROM paid WHERE s.id = paid.subscription_id
+	`, paymentID, txID, now)
+	if err != nil {
+		return "", err
+	}
+	return subscriptionID, tx.Commit()
+}